@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"github.com/joho/godotenv"
 	"github.com/nikitalystsev/BookSmart/internal/app"
+	"github.com/nikitalystsev/BookSmart/internal/config"
+	"github.com/nikitalystsev/BookSmart/internal/health"
 	"log"
+	"os"
 )
 
 const configsDir = "configs"
@@ -26,7 +30,28 @@ func init() {
 // @name Authorization
 
 func main() {
+	// `app healthcheck` - проверка готовности для healthcheck docker-контейнера
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		cfg, err := config.Init(configsDir, os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = health.Probe(context.Background(), "http://localhost:"+cfg.Port+"/readyz"); err != nil {
+			log.Print(err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	app.Run(configsDir)
+	// `app migrate up|down|status|to N` - миграции схемы БД
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(configsDir, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
+	if err := app.Run(configsDir, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
 }
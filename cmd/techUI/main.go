@@ -5,6 +5,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/nikitalystsev/BookSmart-tech-ui/requesters"
 	"log"
+	"os"
 )
 
 const configDir = "configs"
@@ -16,7 +17,7 @@ func init() {
 }
 
 func main() {
-	cfg, err := config.Init(configDir)
+	cfg, err := config.Init(configDir, os.Args[1:])
 	if err != nil {
 		return
 	}
@@ -15,60 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/auth/refresh": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Метод обновления токенов",
-                "operationId": "refresh",
-                "parameters": [
-                    {
-                        "description": "Токен обновления",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RefreshTokenInputDTO"
-                        }
-                    }
-                ],
+                "summary": "Метод получения открытых ключей для проверки access-токенов",
+                "operationId": "getJWKS",
                 "responses": {
                     "200": {
-                        "description": "Успешное обновление токенов",
-                        "schema": {
-                            "$ref": "#/definitions/dto.RefreshTokenOutputDTO"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный запрос",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Читателя не существует",
+                        "description": "Успешное получение ключей",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.JWKSOutputDTO"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/sign-in": {
+        "/api/v1/auth/logout": {
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -78,25 +51,22 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Метод аутентификации пользователя",
-                "operationId": "signIn",
+                "summary": "Метод выхода из аккаунта на текущем устройстве",
+                "operationId": "logout",
                 "parameters": [
                     {
-                        "description": "DTO c номером телефона и паролем пользователя",
+                        "description": "Refresh-токен текущей сессии",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SignInInputDTO"
+                            "$ref": "#/definitions/dto.LogoutInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успешный вход пользователя",
-                        "schema": {
-                            "$ref": "#/definitions/dto.SignInOutputDTO"
-                        }
+                    "204": {
+                        "description": "Успешный выход"
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -104,14 +74,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Читателя не существует",
+                    "401": {
+                        "description": "Неавторизованный пользователь",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Неверный логин или пароль",
+                    "404": {
+                        "description": "Сессия не найдена",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -125,7 +95,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/sign-up": {
+        "/api/v1/auth/password-reset": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -136,22 +106,22 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Метод регистрации пользователя",
-                "operationId": "signUp",
+                "summary": "Метод запроса кода для сброса пароля",
+                "operationId": "requestPasswordReset",
                 "parameters": [
                     {
-                        "description": "DTO c данными пользователя",
+                        "description": "Номер телефона читателя",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SignUpInputDTO"
+                            "$ref": "#/definitions/dto.PasswordResetRequestInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешное создание пользователя"
+                    "202": {
+                        "description": "Код отправлен, если номер зарегистрирован"
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -159,8 +129,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Пользователь уже существует",
+                    "429": {
+                        "description": "Код запрошен слишком часто",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -174,8 +144,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/books": {
-            "get": {
+        "/api/v1/auth/password-reset/confirm": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -183,91 +153,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "book"
+                    "auth"
                 ],
-                "summary": "Метод получения книг по параметрам",
-                "operationId": "getPageBooks",
+                "summary": "Метод установки нового пароля по одноразовому коду",
+                "operationId": "confirmPasswordReset",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Название книги",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Автор книги",
-                        "name": "author",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Издательство книги",
-                        "name": "publisher",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Редкость книги",
-                        "name": "rarity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Жанр книги",
-                        "name": "genre",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Язык книги",
-                        "name": "language",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Количество копий",
-                        "name": "copies_number",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Год издания",
-                        "name": "publishing_year",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Возрастное ограничение",
-                        "name": "age_limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Номер страницы для пагинации",
-                        "name": "page_number",
-                        "in": "query",
-                        "required": true
+                        "description": "Номер телефона, код и новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordResetConfirmInputDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Список книг",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.JSONBookModel"
-                            }
-                        }
+                    "204": {
+                        "description": "Успешный сброс пароля"
                     },
                     "400": {
-                        "description": "Неверный запрос",
+                        "description": "Неверный запрос или неверный код",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Книги не найдены",
+                    "429": {
+                        "description": "Превышено число попыток ввода кода",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -281,8 +193,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/books/{id}": {
-            "get": {
+        "/api/v1/auth/refresh": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -290,24 +202,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "book"
+                    "auth"
                 ],
-                "summary": "Метод получения книги по идентификатору",
-                "operationId": "getBookByID",
+                "summary": "Метод обновления токенов",
+                "operationId": "refresh",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Идентификатор книги",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Токен обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenInputDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение книги по идентификатору",
+                        "description": "Успешное обновление токенов",
                         "schema": {
-                            "$ref": "#/definitions/models.JSONBookModel"
+                            "$ref": "#/definitions/dto.RefreshTokenOutputDTO"
                         }
                     },
                     "400": {
@@ -317,7 +231,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Книги нет",
+                        "description": "Читателя не существует",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -331,8 +245,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/books/{id}/ratings": {
+        "/api/v1/auth/sessions": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -340,44 +259,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "book_ratings"
-                ],
-                "summary": "Метод получения рейтингов книги",
-                "operationId": "getRatingsByBookID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Идентификатор книги",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Номер страницы",
-                        "name": "page_number",
-                        "in": "query",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Метод получения активных сессий читателя",
+                "operationId": "getSessions",
                 "responses": {
                     "200": {
-                        "description": "Успешное получение отзывов на книгу",
+                        "description": "Успешное получение сессий",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.RatingOutputDTO"
+                                "$ref": "#/definitions/dto.SessionOutputDTO"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Неверный запрос",
+                    "401": {
+                        "description": "Неавторизованный пользователь",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "У книги нет отзывов",
+                        "description": "Сессии не найдены",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -389,8 +292,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/auth/sessions/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -403,31 +308,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "book_ratings"
+                    "auth"
                 ],
-                "summary": "Метод добавления отзыва на книгу",
-                "operationId": "addNewRating",
+                "summary": "Метод завершения сессии на другом устройстве",
+                "operationId": "revokeSession",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор книги",
+                        "description": "Идентификатор сессии",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "DTO с данными отзыва",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RatingInputDTO"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешное добавление отзыва"
+                    "204": {
+                        "description": "Успешное завершение сессии"
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -441,20 +337,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Доступ запрещен",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
                     "404": {
-                        "description": "Пользователь никогда не бронировал книгу",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Пользователь уже оценил книгу",
+                        "description": "Сессия не найдена",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -468,8 +352,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/books/{id}/ratings/avg": {
-            "get": {
+        "/api/v1/auth/sign-in": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -477,24 +361,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "book_ratings"
+                    "auth"
                 ],
-                "summary": "Метод получения среднего рейтинга книги",
-                "operationId": "getAvgRatingByBookID",
+                "summary": "Метод аутентификации пользователя",
+                "operationId": "signIn",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Идентификатор книги",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "DTO c номером телефона и паролем пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SignInInputDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение среднего рейтинга книги",
+                        "description": "Успешный вход пользователя",
                         "schema": {
-                            "$ref": "#/definitions/dto.AvgRatingOutputDTO"
+                            "$ref": "#/definitions/dto.SignInOutputDTO"
                         }
                     },
                     "400": {
@@ -504,7 +390,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "У книги нет отзывов",
+                        "description": "Читателя не существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Неверный логин или пароль",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -518,13 +410,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/readers/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+        "/api/v1/auth/sign-in/2fa": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -532,40 +419,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader"
+                    "auth"
                 ],
-                "summary": "Метод получения читателя по идентификатору",
-                "operationId": "getReaderByPhoneNumber",
+                "summary": "Метод подтверждения входа кодом второго фактора",
+                "operationId": "verifyTwoFactorChallenge",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Идентификатор читателя",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Challenge-токен и код из приложения или код восстановления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorChallengeInputDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение читателя",
+                        "description": "Успешный вход; при первой настройке 2FA ответ содержит recovery_codes",
                         "schema": {
-                            "$ref": "#/definitions/models.JSONReaderModel"
+                            "$ref": "#/definitions/dto.TwoFactorSignInOutputDTO"
                         }
                     },
-                    "401": {
-                        "description": "Неавторизованный пользователь",
+                    "400": {
+                        "description": "Неверный запрос",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Доступ запрещен",
+                    "401": {
+                        "description": "Неверный код или истекший challenge-токен",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Читатель не найден",
+                    "429": {
+                        "description": "Превышено число попыток или читатель временно заблокирован",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -579,13 +468,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/readers/{id}/favorite_books": {
+        "/api/v1/auth/sign-in/2fa/enroll": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -593,31 +477,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader"
+                    "auth"
                 ],
-                "summary": "Метод добавления книги в избранное",
-                "operationId": "addBookToFavorites",
+                "summary": "Метод настройки 2FA при входе, если она обязательна для роли",
+                "operationId": "enrollTwoFactorChallenge",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Идентификатор читателя",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Идентификатор книги",
+                        "description": "Challenge-токен",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.FavoriteBookInputDTO"
+                            "$ref": "#/definitions/dto.TwoFactorChallengeEnrollInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешное добавление книги в избранное"
+                    "200": {
+                        "description": "Секрет и otpauth-ссылка для приложения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorEnrollmentOutputDTO"
+                        }
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -626,25 +506,62 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Неавторизованный пользователь",
+                        "description": "Истекший challenge-токен",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Доступ запрещен",
+                    "409": {
+                        "description": "2FA уже настроена",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Читатель не найден",
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/sign-up": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Метод регистрации пользователя",
+                "operationId": "signUp",
+                "parameters": [
+                    {
+                        "description": "DTO c данными пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SignUpInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное создание пользователя"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": " Книга уже добавлена в избранное",
+                        "description": "Пользователь уже существует",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -658,13 +575,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/readers/{id}/lib_cards": {
+        "/api/v1/books": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -672,49 +584,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_lib_card"
+                    "book"
                 ],
-                "summary": "Метод получения читательского билета",
-                "operationId": "getLibCardByReaderID",
+                "summary": "Метод получения книг по параметрам",
+                "operationId": "getPageBooks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Название книги",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор книги",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Издательство книги",
+                        "name": "publisher",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Редкость книги",
+                        "name": "rarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Жанр книги",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык книги",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество копий",
+                        "name": "copies_number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год издания",
+                        "name": "publishing_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Возрастное ограничение",
+                        "name": "age_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы для пагинации",
+                        "name": "page_number",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение читательского билета",
+                        "description": "Список книг",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.JSONLibCardModel"
+                                "$ref": "#/definitions/models.JSONBookModel"
                             }
                         }
                     },
                     "400": {
-                        "description": "Некорректный идентификатор пользователя",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Неавторизованный пользователь",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Доступ запрещен",
+                        "description": "Неверный запрос",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Читательский билет не найден",
+                        "description": "Книги не найдены",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -726,13 +680,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+            }
+        },
+        "/api/v1/books/{id}": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -740,14 +691,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_lib_card"
+                    "book"
                 ],
-                "summary": "Метод обновления читательского билета",
-                "operationId": "updateLibCard",
+                "summary": "Метод получения книги по идентификатору",
+                "operationId": "getBookByID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
+                        "description": "Идентификатор книги",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -755,7 +706,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное обновление читательского билета"
+                        "description": "Успешное получение книги по идентификатору",
+                        "schema": {
+                            "$ref": "#/definitions/models.JSONBookModel"
+                        }
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -763,26 +717,68 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Неавторизованный пользователь",
+                    "404": {
+                        "description": "Книги нет",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Доступ запрещен",
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Читательского билета не существует",
+                    }
+                }
+            }
+        },
+        "/api/v1/books/{id}/ratings": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "book_ratings"
+                ],
+                "summary": "Метод получения рейтингов книги",
+                "operationId": "getRatingsByBookID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор книги",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Номер страницы",
+                        "name": "page_number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение отзывов на книгу",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RatingOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Читательский билет актуален",
+                    "404": {
+                        "description": "У книги нет отзывов",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -808,22 +804,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_lib_card"
+                    "book_ratings"
                 ],
-                "summary": "Метод создания читательского билета",
-                "operationId": "createLibCard",
+                "summary": "Метод добавления отзыва на книгу",
+                "operationId": "addNewRating",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
+                        "description": "Идентификатор книги",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "DTO с данными отзыва",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RatingInputDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Успешное создание читательского билета"
+                        "description": "Успешное добавление отзыва"
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -843,8 +848,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Пользователь никогда не бронировал книгу",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "Читательский билет уже существует",
+                        "description": "Пользователь уже оценил книгу",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -858,13 +869,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/readers/{id}/reservations": {
+        "/api/v1/books/{id}/ratings/avg": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -872,34 +878,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_reservations"
+                    "book_ratings"
                 ],
-                "summary": "Метод получения всех броней читателя",
-                "operationId": "getReservationsByReaderID",
+                "summary": "Метод получения среднего рейтинга книги",
+                "operationId": "getAvgRatingByBookID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
+                        "description": "Идентификатор книги",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Номер страницы",
-                        "name": "page_number",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение броней",
+                        "description": "Успешное получение среднего рейтинга книги",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ReservationOutputDTO"
-                            }
+                            "$ref": "#/definitions/dto.AvgRatingOutputDTO"
                         }
                     },
                     "400": {
@@ -908,20 +904,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Неавторизованный пользователь",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Доступ запрещен",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
                     "404": {
-                        "description": "Нет броней",
+                        "description": "У книги нет отзывов",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -933,7 +917,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/books/{id}/restore": {
             "post": {
                 "security": [
                     {
@@ -947,31 +933,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_reservations"
+                    "book"
                 ],
-                "summary": "Метод бронирования книги",
-                "operationId": "reserveBook",
+                "summary": "Метод восстановления удаленной книги",
+                "operationId": "restoreBook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
+                        "description": "Идентификатор книги",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Идентификатор книги",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ReservationInputDTO"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешное бронирование книги"
+                    "204": {
+                        "description": "Успешное восстановление книги"
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -986,19 +963,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "доступ запрещен",
+                        "description": "Доступ запрещен",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Нет читательского билета или книги",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Бронирование невозможно из-за нарушения некоторых условий",
+                        "description": "Удаленная книга не найдена",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -1012,13 +983,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/readers/{id}/reservations/{reservation_id}": {
+        "/api/v1/books/{id}/similar": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1026,31 +992,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_reservations"
+                    "book"
                 ],
-                "summary": "Метод получения брони читателя по идентификатору",
-                "operationId": "getReservationByID",
+                "summary": "Метод получения похожих книг",
+                "operationId": "getSimilarBooks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор читателя",
+                        "description": "Идентификатор книги",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Идентификатор брони",
-                        "name": "reservation_id",
-                        "in": "path",
+                        "description": "Номер страницы",
+                        "name": "page_number",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение брони",
+                        "description": "Успешное получение похожих книг",
                         "schema": {
-                            "$ref": "#/definitions/dto.ReservationOutputDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SimilarBookOutputDTO"
+                            }
                         }
                     },
                     "400": {
@@ -1059,6 +1028,55 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Похожие книги не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader"
+                ],
+                "summary": "Метод получения читателя по идентификатору",
+                "operationId": "getReaderByPhoneNumber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение читателя",
+                        "schema": {
+                            "$ref": "#/definitions/models.JSONReaderModel"
+                        }
+                    },
                     "401": {
                         "description": "Неавторизованный пользователь",
                         "schema": {
@@ -1072,7 +1090,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": " Бронь не найдена",
+                        "description": "Читатель не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -1098,10 +1116,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reader_reservations"
+                    "reader_account"
                 ],
-                "summary": "Метод обновления брони читателя по идентификатору",
-                "operationId": "updateReservation",
+                "summary": "Метод изменения ФИО читателя",
+                "operationId": "updateReaderFio",
                 "parameters": [
                     {
                         "type": "string",
@@ -1111,25 +1129,21 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Идентификатор брони",
-                        "name": "reservation_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Срок продления брони",
+                        "description": "Новое ФИО",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ReservationExtentionPeriodDaysInputDTO"
+                            "$ref": "#/definitions/dto.ReaderFioInputDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное продление брони"
+                        "description": "Успешное изменение ФИО",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReaderProfileOutputDTO"
+                        }
                     },
                     "400": {
                         "description": "Неверный запрос",
@@ -1143,14 +1157,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Бронь не найдена",
+                    "403": {
+                        "description": "Доступ запрещен",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Нарушение каких либо условий для успешного продления брони",
+                    "404": {
+                        "description": "Читатель не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -1162,30 +1176,2311 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.AvgRatingOutputDTO": {
-            "type": "object",
-            "properties": {
-                "avg_rating": {
-                    "type": "number"
-                }
-            }
-        },
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод удаления аккаунта читателя",
+                "operationId": "deleteReaderAccount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текущий пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReaderPasswordConfirmInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Успешное удаление аккаунта"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен или неверный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У читателя есть невозвращенные книги",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/2fa": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод начала настройки 2FA",
+                "operationId": "enrollTwoFactor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Секрет и otpauth-ссылка для приложения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorEnrollmentOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод отключения 2FA",
+                "operationId": "disableTwoFactor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Успешное отключение 2FA"
+                    },
+                    "400": {
+                        "description": "Неверный запрос или 2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен или 2FA обязательна для роли",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Читатель временно заблокирован из-за неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод включения 2FA кодом из приложения",
+                "operationId": "confirmTwoFactor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Код из приложения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Коды восстановления",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodesOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или настройка не начата",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Читатель временно заблокирован из-за неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/2fa/recovery_codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод выпуска новых кодов восстановления",
+                "operationId": "regenerateRecoveryCodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новые коды восстановления, старые больше не действуют",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodesOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или 2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Читатель временно заблокирован из-за неверных кодов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/favorite_books": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader"
+                ],
+                "summary": "Метод добавления книги в избранное",
+                "operationId": "addBookToFavorites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Идентификатор книги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavoriteBookInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное добавление книги в избранное"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": " Книга уже добавлена в избранное",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_favorite_books"
+                ],
+                "summary": "Метод получения избранных книг читателя",
+                "operationId": "getFavoriteBooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Номер страницы",
+                        "name": "page_number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение избранных книг",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BookOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Избранные книги не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/favorite_books/{book_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_favorite_books"
+                ],
+                "summary": "Метод удаления книги из избранного",
+                "operationId": "deleteFavoriteBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор книги",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление книги из избранного"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книги нет в избранном",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/lib_cards": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_lib_card"
+                ],
+                "summary": "Метод получения читательского билета",
+                "operationId": "getLibCardByReaderID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение читательского билета",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JSONLibCardModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читательский билет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_lib_card"
+                ],
+                "summary": "Метод обновления читательского билета",
+                "operationId": "updateLibCard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное обновление читательского билета"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читательского билета не существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Читательский билет актуален",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_lib_card"
+                ],
+                "summary": "Метод создания читательского билета",
+                "operationId": "createLibCard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное создание читательского билета"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Читательский билет уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод изменения пароля читателя",
+                "operationId": "changeReaderPassword",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текущий и новый пароли",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReaderPasswordInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Успешное изменение пароля"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен или неверный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/phone_number": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод изменения номера телефона читателя",
+                "operationId": "changeReaderPhoneNumber",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текущий пароль и новый номер телефона",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReaderPhoneNumberInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное изменение номера телефона",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReaderProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен или неверный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Читатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Номер телефона уже занят",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reading_lists": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод получения списков чтения читателя",
+                "operationId": "getReadingLists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение списков чтения",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReadingListOutputDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Списки чтения не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод создания списка чтения",
+                "operationId": "createReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Название списка чтения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное создание списка чтения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Список чтения с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reading_lists/{list_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод получения списка чтения по идентификатору",
+                "operationId": "getReadingListByID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение списка чтения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод переименования списка чтения",
+                "operationId": "renameReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название списка чтения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное переименование списка чтения"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Список чтения с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод удаления списка чтения",
+                "operationId": "deleteReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление списка чтения"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reading_lists/{list_id}/books": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод добавления книги в список чтения",
+                "operationId": "addBookToReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Идентификатор книги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListBookInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное добавление книги в список чтения"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения или книга не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Книга уже есть в списке чтения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод изменения порядка книг в списке чтения",
+                "operationId": "reorderReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Идентификаторы книг в новом порядке",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadingListOrderInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное изменение порядка книг"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reading_lists/{list_id}/books/{book_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод удаления книги из списка чтения",
+                "operationId": "removeBookFromReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор книги",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление книги из списка чтения"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reading_lists/{list_id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод открытия публичного доступа к списку чтения",
+                "operationId": "shareReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное открытие доступа к списку чтения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShareTokenOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reading_lists"
+                ],
+                "summary": "Метод закрытия публичного доступа к списку чтения",
+                "operationId": "unshareReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор списка чтения",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное закрытие доступа к списку чтения"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reservations"
+                ],
+                "summary": "Метод получения всех броней читателя",
+                "operationId": "getReservationsByReaderID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Номер страницы",
+                        "name": "page_number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение броней",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReservationOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Нет броней",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reservations"
+                ],
+                "summary": "Метод бронирования книги",
+                "operationId": "reserveBook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Идентификатор книги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReservationInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное бронирование книги"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Нет читательского билета или книги",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бронирование невозможно из-за нарушения некоторых условий",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/reservations/{reservation_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reservations"
+                ],
+                "summary": "Метод получения брони читателя по идентификатору",
+                "operationId": "getReservationByID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор брони",
+                        "name": "reservation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение брони",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReservationOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": " Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_reservations"
+                ],
+                "summary": "Метод обновления брони читателя по идентификатору",
+                "operationId": "updateReservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор брони",
+                        "name": "reservation_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Срок продления брони",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReservationExtentionPeriodDaysInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное продление брони"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нарушение каких либо условий для успешного продления брони",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/readers/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reader_account"
+                ],
+                "summary": "Метод восстановления удаленного аккаунта читателя",
+                "operationId": "restoreReaderAccount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор читателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Успешное восстановление аккаунта"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Удаленный читатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reading_lists/shared/{share_token}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reading_lists"
+                ],
+                "summary": "Метод получения списка чтения по публичной ссылке",
+                "operationId": "getSharedReadingList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен публичной ссылки",
+                        "name": "share_token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение списка чтения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SharedReadingListOutputDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Список чтения не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Метод проверки, что процесс приложения жив",
+                "operationId": "liveness",
+                "responses": {
+                    "200": {
+                        "description": "Приложение работает",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LivenessOutputDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Метод проверки готовности экземпляра принимать запросы",
+                "operationId": "readiness",
+                "responses": {
+                    "200": {
+                        "description": "Все зависимости доступны",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadinessOutputDTO"
+                        }
+                    },
+                    "503": {
+                        "description": "Недоступна хотя бы одна зависимость",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadinessOutputDTO"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AvgRatingOutputDTO": {
+            "type": "object",
+            "properties": {
+                "avg_rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.BookOutputDTO": {
+            "type": "object",
+            "properties": {
+                "age_limit": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "copies_number": {
+                    "type": "integer"
+                },
+                "genre": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "publisher": {
+                    "type": "string"
+                },
+                "publishing_year": {
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DependencyStatusDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.ErrorResponse": {
             "type": "object",
             "properties": {
-                "error_msg": {
+                "error_msg": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FavoriteBookInputDTO": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWKOutputDTO": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWKSOutputDTO": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JWKOutputDTO"
+                    }
+                }
+            }
+        },
+        "dto.LivenessOutputDTO": {
+            "type": "object",
+            "properties": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dto.FavoriteBookInputDTO": {
+        "dto.LogoutInputDTO": {
             "type": "object",
             "properties": {
-                "book_id": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordResetConfirmInputDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordResetRequestInputDTO": {
+            "type": "object",
+            "properties": {
+                "phone_number": {
                     "type": "string"
                 }
             }
@@ -1218,6 +3513,136 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ReaderFioInputDTO": {
+            "type": "object",
+            "properties": {
+                "fio": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReaderPasswordConfirmInputDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReaderPasswordInputDTO": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReaderPhoneNumberInputDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReaderProfileOutputDTO": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "fio": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReadinessOutputDTO": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.DependencyStatusDTO"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReadingListBookInputDTO": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReadingListInputDTO": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReadingListOrderInputDTO": {
+            "type": "object",
+            "properties": {
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ReadingListOutputDTO": {
+            "type": "object",
+            "properties": {
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "share_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RecoveryCodesOutputDTO": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "dto.RefreshTokenInputDTO": {
             "type": "object",
             "properties": {
@@ -1276,6 +3701,48 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SessionOutputDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ShareTokenOutputDTO": {
+            "type": "object",
+            "properties": {
+                "share_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SharedReadingListOutputDTO": {
+            "type": "object",
+            "properties": {
+                "book_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.SignInInputDTO": {
             "type": "object",
             "properties": {
@@ -1321,6 +3788,119 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SimilarBookOutputDTO": {
+            "type": "object",
+            "properties": {
+                "age_limit": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "copies_number": {
+                    "type": "integer"
+                },
+                "genre": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "publisher": {
+                    "type": "string"
+                },
+                "publishing_year": {
+                    "type": "integer"
+                },
+                "rarity": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorChallengeEnrollInputDTO": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorChallengeInputDTO": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorChallengeOutputDTO": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "enrollment_required": {
+                    "type": "boolean"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.TwoFactorCodeInputDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorEnrollmentOutputDTO": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorSignInOutputDTO": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "type": "integer"
+                },
+                "reader_id": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "models.JSONBookModel": {
             "type": "object",
             "properties": {
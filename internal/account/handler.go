@@ -0,0 +1,200 @@
+package account
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	accountService IAccountService
+	tokenManager   auth.ITokenManager
+}
+
+func NewHandler(accountService IAccountService, tokenManager auth.ITokenManager) *Handler {
+	return &Handler{accountService: accountService, tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		account := api.Group("/readers/:id",
+			middleware.Authenticate(h.tokenManager), middleware.RequireSameReader())
+		{
+			account.PATCH("", h.updateFio)
+			account.PUT("/phone_number", h.changePhoneNumber)
+			account.PUT("/password", h.changePassword)
+			account.DELETE("", h.deleteAccount)
+		}
+	}
+}
+
+// @Summary Метод изменения ФИО читателя
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID updateReaderFio
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.ReaderFioInputDTO true "Новое ФИО"
+// @Success 200 {object} dto.ReaderProfileOutputDTO "Успешное изменение ФИО"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Читатель не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id} [patch]
+func (h *Handler) updateFio(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.ReaderFioInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	reader, err := h.accountService.UpdateFio(c.Request.Context(), readerID, input.Fio)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProfileDTO(reader))
+}
+
+// @Summary Метод изменения номера телефона читателя
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID changeReaderPhoneNumber
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.ReaderPhoneNumberInputDTO true "Текущий пароль и новый номер телефона"
+// @Success 200 {object} dto.ReaderProfileOutputDTO "Успешное изменение номера телефона"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен или неверный пароль"
+// @Failure 404 {object} dto.ErrorResponse "Читатель не найден"
+// @Failure 409 {object} dto.ErrorResponse "Номер телефона уже занят"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/phone_number [put]
+func (h *Handler) changePhoneNumber(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.ReaderPhoneNumberInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	reader, err := h.accountService.ChangePhoneNumber(c.Request.Context(), readerID, input.Password, input.PhoneNumber)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProfileDTO(reader))
+}
+
+// @Summary Метод изменения пароля читателя
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID changeReaderPassword
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.ReaderPasswordInputDTO true "Текущий и новый пароли"
+// @Success 204 "Успешное изменение пароля"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен или неверный пароль"
+// @Failure 404 {object} dto.ErrorResponse "Читатель не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/password [put]
+func (h *Handler) changePassword(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.ReaderPasswordInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	err := h.accountService.ChangePassword(c.Request.Context(), readerID, input.Password, input.NewPassword)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Метод удаления аккаунта читателя
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID deleteReaderAccount
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.ReaderPasswordConfirmInputDTO true "Текущий пароль"
+// @Success 204 "Успешное удаление аккаунта"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен или неверный пароль"
+// @Failure 404 {object} dto.ErrorResponse "Читатель не найден"
+// @Failure 409 {object} dto.ErrorResponse "У читателя есть невозвращенные книги"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id} [delete]
+func (h *Handler) deleteAccount(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.ReaderPasswordConfirmInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.accountService.Delete(c.Request.Context(), readerID, input.Password); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, errs.ErrEmptyReaderFio),
+		errors.Is(err, errs.ErrEmptyReaderPhoneNumber),
+		errors.Is(err, errs.ErrInvalidReaderPhoneNumberLen),
+		errors.Is(err, errs.ErrInvalidReaderPhoneNumberFormat),
+		errors.Is(err, errs.ErrInvalidReaderPasswordLen),
+		errors.Is(err, ErrSamePassword):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrWrongPassword):
+		status = http.StatusForbidden
+	case errors.Is(err, errs.ErrReaderDoesNotExists):
+		status = http.StatusNotFound
+	case errors.Is(err, errs.ErrReaderAlreadyExist),
+		errors.Is(err, ErrReaderHasBooksOnLoan):
+		status = http.StatusConflict
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
+
+func toProfileDTO(reader *models.ReaderModel) *dto.ReaderProfileOutputDTO {
+	return &dto.ReaderProfileOutputDTO{
+		ID:          reader.ID,
+		Fio:         reader.Fio,
+		PhoneNumber: reader.PhoneNumber,
+		Age:         reader.Age,
+	}
+}
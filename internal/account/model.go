@@ -0,0 +1,22 @@
+package account
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+const (
+	ReaderPhoneNumberLen = 11
+	ReaderPasswordMinLen = 10
+	AnonymousReaderFio   = "Удаленный читатель"
+)
+
+// AnonymousReaderID - идентификатор служебного читателя, на которого
+// переносятся отзывы и история бронирований удаленных аккаунтов
+var AnonymousReaderID = uuid.Nil
+
+var (
+	ErrWrongPassword        = errors.New("wrong password")
+	ErrReaderHasBooksOnLoan = errors.New("reader has books on loan")
+	ErrSamePassword         = errors.New("new password must differ from the current one")
+)
@@ -0,0 +1,113 @@
+package account
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	readerCollection        = "reader"
+	ratingCollection        = "rating"
+	reservationCollection   = "reservation"
+	libCardCollection       = "lib_card"
+	favoriteBooksCollection = "favorite_books"
+)
+
+type MongoRepo struct {
+	db     *mongo.Database
+	logger *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) IAccountRepo {
+	return &MongoRepo{db: db, logger: logger}
+}
+
+func (r *MongoRepo) Update(ctx context.Context, reader *models.ReaderModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader")
+
+	result, err := r.db.Collection(readerCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(reader.ID)},
+		bson.M{"$set": bson.M{
+			"fio":          reader.Fio,
+			"phone_number": reader.PhoneNumber,
+			"password":     reader.Password,
+		}},
+	)
+	if err != nil {
+		logger.Errorf("error updating reader: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("reader not found")
+		return errs.ErrReaderDoesNotExists
+	}
+
+	logger.Info("successfully updated reader")
+
+	return nil
+}
+
+func (r *MongoRepo) ReplacePasswordHash(ctx context.Context, readerID uuid.UUID, oldHash, newHash string) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("replacing reader password hash")
+
+	result, err := r.db.Collection(readerCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(readerID), "password": oldHash},
+		bson.M{"$set": bson.M{"password": newHash}},
+	)
+	if err != nil {
+		logger.Errorf("error replacing reader password hash: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("reader password changed meanwhile, hash not replaced")
+		return nil
+	}
+
+	logger.Info("successfully replaced reader password hash")
+
+	return nil
+}
+
+func (r *MongoRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader")
+
+	filter := bson.M{"reader_id": toBinary(readerID)}
+	anonymise := bson.M{"$set": bson.M{"reader_id": toBinary(AnonymousReaderID)}}
+
+	for _, name := range []string{ratingCollection, reservationCollection} {
+		if _, err := r.db.Collection(name).UpdateMany(ctx, filter, anonymise); err != nil {
+			logger.Errorf("error anonymising reader data: %v", err)
+			return err
+		}
+	}
+
+	for _, name := range []string{favoriteBooksCollection, libCardCollection} {
+		if _, err := r.db.Collection(name).DeleteMany(ctx, filter); err != nil {
+			logger.Errorf("error deleting reader data: %v", err)
+			return err
+		}
+	}
+
+	if _, err := r.db.Collection(readerCollection).DeleteOne(ctx, bson.M{"_id": toBinary(readerID)}); err != nil {
+		logger.Errorf("error deleting reader: %v", err)
+		return err
+	}
+
+	logger.Info("successfully deleted reader")
+
+	return nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
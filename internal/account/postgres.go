@@ -0,0 +1,114 @@
+package account
+
+import (
+	"context"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) IAccountRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresRepo) Update(ctx context.Context, reader *models.ReaderModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader set fio = $1, phone_number = $2, password = $3 where id = $4`
+
+	result, err := tr.ExecContext(ctx, query, reader.Fio, reader.PhoneNumber, reader.Password, reader.ID)
+	if err != nil {
+		logger.Errorf("error updating reader: %v", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("error updating reader: %v", err)
+		return err
+	}
+	if rows == 0 {
+		logger.Warn("reader not found")
+		return errs.ErrReaderDoesNotExists
+	}
+
+	logger.Info("successfully updated reader")
+
+	return nil
+}
+
+func (r *PostgresRepo) ReplacePasswordHash(ctx context.Context, readerID uuid.UUID, oldHash, newHash string) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("replacing reader password hash")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader set password = $3 where id = $1 and password = $2`
+
+	result, err := tr.ExecContext(ctx, query, readerID, oldHash, newHash)
+	if err != nil {
+		logger.Errorf("error replacing reader password hash: %v", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("error replacing reader password hash: %v", err)
+		return err
+	}
+	if rows == 0 {
+		logger.Warn("reader password changed meanwhile, hash not replaced")
+		return nil
+	}
+
+	logger.Info("successfully replaced reader password hash")
+
+	return nil
+}
+
+func (r *PostgresRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	queries := []string{
+		`update bs.rating set reader_id = $2 where reader_id = $1`,
+		`update bs.reservation set reader_id = $2 where reader_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tr.ExecContext(ctx, query, readerID, AnonymousReaderID); err != nil {
+			logger.Errorf("error anonymising reader data: %v", err)
+			return err
+		}
+	}
+
+	queries = []string{
+		`delete from bs.favorite_books where reader_id = $1`,
+		`delete from bs.lib_card where reader_id = $1`,
+		`delete from bs.reader where id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tr.ExecContext(ctx, query, readerID); err != nil {
+			logger.Errorf("error deleting reader data: %v", err)
+			return err
+		}
+	}
+
+	logger.Info("successfully deleted reader")
+
+	return nil
+}
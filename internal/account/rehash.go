@@ -0,0 +1,125 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/hash"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	signInPath       = "/api/v1/auth/sign-in"
+	maxSignInBodyLen = 1 << 20
+)
+
+type signInInput struct {
+	PhoneNumber string `json:"phone_number"`
+	Password    string `json:"password"`
+}
+
+// statusRecorder запоминает код ответа обработчика входа
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// IPasswordRehasher проверяет пароль и сообщает, что его хэш получен не по
+// текущей схеме
+type IPasswordRehasher interface {
+	hash.IPasswordHasher
+	NeedsRehash(hashedPassword string) bool
+}
+
+// PasswordRehasher пересчитывает хэш пароля читателя по текущей схеме после
+// успешного входа. Хэшер не знает, чей пароль проверяет, поэтому читатель
+// определяется здесь по номеру телефона из запроса, а хэш заменяется только
+// у него и только если пароль не успели сменить
+type PasswordRehasher struct {
+	readerRepo  intfRepo.IReaderRepo
+	accountRepo IAccountRepo
+	hasher      IPasswordRehasher
+	logger      *logrus.Entry
+}
+
+func NewPasswordRehasher(
+	readerRepo intfRepo.IReaderRepo,
+	accountRepo IAccountRepo,
+	hasher IPasswordRehasher,
+	logger *logrus.Entry,
+) *PasswordRehasher {
+	return &PasswordRehasher{readerRepo: readerRepo, accountRepo: accountRepo, hasher: hasher, logger: logger}
+}
+
+// Wrap пересчитывает хэш после ответа обработчика входа. Обработчик находится
+// во внешнем модуле, поэтому обертка ставится вокруг всего роутера. Пересчет
+// идет в том же запросе и не прерывается, если клиент уже отключился
+func (h *PasswordRehasher) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != signInPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSignInBodyLen))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(dto.ErrorResponse{ErrorMsg: "invalid request body"})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status != http.StatusOK {
+			return
+		}
+
+		var input signInInput
+		if err = json.Unmarshal(body, &input); err != nil {
+			return
+		}
+
+		h.rehash(context.WithoutCancel(r.Context()), strings.TrimSpace(input.PhoneNumber), input.Password)
+	})
+}
+
+func (h *PasswordRehasher) rehash(ctx context.Context, phoneNumber, password string) {
+	logger := logging.FromContext(ctx, h.logger)
+
+	reader, err := h.readerRepo.GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		logger.Errorf("error getting reader for password rehash: %v", err)
+		return
+	}
+
+	if !h.hasher.NeedsRehash(reader.Password) || h.hasher.Compare(reader.Password, password) != nil {
+		return
+	}
+
+	newHash, err := h.hasher.Hash(password)
+	if err != nil {
+		logger.Errorf("error rehashing password: %v", err)
+		return
+	}
+
+	if err = h.accountRepo.ReplacePasswordHash(ctx, reader.ID, reader.Password, newHash); err != nil {
+		logger.Errorf("error saving rehashed password: %v", err)
+		return
+	}
+
+	logger.Info("password hash upgraded to the current scheme")
+}
@@ -0,0 +1,24 @@
+package account
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+)
+
+type IAccountRepo interface {
+	// Update сохраняет ФИО, номер телефона и хэш пароля читателя
+	Update(ctx context.Context, reader *models.ReaderModel) error
+	// ReplacePasswordHash заменяет хэш пароля читателя, пересчитанный по новой
+	// схеме, только если хэш все еще равен oldHash. Если пароль успели сменить,
+	// хэш не меняется
+	ReplacePasswordHash(ctx context.Context, readerID uuid.UUID, oldHash, newHash string) error
+	// Delete удаляет персональные данные читателя: отзывы и бронирования
+	// переносятся на анонимного читателя, читательский билет и избранное удаляются
+	Delete(ctx context.Context, readerID uuid.UUID) error
+}
+
+type IRefreshTokenRepo interface {
+	// DeleteByReaderID отзывает все refresh-токены читателя
+	DeleteByReaderID(ctx context.Context, readerID uuid.UUID) error
+}
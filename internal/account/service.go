@@ -0,0 +1,263 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/hash"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type IAccountService interface {
+	UpdateFio(ctx context.Context, readerID uuid.UUID, fio string) (*models.ReaderModel, error)
+	ChangePhoneNumber(ctx context.Context, readerID uuid.UUID, password, phoneNumber string) (*models.ReaderModel, error)
+	ChangePassword(ctx context.Context, readerID uuid.UUID, password, newPassword string) error
+	Delete(ctx context.Context, readerID uuid.UUID, password string) error
+}
+
+type AccountService struct {
+	accountRepo        IAccountRepo
+	readerRepo         intfRepo.IReaderRepo
+	reservationRepo    intfRepo.IReservationRepo
+	refreshTokenRepo   IRefreshTokenRepo
+	hasher             hash.IPasswordHasher
+	transactionManager transact.ITransactionManager
+	logger             *logrus.Entry
+}
+
+func NewAccountService(
+	accountRepo IAccountRepo,
+	readerRepo intfRepo.IReaderRepo,
+	reservationRepo intfRepo.IReservationRepo,
+	refreshTokenRepo IRefreshTokenRepo,
+	hasher hash.IPasswordHasher,
+	transactionManager transact.ITransactionManager,
+	logger *logrus.Entry,
+) IAccountService {
+	return &AccountService{
+		accountRepo:        accountRepo,
+		readerRepo:         readerRepo,
+		reservationRepo:    reservationRepo,
+		refreshTokenRepo:   refreshTokenRepo,
+		hasher:             hasher,
+		transactionManager: transactionManager,
+		logger:             logger,
+	}
+}
+
+func (as *AccountService) UpdateFio(ctx context.Context, readerID uuid.UUID, fio string) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, as.logger)
+	logger.Info("attempting to update reader fio")
+
+	fio = strings.TrimSpace(fio)
+	if fio == "" {
+		logger.Warn("empty reader fio")
+		return nil, errs.ErrEmptyReaderFio
+	}
+
+	reader, err := as.readerRepo.GetByID(ctx, readerID)
+	if err != nil {
+		logger.Errorf("error getting reader: %v", err)
+		return nil, err
+	}
+
+	reader.Fio = fio
+
+	if err = as.accountRepo.Update(ctx, reader); err != nil {
+		logger.Errorf("error updating reader: %v", err)
+		return nil, err
+	}
+
+	logger.Info("reader fio successfully updated")
+
+	return reader, nil
+}
+
+// ChangePhoneNumber меняет номер телефона читателя. Так как номер телефона
+// используется для входа, смена требует подтверждения текущим паролем
+func (as *AccountService) ChangePhoneNumber(
+	ctx context.Context,
+	readerID uuid.UUID,
+	password, phoneNumber string,
+) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, as.logger)
+	logger.Info("attempting to change reader phone number")
+
+	if err := as.checkPhoneNumber(phoneNumber); err != nil {
+		return nil, err
+	}
+
+	var reader *models.ReaderModel
+	err := as.transactionManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if reader, err = as.reauthenticate(ctx, readerID, password); err != nil {
+			return err
+		}
+
+		if reader.PhoneNumber == phoneNumber {
+			return nil
+		}
+
+		existing, err := as.readerRepo.GetByPhoneNumber(ctx, phoneNumber)
+		if err != nil && !errors.Is(err, errs.ErrReaderDoesNotExists) {
+			return err
+		}
+		if existing != nil {
+			logger.Warn("reader with this phone number already exists")
+			return errs.ErrReaderAlreadyExist
+		}
+
+		reader.PhoneNumber = phoneNumber
+
+		return as.accountRepo.Update(ctx, reader)
+	})
+	if err != nil {
+		logger.Errorf("error changing reader phone number: %v", err)
+		return nil, err
+	}
+
+	logger.Info("reader phone number successfully changed")
+
+	return reader, nil
+}
+
+// ChangePassword меняет пароль читателя после проверки текущего пароля
+// и отзывает все выданные читателю refresh-токены
+func (as *AccountService) ChangePassword(ctx context.Context, readerID uuid.UUID, password, newPassword string) error {
+	logger := logging.FromContext(ctx, as.logger)
+	logger.Info("attempting to change reader password")
+
+	if utf8.RuneCountInString(newPassword) < ReaderPasswordMinLen {
+		logger.Warn("invalid reader password length")
+		return errs.ErrInvalidReaderPasswordLen
+	}
+
+	if password == newPassword {
+		logger.Warn("new password equals the current one")
+		return ErrSamePassword
+	}
+
+	err := as.transactionManager.Do(ctx, func(ctx context.Context) error {
+		reader, err := as.reauthenticate(ctx, readerID, password)
+		if err != nil {
+			return err
+		}
+
+		if reader.Password, err = as.hasher.Hash(newPassword); err != nil {
+			return err
+		}
+
+		return as.accountRepo.Update(ctx, reader)
+	})
+	if err != nil {
+		logger.Errorf("error changing reader password: %v", err)
+		return err
+	}
+
+	if err = as.refreshTokenRepo.DeleteByReaderID(ctx, readerID); err != nil {
+		logger.Errorf("error revoking reader refresh tokens: %v", err)
+		return err
+	}
+
+	logger.Info("reader password successfully changed")
+
+	return nil
+}
+
+// Delete удаляет аккаунт читателя. Удаление невозможно, пока у читателя
+// есть невозвращенные книги
+func (as *AccountService) Delete(ctx context.Context, readerID uuid.UUID, password string) error {
+	logger := logging.FromContext(ctx, as.logger)
+	logger.Info("attempting to delete reader")
+
+	err := as.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := as.reauthenticate(ctx, readerID, password); err != nil {
+			return err
+		}
+
+		if err := as.checkNoBooksOnLoan(ctx, readerID); err != nil {
+			return err
+		}
+
+		return as.accountRepo.Delete(ctx, readerID)
+	})
+	if err != nil {
+		logger.Errorf("error deleting reader: %v", err)
+		return err
+	}
+
+	if err = as.refreshTokenRepo.DeleteByReaderID(ctx, readerID); err != nil {
+		logger.Errorf("error revoking reader refresh tokens: %v", err)
+		return err
+	}
+
+	logger.Info("reader successfully deleted")
+
+	return nil
+}
+
+func (as *AccountService) reauthenticate(ctx context.Context, readerID uuid.UUID, password string) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, as.logger)
+
+	reader, err := as.readerRepo.GetByID(ctx, readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = as.hasher.Compare(reader.Password, password); err != nil {
+		logger.Warn("wrong reader password")
+		return nil, ErrWrongPassword
+	}
+
+	return reader, nil
+}
+
+func (as *AccountService) checkNoBooksOnLoan(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, as.logger)
+
+	active, err := as.reservationRepo.GetActiveByReaderID(ctx, readerID)
+	if err != nil && !errors.Is(err, errs.ErrReservationDoesNotExists) {
+		return err
+	}
+
+	expired, err := as.reservationRepo.GetExpiredByReaderID(ctx, readerID)
+	if err != nil && !errors.Is(err, errs.ErrReservationDoesNotExists) {
+		return err
+	}
+
+	if len(active) > 0 || len(expired) > 0 {
+		logger.Warn("reader has books on loan")
+		return ErrReaderHasBooksOnLoan
+	}
+
+	return nil
+}
+
+func (as *AccountService) checkPhoneNumber(phoneNumber string) error {
+	if phoneNumber == "" {
+		as.logger.Warn("empty reader phone number")
+		return errs.ErrEmptyReaderPhoneNumber
+	}
+
+	if len(phoneNumber) != ReaderPhoneNumberLen {
+		as.logger.Warn("invalid reader phone number length")
+		return errs.ErrInvalidReaderPhoneNumberLen
+	}
+
+	for _, r := range phoneNumber {
+		if !unicode.IsDigit(r) {
+			as.logger.Warn("invalid reader phone number format")
+			return errs.ErrInvalidReaderPhoneNumberFormat
+		}
+	}
+
+	return nil
+}
@@ -1,10 +1,15 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"github.com/alicebob/miniredis/v2"
 	trmmongo "github.com/avito-tech/go-transaction-manager/drivers/mongo/v2"
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	repoMongo "github.com/nikitalystsev/BookSmart-repo-mongo"
 	implMongo "github.com/nikitalystsev/BookSmart-repo-mongo/impl"
@@ -16,103 +21,549 @@ import (
 	"github.com/nikitalystsev/BookSmart-services/pkg/hash"
 	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
 	"github.com/nikitalystsev/BookSmart-web-api/handlers"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/internal/cache"
+	"github.com/nikitalystsev/BookSmart/internal/conditional"
 	"github.com/nikitalystsev/BookSmart/internal/config"
+	"github.com/nikitalystsev/BookSmart/internal/favorite"
+	"github.com/nikitalystsev/BookSmart/internal/health"
+	"github.com/nikitalystsev/BookSmart/internal/idempotency"
+	"github.com/nikitalystsev/BookSmart/internal/inventory"
+	"github.com/nikitalystsev/BookSmart/internal/jwks"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	"github.com/nikitalystsev/BookSmart/internal/metrics"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/migration"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	"github.com/nikitalystsev/BookSmart/internal/passwordreset"
+	"github.com/nikitalystsev/BookSmart/internal/ratelimit"
+	"github.com/nikitalystsev/BookSmart/internal/readinglist"
+	"github.com/nikitalystsev/BookSmart/internal/replication"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"github.com/nikitalystsev/BookSmart/internal/signinthrottle"
+	"github.com/nikitalystsev/BookSmart/internal/similar"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	"github.com/nikitalystsev/BookSmart/internal/tracing"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"github.com/nikitalystsev/BookSmart/pkg/lifecycle"
 	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/passwordhash"
+	"github.com/nikitalystsev/BookSmart/pkg/sms"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"strings"
+	"time"
 )
 
-func Run(configDir string) {
-	cfg, err := config.Init(configDir)
+// metricsRegistry - реестр метрик приложения, отдаваемый на /metrics
+var metricsRegistry = metrics.NewRegistry()
+
+// MetricsRegistry позволяет пакетам приложения регистрировать собственные метрики
+func MetricsRegistry() prometheus.Registerer {
+	return metricsRegistry
+}
+
+// Run собирает приложение и обслуживает запросы до SIGINT/SIGTERM. Ошибка
+// возвращается, если приложение не удалось запустить или корректно остановить.
+// args - аргументы командной строки, переопределяющие параметры конфигурации
+func Run(configDir string, args []string) (err error) {
+	configLoader, err := config.NewLoader(configDir, args)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error initializing config: %w", err)
 	}
 
-	fmt.Printf("postgres db name: %s\n", cfg.Postgres.DBName)
-	fmt.Printf("postgres db user: %s\n", cfg.Postgres.Username)
-	fmt.Printf("postgres db password: %s\n", cfg.Postgres.Password)
-	fmt.Printf("postgres host: %s\n", cfg.Postgres.Host)
+	cfg, err := configLoader.Load()
+	if err != nil {
+		return fmt.Errorf("error initializing config: %w", err)
+	}
 
-	logger, err := logging.NewLogger()
+	logger, err := newLogger(cfg.Logging)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error initializing logger: %w", err)
 	}
 
+	logger.WithFields(logrus.Fields{
+		"db_type":           cfg.DBType,
+		"postgres_host":     cfg.Postgres.Host,
+		"postgres_db_name":  cfg.Postgres.DBName,
+		"postgres_user":     cfg.Postgres.Username,
+		"postgres_password": config.Redact(cfg.Postgres.Password),
+		"mongo_db_name":     cfg.Mongo.DBName,
+		"mongo_user":        cfg.Mongo.Username,
+		"mongo_password":    config.Redact(cfg.Mongo.Password),
+		"redis_host":        cfg.Redis.Host,
+		"redis_password":    config.Redact(cfg.Redis.Password),
+	}).Info("starting application")
+
+	// ресурсы регистрируются в lc сразу после создания; если запуск прервется,
+	// уже открытые соединения будут закрыты
+	lc := lifecycle.New(logger, cfg.Server.ShutdownTimeout)
+	defer func() {
+		if err != nil {
+			_ = lc.Stop(context.Background())
+		}
+	}()
+
+	retryPolicy := lifecycle.RetryPolicy{
+		Attempts:     cfg.Startup.RetryAttempts,
+		InitialDelay: cfg.Startup.RetryInitialDelay,
+		MaxDelay:     cfg.Startup.RetryMaxDelay,
+	}
+
+	tracingCfg := cfg.Tracing
+	tracerProvider, err := tracing.NewProvider(context.Background(), tracing.Options{
+		Exporter:     tracingCfg.Exporter,
+		ServiceName:  tracingCfg.ServiceName,
+		SampleRatio:  tracingCfg.SampleRatio,
+		OTLPEndpoint: tracingCfg.OTLP.Endpoint,
+		OTLPInsecure: tracingCfg.OTLP.Insecure,
+		OTLPHeaders:  tracingCfg.OTLP.Headers,
+	})
+	if err != nil {
+		logger.Errorf("error initializing tracer provider: %v", err)
+		return err
+	}
+	lc.OnStop("tracer provider", tracerProvider.Shutdown)
+
 	var (
-		bookRepo        intfRepo.IBookRepo
-		libCardRepo     intfRepo.ILibCardRepo
-		readerRepo      intfRepo.IReaderRepo
-		reservationRepo intfRepo.IReservationRepo
-		ratingRepo      intfRepo.IRatingRepo
+		bookRepo          intfRepo.IBookRepo
+		libCardRepo       intfRepo.ILibCardRepo
+		readerRepo        intfRepo.IReaderRepo
+		reservationRepo   intfRepo.IReservationRepo
+		ratingRepo        intfRepo.IRatingRepo
+		copiesRepo        inventory.ICopiesRepo
+		deletedBookRepo   softdelete.IDeletedBookRepo
+		deletedReaderRepo softdelete.IDeletedReaderRepo
+		readingListRepo   readinglist.IReadingListRepo
+		accountRepo       account.IAccountRepo
+		twoFactorRepo     twofactor.ITwoFactorRepo
+		similarBookRepo   similar.ISimilarBookRepo
+		favoriteBookRepo  favorite.IFavoriteBookRepo
+		versionRepo       optimistic.IVersionRepo
 
 		trm *manager.Manager
 	)
 
-	client := redis.NewClient(&redis.Options{
+	redisOptions := &redis.Options{
 		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
 		Username: cfg.Redis.Username,
 		Password: cfg.Redis.Password,
 		DB:       cfg.Redis.DB,
+	}
+	if cfg.DBType == config.DBTypeMemory {
+		// хранилище в памяти запускается без внешних сервисов: сессии, токены,
+		// лимиты и блокировки входа хранит miniredis в памяти процесса
+		var redisServer *miniredis.Miniredis
+		if redisServer, err = miniredis.Run(); err != nil {
+			logger.Errorf("error starting in-process redis: %v", err)
+			return err
+		}
+		lc.OnStop("in-process redis", func(context.Context) error {
+			redisServer.Close()
+			return nil
+		})
+		redisOptions = &redis.Options{Addr: redisServer.Addr()}
+	}
+
+	client := redis.NewClient(redisOptions)
+	lc.OnStop("redis client", func(context.Context) error { return client.Close() })
+
+	err = lifecycle.Retry(context.Background(), retryPolicy, logger, "redis", func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
 	})
+	if err != nil {
+		logger.Errorf("error connect to redis: %v", err)
+		return err
+	}
+
+	// готовность экземпляра определяется активной БД и Redis
+	checker := health.NewChecker(health.DefaultCheckTimeout)
+	checker.Add("redis", health.RedisCheck(client))
+
+	readFromReplicas := false
 
 	switch cfg.DBType {
-	case "postgres":
-		dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
-			cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.Username, cfg.Postgres.DBName,
-			cfg.Postgres.Password, cfg.Postgres.SSLMode)
+	case config.DBTypePostgres:
+		postgresCfg := cfg.Postgres
+		openPostgres := func(ctx context.Context, host, port string) (*sqlx.DB, error) {
+			dsn := postgresDSN(postgresCfg, host, port)
+			if tracerProvider.Enabled() {
+				return tracing.OpenPostgres(ctx, dsn, tracerProvider)
+			}
+			return repoPostgres.NewClient(dsn)
+		}
 
-		db, err := repoPostgres.NewClient(dsn)
+		var db *sqlx.DB
+		err = lifecycle.Retry(context.Background(), retryPolicy, logger, "postgres", func(ctx context.Context) error {
+			var err error
+			db, err = openPostgres(ctx, postgresCfg.Host, postgresCfg.Port)
+			return err
+		})
 		if err != nil {
 			logger.Errorf("error connect to postgres: %v", err)
-			return
+			return err
 		}
+		lc.OnStop("postgres pool", func(context.Context) error { return db.Close() })
 
-		bookRepo = implPostgres.NewBookRepo(db, logger)
-		libCardRepo = implPostgres.NewLibCardRepo(db, logger)
-		readerRepo = implPostgres.NewReaderRepo(db, client, logger)
-		reservationRepo = implPostgres.NewReservationRepo(db, logger)
-		ratingRepo = implPostgres.NewRatingRepo(db, logger)
+		checker.Add("postgres", health.PostgresCheck(db))
+
+		var migrator *migration.Migrator
+		if migrator, err = migration.NewPostgresMigrator(db.DB, logger); err != nil {
+			logger.Errorf("error initializing migrator: %v", err)
+			return err
+		}
+		if err = prepareSchema(migrator, cfg.Migrations, logger); err != nil {
+			logger.Errorf("error preparing database schema: %v", err)
+			return err
+		}
 
 		trm, err = manager.New(trmsqlx.NewDefaultFactory(db))
 		if err != nil {
 			logger.Errorf("error initializing manager: %v", err)
-			return
+			return err
 		}
-	default:
-		mongoClient, err := repoMongo.NewClient(cfg.Mongo.URI, cfg.Mongo.Username, cfg.Mongo.Password, cfg.Mongo.DBName)
+		versionTransactionManager := transact.NewTransactionManager(trm)
+
+		versionRepo = optimistic.NewPostgresRepo(db, logger)
+		bookRepo = optimistic.NewBookRepo(implPostgres.NewBookRepo(db, logger), versionRepo, versionTransactionManager)
+		libCardRepo = optimistic.NewLibCardRepo(implPostgres.NewLibCardRepo(db, logger), versionRepo, versionTransactionManager)
+		readerRepo = implPostgres.NewReaderRepo(db, client, logger)
+		metricsRegistry.MustRegister(collectors.NewDBStatsCollector(db.DB, "postgres"))
+		reservationRepo = optimistic.NewReservationRepo(implPostgres.NewReservationRepo(db, logger), versionRepo, versionTransactionManager)
+		ratingRepo = implPostgres.NewRatingRepo(db, logger)
+		copiesRepo = inventory.NewPostgresRepo(db, logger)
+		deletedBookRepo = softdelete.NewPostgresRepo(db, logger)
+		deletedReaderRepo = softdelete.NewPostgresReaderRepo(db, logger)
+		similarBookRepo = similar.NewPostgresRepo(db, logger)
+
+		// реплики не входят в готовность экземпляра: без них чтение идет с мастера
+		var (
+			replicas            []*sqlx.DB
+			replicaBookRepos    []intfRepo.IBookRepo
+			replicaLibCardRepos []intfRepo.ILibCardRepo
+			replicaReservations []intfRepo.IReservationRepo
+			replicaRatingRepos  []intfRepo.IRatingRepo
+			replicaSimilarBooks []similar.ISimilarBookRepo
+			replicaDeletedBooks []softdelete.IDeletedBookRepo
+		)
+		for _, replicaHost := range postgresCfg.ReplicaHosts {
+			host, port, found := strings.Cut(replicaHost, ":")
+			if !found {
+				port = postgresCfg.Port
+			}
+
+			replica, err := openPostgres(context.Background(), host, port)
+			if err != nil {
+				logger.Warnf("error connect to postgres replica %s, skipping it: %v", replicaHost, err)
+				continue
+			}
+			lc.OnStop("postgres replica pool "+replicaHost, func(context.Context) error { return replica.Close() })
+			metricsRegistry.MustRegister(collectors.NewDBStatsCollector(replica.DB, fmt.Sprintf("postgres_replica_%d", len(replicas))))
+
+			// версия читается с той же реплики, что и данные
+			replicaVersionRepo := optimistic.NewPostgresRepo(replica, logger)
+
+			replicas = append(replicas, replica)
+			replicaBookRepos = append(replicaBookRepos, optimistic.NewBookRepo(
+				implPostgres.NewBookRepo(replica, logger), replicaVersionRepo, versionTransactionManager))
+			replicaLibCardRepos = append(replicaLibCardRepos, optimistic.NewLibCardRepo(
+				implPostgres.NewLibCardRepo(replica, logger), replicaVersionRepo, versionTransactionManager))
+			replicaReservations = append(replicaReservations, optimistic.NewReservationRepo(
+				implPostgres.NewReservationRepo(replica, logger), replicaVersionRepo, versionTransactionManager))
+			replicaRatingRepos = append(replicaRatingRepos, implPostgres.NewRatingRepo(replica, logger))
+			replicaSimilarBooks = append(replicaSimilarBooks, similar.NewPostgresRepo(replica, logger))
+			replicaDeletedBooks = append(replicaDeletedBooks, softdelete.NewPostgresRepo(replica, logger))
+		}
+
+		if len(replicas) > 0 {
+			replicationCfg := postgresCfg.Replication
+			replicaRouter := replication.NewRouter(replicas, replicationCfg.MaxLag, logger)
+			lc.Go("postgres replica lag check", func(ctx context.Context) {
+				replicaRouter.Run(ctx, replicationCfg.LagCheckInterval)
+			})
+
+			bookRepo = replication.NewBookRepo(replicaRouter, bookRepo, replicaBookRepos...)
+			libCardRepo = replication.NewLibCardRepo(replicaRouter, libCardRepo, replicaLibCardRepos...)
+			reservationRepo = replication.NewReservationRepo(replicaRouter, reservationRepo, replicaReservations...)
+			ratingRepo = replication.NewRatingRepo(replicaRouter, ratingRepo, replicaRatingRepos...)
+			similarBookRepo = replication.NewSimilarBookRepo(replicaRouter, similarBookRepo, replicaSimilarBooks...)
+			deletedBookRepo = replication.NewDeletedBookRepo(replicaRouter, deletedBookRepo, replicaDeletedBooks...)
+
+			readFromReplicas = true
+		}
+		readingListRepo = readinglist.NewPostgresRepo(db, logger)
+		accountRepo = account.NewPostgresRepo(db, logger)
+		twoFactorRepo = twofactor.NewPostgresRepo(db, logger)
+		favoriteBookRepo = favorite.NewPostgresRepo(db, logger)
+	case config.DBTypeMongo:
+		var mongoClient *mongo.Client
+		err = lifecycle.Retry(context.Background(), retryPolicy, logger, "mongo", func(ctx context.Context) error {
+			var err error
+			mongoClient, err = repoMongo.NewClient(cfg.Mongo.URI, cfg.Mongo.Username, cfg.Mongo.Password, cfg.Mongo.DBName)
+			return err
+		})
 		if err != nil {
 			logger.Errorf("error connect to mongo: %v", err)
-			return
+			return err
 		}
+		lc.OnStop("mongo client", mongoClient.Disconnect)
 		db := mongoClient.Database(cfg.Mongo.DBName)
+		checker.Add("mongo", health.MongoCheck(mongoClient))
+
+		var migrator *migration.Migrator
+		if migrator, err = migration.NewMongoMigrator(mongoClient, cfg.Mongo.DBName, logger); err != nil {
+			logger.Errorf("error initializing migrator: %v", err)
+			return err
+		}
+		if err = prepareSchema(migrator, cfg.Migrations, logger); err != nil {
+			logger.Errorf("error preparing database schema: %v", err)
+			return err
+		}
+
+		trm, err = manager.New(trmmongo.NewDefaultFactory(mongoClient))
+		if err != nil {
+			logger.Errorf("error initializing manager: %v", err)
+			return err
+		}
+		versionTransactionManager := transact.NewTransactionManager(trm)
 
-		bookRepo = implMongo.NewBookRepo(db, logger)
-		libCardRepo = implMongo.NewLibCardRepo(db, logger)
+		versionRepo = optimistic.NewMongoRepo(db, logger)
+		bookRepo = optimistic.NewBookRepo(implMongo.NewBookRepo(db, logger), versionRepo, versionTransactionManager)
+		libCardRepo = optimistic.NewLibCardRepo(implMongo.NewLibCardRepo(db, logger), versionRepo, versionTransactionManager)
 		readerRepo = implMongo.NewReaderRepo(db, client, logger)
-		reservationRepo = implMongo.NewReservationRepo(db, logger)
+		reservationRepo = optimistic.NewReservationRepo(implMongo.NewReservationRepo(db, logger), versionRepo, versionTransactionManager)
 		ratingRepo = implMongo.NewRatingRepo(db, logger)
+		copiesRepo = inventory.NewMongoRepo(db, logger)
+		deletedBookRepo = softdelete.NewMongoRepo(db, logger)
+		deletedReaderRepo = softdelete.NewMongoReaderRepo(db, logger)
+		readingListRepo = readinglist.NewMongoRepo(db, logger)
+		accountRepo = account.NewMongoRepo(db, logger)
+		twoFactorRepo = twofactor.NewMongoRepo(db, logger)
+		similarBookRepo = similar.NewMongoRepo(db, logger)
+		favoriteBookRepo = favorite.NewMongoRepo(db, logger)
+	case config.DBTypeMemory:
+		// данные живут в памяти процесса; снимок, если он задан, сохраняется
+		// после остановки сервера и фоновых задач
+		store := memory.NewStore(logger)
+		if snapshotPath := cfg.Memory.SnapshotPath; snapshotPath != "" {
+			if err = store.Load(context.Background(), snapshotPath); err != nil {
+				logger.Errorf("error loading memory snapshot: %v", err)
+				return err
+			}
+			lc.OnStop("memory snapshot", func(ctx context.Context) error { return store.Save(ctx, snapshotPath) })
+		}
 
-		trm, err = manager.New(trmmongo.NewDefaultFactory(mongoClient))
+		trm, err = manager.New(store.TrFactory)
 		if err != nil {
 			logger.Errorf("error initializing manager: %v", err)
-			return
+			return err
+		}
+		versionTransactionManager := transact.NewTransactionManager(trm)
+
+		versionRepo = memory.NewVersionRepo(store, logger)
+		bookRepo = optimistic.NewBookRepo(memory.NewBookRepo(store, logger), versionRepo, versionTransactionManager)
+		libCardRepo = optimistic.NewLibCardRepo(memory.NewLibCardRepo(store, logger), versionRepo, versionTransactionManager)
+		readerRepo = memory.NewReaderRepo(store, logger)
+		reservationRepo = optimistic.NewReservationRepo(memory.NewReservationRepo(store, logger), versionRepo, versionTransactionManager)
+		ratingRepo = memory.NewRatingRepo(store, logger)
+		copiesRepo = memory.NewCopiesRepo(store, logger)
+		deletedBookRepo = memory.NewDeletedBookRepo(store, logger)
+		deletedReaderRepo = memory.NewDeletedReaderRepo(store, logger)
+		readingListRepo = memory.NewReadingListRepo(store, logger)
+		accountRepo = memory.NewAccountRepo(store, logger)
+		twoFactorRepo = memory.NewTwoFactorRepo(store, logger)
+		similarBookRepo = memory.NewSimilarBookRepo(store, logger)
+		favoriteBookRepo = memory.NewFavoriteBookRepo(store, logger)
+	default:
+		return fmt.Errorf("unknown db type %q", cfg.DBType)
+	}
+
+	// удаление книги мягкое; удаленную книгу нельзя забронировать, но можно
+	// вернуть ее экземпляр
+	bookRepo = softdelete.NewBookRepo(bookRepo, deletedBookRepo, versionRepo)
+	// изменение числа экземпляров в транзакции выполняется условным обновлением,
+	// чтобы параллельные брони не взяли один экземпляр дважды
+	bookRepo = inventory.NewBookRepo(bookRepo, copiesRepo)
+
+	// пул соединений Mongo создается внешним модулем без PoolMonitor, поэтому
+	// статистика пула отдается только для Postgres и Redis
+	appMetrics := metrics.New(metricsRegistry)
+	metricsRegistry.MustRegister(metrics.NewRedisPoolCollector(client))
+
+	backend := string(cfg.DBType)
+	bookRepo = metrics.NewBookRepo(bookRepo, appMetrics, backend)
+	libCardRepo = metrics.NewLibCardRepo(libCardRepo, appMetrics, backend)
+	readerRepo = metrics.NewReaderRepo(readerRepo, appMetrics, backend)
+	reservationRepo = metrics.NewReservationRepo(reservationRepo, appMetrics, backend)
+	ratingRepo = metrics.NewRatingRepo(ratingRepo, appMetrics, backend)
+
+	bookRepo = tracing.NewBookRepo(bookRepo, tracerProvider, backend)
+	libCardRepo = tracing.NewLibCardRepo(libCardRepo, tracerProvider, backend)
+	readerRepo = tracing.NewReaderRepo(readerRepo, tracerProvider, backend)
+	reservationRepo = tracing.NewReservationRepo(reservationRepo, tracerProvider, backend)
+	ratingRepo = tracing.NewRatingRepo(ratingRepo, tracerProvider, backend)
+	readingListRepo = tracing.NewReadingListRepo(readingListRepo, tracerProvider, backend)
+	accountRepo = tracing.NewAccountRepo(accountRepo, tracerProvider, backend)
+	twoFactorRepo = tracing.NewTwoFactorRepo(twoFactorRepo, tracerProvider, backend)
+	favoriteBookRepo = tracing.NewFavoriteBookRepo(favoriteBookRepo, tracerProvider, backend)
+
+	// кэш оборачивает остальные декораторы: попадания в кэш не считаются
+	// запросами к БД в метриках и трассировке
+	var repoCache *cache.Cache
+	if cacheCfg := cfg.Cache; cacheCfg.Enabled {
+		toTTL := func(ttlCfg config.CacheTTLConfig) cache.TTL {
+			return cache.TTL{Fresh: ttlCfg.TTL, Stale: ttlCfg.StaleTTL}
 		}
+
+		repoCache = cache.New(client, logger)
+		bookRepo = cache.NewBookRepo(bookRepo, repoCache, toTTL(cacheCfg.Book), toTTL(cacheCfg.BookList))
+		ratingRepo = cache.NewRatingRepo(ratingRepo, repoCache, toTTL(cacheCfg.Rating))
 	}
 
-	tokenManager, err := auth.NewTokenManager(cfg.Auth.JWT.SigningKey)
+	// версии ресурсов, по которым строятся ETag и Last-Modified ответов
+	versionStore := conditional.NewRedisVersionStore(client, logger)
+	bookRepo = conditional.NewBookRepo(bookRepo, versionStore)
+	ratingRepo = conditional.NewRatingRepo(ratingRepo, versionStore)
+	reservationRepo = conditional.NewReservationRepo(reservationRepo, versionStore)
+
+	sessionStore := session.NewRedisStore(client, logger)
+	readerRepo = session.NewReaderRepo(readerRepo, sessionStore, cfg.Auth.JWT.RefreshTokenTTL, logger)
+	// удаленный читатель не получает новых сессий; декоратор лежит над
+	// сессиями, потому что они не передают сохранение refresh-токена дальше
+	readerRepo = softdelete.NewReaderRepo(readerRepo, deletedReaderRepo)
+
+	legacyTokenManager, err := auth.NewTokenManager(cfg.Auth.JWT.SigningKey)
 	if err != nil {
 		logger.Errorf("error initializing token manager: %v", err)
-		return
+		return err
 	}
 
-	hasher := hash.NewPasswordHasher(cfg.Auth.PasswordSalt)
+	signingKeys, err := loadSigningKeys(cfg.Auth.JWT)
+	if err != nil {
+		logger.Errorf("error loading jwt signing keys: %v", err)
+		return err
+	}
 
-	transactionManager := transact.NewTransactionManager(trm)
+	// формат уже проверен при загрузке конфигурации, пустое значение дает
+	// нулевое время, и токены без kid не принимаются
+	legacyTokensUntil, _ := time.Parse(time.RFC3339, cfg.Auth.JWT.LegacyTokensUntil)
+
+	tokenManager, err := token.NewManager(cfg.Auth.JWT.ActiveKeyID, signingKeys, legacyTokenManager, legacyTokensUntil,
+		token.NewRedisDenylist(client, logger), cfg.Auth.JWT.AccessTokenTTL, logger)
+	if err != nil {
+		logger.Errorf("error initializing token manager: %v", err)
+		return err
+	}
+	tokenRevoker := session.NewRevoker(sessionStore, tokenManager)
+
+	hashingCfg := cfg.Auth.PasswordHashing
+	hasher, err := passwordhash.NewHasher(passwordhash.Params{
+		Algorithm: hashingCfg.Algorithm,
+		Argon2: passwordhash.Argon2Params{
+			Memory:      hashingCfg.Argon2.Memory,
+			Iterations:  hashingCfg.Argon2.Iterations,
+			Parallelism: hashingCfg.Argon2.Parallelism,
+		},
+		BcryptCost: hashingCfg.BcryptCost,
+	}, hash.NewPasswordHasher(cfg.Auth.PasswordSalt))
+	if err != nil {
+		logger.Errorf("error initializing password hasher: %v", err)
+		return err
+	}
+
+	transactionManager := tracing.NewTransactionManager(transact.NewTransactionManager(trm), tracerProvider)
+	if repoCache != nil {
+		transactionManager = cache.NewTransactionManager(transactionManager, repoCache)
+	}
+	transactionManager = conditional.NewTransactionManager(transactionManager, versionStore)
+	transactionManager = inventory.NewTransactionManager(transactionManager)
+	transactionManager = optimistic.NewTransactionManager(transactionManager)
+
+	throttleCfg := cfg.SignInThrottle
+	signInThrottle := signinthrottle.NewThrottle(signinthrottle.NewRedisAttemptStore(client, logger), signinthrottle.Policy{
+		FailureWindow:   throttleCfg.FailureWindow,
+		BaseDelay:       throttleCfg.BaseDelay,
+		MaxDelay:        throttleCfg.MaxDelay,
+		LockoutDuration: throttleCfg.LockoutDuration,
+		Phone:           signinthrottle.Limits{FreeAttempts: throttleCfg.Phone.FreeAttempts, MaxFailures: throttleCfg.Phone.MaxFailures},
+		IP:              signinthrottle.Limits{FreeAttempts: throttleCfg.IP.FreeAttempts, MaxFailures: throttleCfg.IP.MaxFailures},
+	}, logger)
+
+	twoFactorService := twofactor.NewTwoFactorService(
+		twoFactorRepo,
+		twofactor.NewRedisChallengeStore(client, logger),
+		readerRepo,
+		signInThrottle,
+		tokenManager,
+		logger,
+		cfg.Auth.JWT.AccessTokenTTL,
+		cfg.Auth.JWT.RefreshTokenTTL,
+		cfg.TwoFactor.EnforcedRoles,
+		cfg.TwoFactor.ChallengeTTL,
+		cfg.TwoFactor.MaxAttempts,
+		cfg.TwoFactor.MaxFailures,
+		cfg.TwoFactor.LockoutDuration,
+	)
 
 	bookService := impl.NewBookService(bookRepo, logger)
 	libCardService := impl.NewLibCardService(libCardRepo, logger)
-	readerService := impl.NewReaderService(readerRepo, bookRepo, tokenManager, hasher, logger, cfg.Auth.JWT.AccessTokenTTL, cfg.Auth.JWT.RefreshTokenTTL)
-	reservationService := impl.NewReservationService(reservationRepo, bookRepo, readerRepo, libCardRepo, transactionManager, logger)
+	// вход через внешний сервис читателей открывает challenge вместо сессии,
+	// если читателю нужен второй фактор
+	readerService := impl.NewReaderService(twofactor.NewReaderRepo(readerRepo, twoFactorService, logger), bookRepo,
+		tokenManager, hasher, logger, cfg.Auth.JWT.AccessTokenTTL, cfg.Auth.JWT.RefreshTokenTTL)
+	reservationService := softdelete.NewReservationService(
+		impl.NewReservationService(reservationRepo, bookRepo, readerRepo, libCardRepo, transactionManager, logger))
 	ratingService := impl.NewRatingService(ratingRepo, reservationRepo, logger)
+	readingListService := readinglist.NewReadingListService(readingListRepo, bookRepo, transactionManager, logger)
+	favoriteBookService := favorite.NewFavoriteBookService(favoriteBookRepo, logger)
+	// удаление аккаунта мягкое; данные читателя окончательно удаляет deletedReaderService
+	accountService := account.NewAccountService(softdelete.NewAccountRepo(accountRepo, deletedReaderRepo),
+		readerRepo, reservationRepo, tokenRevoker, hasher, transactionManager, logger)
+
+	smsSender, err := sms.NewSender(cfg.SMS.Sender, cfg.SMS.FilePath, logger)
+	if err != nil {
+		logger.Errorf("error initializing sms sender: %v", err)
+		return err
+	}
+	passwordResetService := passwordreset.NewPasswordResetService(
+		passwordreset.NewRedisCodeStore(client, logger),
+		readerRepo,
+		accountRepo,
+		tokenRevoker,
+		hasher,
+		smsSender,
+		logger,
+		cfg.PasswordReset.CodeTTL,
+		cfg.PasswordReset.MaxAttempts,
+		cfg.PasswordReset.ResendInterval,
+	)
+
+	onBookChange := []softdelete.OnChange{
+		func(ctx context.Context, bookID uuid.UUID) { conditional.TouchBook(ctx, versionStore, bookID) },
+	}
+	if repoCache != nil {
+		onBookChange = append(onBookChange, repoCache.InvalidateBook)
+	}
+	deletedBookService := softdelete.NewDeletedBookService(deletedBookRepo, transactionManager, logger,
+		cfg.BookDeletion.Retention, onBookChange...)
+	lc.Go("deleted books purge", func(ctx context.Context) {
+		deletedBookService.Run(ctx, cfg.BookDeletion.PurgeInterval)
+	})
+	deletedReaderService := softdelete.NewDeletedReaderService(deletedReaderRepo, accountRepo, transactionManager, logger,
+		cfg.ReaderDeletion.Retention)
+	lc.Go("deleted readers purge", func(ctx context.Context) {
+		deletedReaderService.Run(ctx, cfg.ReaderDeletion.PurgeInterval)
+	})
+
+	similarBookService := similar.NewSimilarBookService(bookRepo, similarBookRepo, logger)
+	lc.Go("book co-reservations refresh", func(ctx context.Context) {
+		similarBookService.Run(ctx, similar.RefreshInterval)
+	})
 
 	handler := handlers.NewHandler(
 		bookService,
@@ -126,10 +577,157 @@ func Run(configDir string) {
 	)
 
 	router := handler.InitRoutes()
+	similar.NewHandler(similarBookService).InitRoutes(router)
+	readinglist.NewHandler(readingListService, tokenManager).InitRoutes(router)
+	favorite.NewHandler(favoriteBookService, tokenManager).InitRoutes(router)
+	account.NewHandler(accountService, tokenManager).InitRoutes(router)
+	passwordreset.NewHandler(passwordResetService).InitRoutes(router)
+	session.NewHandler(session.NewSessionService(sessionStore, tokenManager, logger), tokenManager).InitRoutes(router)
+	jwks.NewHandler(tokenManager).InitRoutes(router)
+	twofactor.NewHandler(twoFactorService, tokenManager).InitRoutes(router)
+	softdelete.NewHandler(deletedBookService, tokenManager, cfg.BookDeletion.RestoreRoles).InitRoutes(router)
+	softdelete.NewReaderHandler(deletedReaderService, tokenManager, cfg.ReaderDeletion.RestoreRoles).InitRoutes(router)
+
+	// сведения об устройстве нужны репозиторию сессий и внутри внешних обработчиков,
+	// поэтому они кладутся в контекст запроса до роутера
+	router.ContextWithFallback = true
 
-	err = router.Run(":" + cfg.Port)
+	accessTokenTracker := session.NewAccessTokenTracker(sessionStore, tokenManager, logger)
+	passwordRehasher := account.NewPasswordRehasher(readerRepo, accountRepo, hasher, logger)
+
+	trustedProxies, err := session.ParseTrustedProxies(cfg.Server.TrustedProxies)
 	if err != nil {
-		logger.Errorf("error running server: %v", err)
-		return
+		logger.Errorf("error parsing trusted proxies: %v", err)
+		return err
+	}
+
+	routeMatcher := middleware.NewRouteMatcher(router.Routes())
+	middlewares := []gin.HandlerFunc{
+		session.ClientIPMiddleware(trustedProxies),
+		middleware.IdentifyClient(tokenManager),
+		tracing.Middleware(tracerProvider, routeMatcher),
+		middleware.RequestID(routeMatcher, logger),
+		appMetrics.HTTPMiddleware(routeMatcher),
+	}
+	rateLimitPolicies := ratelimit.NewPolicyStore(toRateLimitPolicy(cfg.RateLimit))
+	middlewares = append(middlewares, ratelimit.Middleware(ratelimit.NewRedisLimiter(client, logger),
+		rateLimitPolicies, logger))
+
+	if readFromReplicas {
+		middlewares = append(middlewares, replication.Middleware(replication.NewRedisPinStore(client, logger),
+			cfg.Postgres.Replication.Stickiness))
+	}
+	// конфликт версий заменяется на 409 снаружи идемпотентности: ответ с ошибкой
+	// не сохраняется под ключом, и запрос можно повторить с тем же ключом
+	middlewares = append(middlewares, optimistic.Middleware())
+	if idempotencyCfg := cfg.Idempotency; idempotencyCfg.Enabled {
+		middlewares = append(middlewares, idempotency.Middleware(idempotency.NewRedisRecordStore(client, logger),
+			routeMatcher, idempotencyCfg.Window, logger))
 	}
+	middlewares = append(middlewares, conditional.Middleware(versionStore, versionRepo, routeMatcher, logger))
+
+	// уровень логирования и лимиты запросов применяются без перезапуска, остальные
+	// параметры (подключения к БД, ключи, таймауты) читаются только при старте
+	configLoader.Watch(func(newCfg *config.Config) {
+		if err := logging.SetLevel(logger, newCfg.Logging.Level); err != nil {
+			logger.Errorf("error setting log level: %v", err)
+		}
+		rateLimitPolicies.Store(toRateLimitPolicy(newCfg.RateLimit))
+
+		logger.WithFields(logrus.Fields{
+			"log_level":          newCfg.Logging.Level,
+			"rate_limit_enabled": newCfg.RateLimit.Enabled,
+		}).Info("config reloaded")
+	}, func(err error) {
+		logger.Errorf("config change rejected: %v", err)
+	})
+
+	server := newServer(session.WithRequestInfo(accessTokenTracker.Wrap(signInThrottle.Wrap(twofactor.SignInGuard(passwordRehasher.Wrap(router))))), middlewares...)
+	server.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	health.NewHandler(checker).InitRoutes(server)
+
+	serverCfg := cfg.Server
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           server,
+		ReadHeaderTimeout: serverCfg.ReadHeaderTimeout,
+		ReadTimeout:       serverCfg.ReadTimeout,
+		WriteTimeout:      serverCfg.WriteTimeout,
+		IdleTimeout:       serverCfg.IdleTimeout,
+	}
+
+	return lc.Serve(httpServer)
+}
+
+// loadSigningKeys загружает ключи подписи access-токенов. Если ключи не
+// заданы в конфигурации, используется один HMAC-ключ из JWT_SIGNING_KEY
+func loadSigningKeys(cfg config.JWTConfig) ([]*token.Key, error) {
+	if len(cfg.Keys) == 0 {
+		key, err := token.NewHMACKey("default", []byte(cfg.SigningKey))
+		if err != nil {
+			return nil, err
+		}
+		return []*token.Key{key}, nil
+	}
+
+	keys := make([]*token.Key, len(cfg.Keys))
+	for i, keyCfg := range cfg.Keys {
+		key, err := token.LoadKey(token.KeyConfig{
+			ID:             keyCfg.ID,
+			Algorithm:      keyCfg.Algorithm,
+			SecretEnv:      keyCfg.SecretEnv,
+			PrivateKeyFile: keyCfg.PrivateKeyFile,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", keyCfg.ID, err)
+		}
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+// newServer ставит общие gin-middleware перед роутером. Маршруты внешнего
+// модуля уже зарегистрированы, и router.Use на них не действует, поэтому
+// роутер подключается к отдельному движку как обработчик всех путей
+func newServer(handler http.Handler, middlewares ...gin.HandlerFunc) *gin.Engine {
+	server := gin.New()
+	server.Use(middlewares...)
+	server.NoRoute(gin.WrapH(handler))
+
+	return server
+}
+
+// toRateLimitPolicy переводит конфигурацию в политику; при выключенном
+// ограничении возвращается пустая политика, которая пропускает все запросы
+func toRateLimitPolicy(cfg config.RateLimitConfig) *ratelimit.Policy {
+	if !cfg.Enabled {
+		return &ratelimit.Policy{}
+	}
+
+	toLimit := func(value config.RateLimitValueConfig) ratelimit.Limit {
+		return ratelimit.Limit{Rate: value.Rate, Burst: value.Burst}
+	}
+	toLimits := func(values map[string]config.RateLimitValueConfig) map[string]ratelimit.Limit {
+		limits := make(map[string]ratelimit.Limit, len(values))
+		for role, value := range values {
+			limits[strings.ToLower(role)] = toLimit(value)
+		}
+		return limits
+	}
+
+	policy := &ratelimit.Policy{
+		Default: toLimit(cfg.Default),
+		Roles:   toLimits(cfg.Roles),
+		Rules:   make([]ratelimit.Rule, len(cfg.Groups)),
+	}
+	for i, group := range cfg.Groups {
+		policy.Rules[i] = ratelimit.Rule{
+			Prefix: group.Prefix,
+			Limit:  ratelimit.Limit{Rate: group.Rate, Burst: group.Burst},
+			Roles:  toLimits(group.Roles),
+		}
+	}
+
+	return policy
 }
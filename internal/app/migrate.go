@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	repoMongo "github.com/nikitalystsev/BookSmart-repo-mongo"
+	repoPostgres "github.com/nikitalystsev/BookSmart-repo-postgres"
+	"github.com/nikitalystsev/BookSmart/internal/config"
+	"github.com/nikitalystsev/BookSmart/internal/migration"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"strconv"
+)
+
+var errMigrateUsage = errors.New("usage: app migrate up|down|status|to N [flags]")
+
+// Migrate выполняет команду `app migrate`: up применяет все миграции, down
+// откатывает последнюю, to N приводит схему к версии N (0 - откатить все),
+// status печатает версию схемы. После команды и ее аргумента можно передать
+// те же флаги конфигурации, что и приложению
+func Migrate(configDir string, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	command, args := args[0], args[1:]
+
+	var version uint64
+	switch command {
+	case "up", "down", "status":
+	case "to":
+		if len(args) == 0 {
+			return errMigrateUsage
+		}
+		var err error
+		if version, err = strconv.ParseUint(args[0], 10, 32); err != nil {
+			return fmt.Errorf("invalid migration version %q: %w", args[0], err)
+		}
+		args = args[1:]
+	default:
+		return errMigrateUsage
+	}
+
+	cfg, err := config.Init(configDir, args)
+	if err != nil {
+		return fmt.Errorf("error initializing config: %w", err)
+	}
+
+	logger, err := newLogger(cfg.Logging)
+	if err != nil {
+		return fmt.Errorf("error initializing logger: %w", err)
+	}
+
+	migrator, closeDB, err := openMigrator(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	defer migrator.Close()
+
+	switch command {
+	case "up":
+		return migrator.Up()
+	case "down":
+		return migrator.Down()
+	case "to":
+		return migrator.To(uint(version))
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s schema version: %d (latest %d)", cfg.DBType, status.Version, status.Latest)
+	if status.Dirty {
+		fmt.Print(", dirty")
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// openMigrator подключается к БД из конфигурации и создает для нее мигратор.
+// closeDB закрывает подключение
+func openMigrator(cfg *config.Config, logger *logrus.Entry) (*migration.Migrator, func(), error) {
+	switch cfg.DBType {
+	case config.DBTypePostgres:
+		db, err := repoPostgres.NewClient(postgresDSN(cfg.Postgres, cfg.Postgres.Host, cfg.Postgres.Port))
+		if err != nil {
+			logger.Errorf("error connect to postgres: %v", err)
+			return nil, nil, err
+		}
+
+		migrator, err := migration.NewPostgresMigrator(db.DB, logger)
+		if err != nil {
+			_ = db.Close()
+			logger.Errorf("error initializing migrator: %v", err)
+			return nil, nil, err
+		}
+
+		return migrator, func() { _ = db.Close() }, nil
+	case config.DBTypeMongo:
+		client, err := repoMongo.NewClient(cfg.Mongo.URI, cfg.Mongo.Username, cfg.Mongo.Password, cfg.Mongo.DBName)
+		if err != nil {
+			logger.Errorf("error connect to mongo: %v", err)
+			return nil, nil, err
+		}
+		disconnect := func() { _ = client.Disconnect(context.Background()) }
+
+		migrator, err := migration.NewMongoMigrator(client, cfg.Mongo.DBName, logger)
+		if err != nil {
+			disconnect()
+			logger.Errorf("error initializing migrator: %v", err)
+			return nil, nil, err
+		}
+
+		return migrator, disconnect, nil
+	}
+
+	return nil, nil, fmt.Errorf("db type %s has no schema migrations", cfg.DBType)
+}
+
+// prepareSchema применяет миграции, если это разрешено конфигурацией, и
+// проверяет, что версия схемы совпадает с приложением
+func prepareSchema(migrator *migration.Migrator, migrationsCfg config.MigrationsConfig, logger *logrus.Entry) error {
+	defer func() {
+		if err := migrator.Close(); err != nil {
+			logger.Warnf("error closing migrator: %v", err)
+		}
+	}()
+
+	if migrationsCfg.Auto {
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	}
+
+	if err := migrator.Check(); err != nil {
+		logger.Errorf("%v; run `app migrate up` or enable migrations.auto", err)
+		return err
+	}
+
+	return nil
+}
+
+func postgresDSN(postgresCfg config.PostgresConfig, host, port string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
+		host, port, postgresCfg.Username, postgresCfg.DBName, postgresCfg.Password, postgresCfg.SSLMode)
+}
+
+func newLogger(loggingCfg config.LoggingConfig) (*logrus.Entry, error) {
+	return logging.NewLogger(logging.Options{
+		Level:            loggingCfg.Level,
+		Dir:              loggingCfg.Dir,
+		Stdout:           loggingCfg.Stdout,
+		MaxSize:          loggingCfg.Rotation.MaxSizeMB << 20,
+		RotationInterval: loggingCfg.Rotation.Interval,
+		MaxBackups:       loggingCfg.Rotation.MaxBackups,
+	})
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+	"time"
+)
+
+const (
+	keyPrefix = "cache:"
+	// refreshTimeout ограничивает фоновое обновление устаревшей записи
+	refreshTimeout = 5 * time.Second
+)
+
+// TTL задает время жизни записи: Fresh - запись считается свежей, еще
+// Stale после этого она отдается клиентам, пока в фоне загружается новая
+type TTL struct {
+	Fresh time.Duration
+	Stale time.Duration
+}
+
+type entry struct {
+	Value      json.RawMessage `json:"v"`
+	FreshUntil int64           `json:"fresh"`
+	StaleUntil int64           `json:"stale"`
+}
+
+// Cache - кэш в Redis, общий для всех экземпляров приложения. Записи
+// группируются в хэши, чтобы связанные запросы (например, все страницы
+// отзывов книги) сбрасывались одной командой. Одновременные промахи по одному
+// ключу внутри экземпляра объединяются в один запрос к БД. Ошибки Redis не
+// мешают работе: данные в этом случае читаются из БД. Запись, загруженная
+// одновременно с изменением данных, может остаться устаревшей до истечения TTL.Fresh
+type Cache struct {
+	client *redis.Client
+	group  singleflight.Group
+	logger *logrus.Entry
+}
+
+func New(client *redis.Client, logger *logrus.Entry) *Cache {
+	return &Cache{client: client, logger: logger}
+}
+
+// getOrLoad возвращает запись field хэша key или загружает ее через load.
+// В транзакции кэш не используется: решения внутри нее должны приниматься
+// по актуальным данным
+func getOrLoad[T any](ctx context.Context, c *Cache, key, field string, ttl TTL, load func(context.Context) (T, error)) (T, error) {
+	if trmcontext.DefaultManager.Default(ctx) != nil {
+		return load(ctx)
+	}
+
+	var value T
+
+	now := time.Now()
+	cached, ok := c.get(ctx, key, field, now)
+	if ok && json.Unmarshal(cached.Value, &value) == nil {
+		if now.UnixNano() > cached.FreshUntil {
+			c.refresh(ctx, key, field, ttl, func(ctx context.Context) (any, error) { return load(ctx) })
+		}
+		return value, nil
+	}
+
+	loaded, err, _ := c.group.Do(key+"|"+field, func() (any, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		c.set(ctx, key, field, ttl, value)
+		return value, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	return loaded.(T), nil
+}
+
+// refresh обновляет устаревшую запись в фоне; повторные вызовы до окончания
+// обновления ничего не делают
+func (c *Cache) refresh(ctx context.Context, key, field string, ttl TTL, load func(context.Context) (any, error)) {
+	c.group.DoChan("refresh|"+key+"|"+field, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if err != nil {
+			logging.FromContext(ctx, c.logger).Warnf("error refreshing cache entry %s: %v", key, err)
+			return nil, err
+		}
+		c.set(ctx, key, field, ttl, value)
+		return value, nil
+	})
+}
+
+func (c *Cache) get(ctx context.Context, key, field string, now time.Time) (*entry, bool) {
+	data, err := c.client.HGet(ctx, keyPrefix+key, field).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false
+	}
+	if err != nil {
+		logging.FromContext(ctx, c.logger).Errorf("error getting cache entry: %v", err)
+		return nil, false
+	}
+
+	var cached entry
+	if err = json.Unmarshal(data, &cached); err != nil || now.UnixNano() > cached.StaleUntil {
+		return nil, false
+	}
+
+	return &cached, true
+}
+
+func (c *Cache) set(ctx context.Context, key, field string, ttl TTL, value any) {
+	logger := logging.FromContext(ctx, c.logger)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Errorf("error encoding cache entry: %v", err)
+		return
+	}
+
+	now := time.Now()
+	data, err = json.Marshal(entry{
+		Value:      data,
+		FreshUntil: now.Add(ttl.Fresh).UnixNano(),
+		StaleUntil: now.Add(ttl.Fresh + ttl.Stale).UnixNano(),
+	})
+	if err != nil {
+		logger.Errorf("error encoding cache entry: %v", err)
+		return
+	}
+
+	// срок жизни хэша продлевается каждой записью, поэтому для каждой записи
+	// отдельно хранится и проверяется при чтении свой срок
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, keyPrefix+key, field, data)
+		pipe.Expire(ctx, keyPrefix+key, ttl.Fresh+ttl.Stale)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error setting cache entry: %v", err)
+	}
+}
+
+// Invalidate удаляет группы записей. Внутри транзакции удаление откладывается
+// до ее завершения (см. NewTransactionManager), иначе параллельный запрос
+// успел бы закэшировать данные, которые транзакция еще не зафиксировала
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) {
+	if pending, ok := ctx.Value(pendingKey{}).(*pendingInvalidation); ok {
+		pending.add(keys...)
+		return
+	}
+
+	c.invalidate(ctx, keys...)
+}
+
+func (c *Cache) invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		logging.FromContext(ctx, c.logger).Errorf("error invalidating cache: %v", err)
+	}
+}
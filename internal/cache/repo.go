@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+)
+
+const (
+	bookKeyPrefix    = "book:"
+	bookListsKey     = "books"
+	ratingsKeyPrefix = "ratings:"
+	entryField       = "v"
+)
+
+// BookRepo кэширует книгу по идентификатору и результаты поиска. Поиск
+// зависит от любой книги, поэтому любое изменение книг сбрасывает его целиком
+type BookRepo struct {
+	next    intfRepo.IBookRepo
+	cache   *Cache
+	bookTTL TTL
+	listTTL TTL
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, cache *Cache, bookTTL, listTTL TTL) intfRepo.IBookRepo {
+	return &BookRepo{next: next, cache: cache, bookTTL: bookTTL, listTTL: listTTL}
+}
+
+func (r *BookRepo) Create(ctx context.Context, book *models.BookModel) error {
+	if err := r.next.Create(ctx, book); err != nil {
+		return err
+	}
+	r.cache.Invalidate(ctx, bookKeyPrefix+book.ID.String(), bookListsKey)
+
+	return nil
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	return getOrLoad(ctx, r.cache, bookKeyPrefix+ID.String(), entryField, r.bookTTL,
+		func(ctx context.Context) (*models.BookModel, error) { return r.next.GetByID(ctx, ID) })
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (*models.BookModel, error) {
+	return getOrLoad(ctx, r.cache, bookListsKey, "title:"+title, r.listTTL,
+		func(ctx context.Context) (*models.BookModel, error) { return r.next.GetByTitle(ctx, title) })
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	if err := r.next.Delete(ctx, ID); err != nil {
+		return err
+	}
+	r.cache.Invalidate(ctx, bookKeyPrefix+ID.String(), bookListsKey)
+
+	return nil
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) error {
+	if err := r.next.Update(ctx, book); err != nil {
+		return err
+	}
+	r.cache.Invalidate(ctx, bookKeyPrefix+book.ID.String(), bookListsKey)
+
+	return nil
+}
+
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	field, err := json.Marshal(params)
+	if err != nil {
+		return r.next.GetByParams(ctx, params)
+	}
+
+	return getOrLoad(ctx, r.cache, bookListsKey, "params:"+string(field), r.listTTL,
+		func(ctx context.Context) ([]*models.BookModel, error) { return r.next.GetByParams(ctx, params) })
+}
+
+// InvalidateBook сбрасывает книгу и результаты поиска после изменения книги в
+// обход репозитория (например, восстановления удаленной книги)
+func (c *Cache) InvalidateBook(ctx context.Context, bookID uuid.UUID) {
+	c.Invalidate(ctx, bookKeyPrefix+bookID.String(), bookListsKey)
+}
+
+// RatingRepo кэширует отзывы книги; все запросы по одной книге лежат в одной
+// группе и сбрасываются при добавлении отзыва к ней
+type RatingRepo struct {
+	next  intfRepo.IRatingRepo
+	cache *Cache
+	ttl   TTL
+}
+
+func NewRatingRepo(next intfRepo.IRatingRepo, cache *Cache, ttl TTL) intfRepo.IRatingRepo {
+	return &RatingRepo{next: next, cache: cache, ttl: ttl}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, rating *models.RatingModel) error {
+	if err := r.next.Create(ctx, rating); err != nil {
+		return err
+	}
+	r.cache.Invalidate(ctx, ratingsKeyPrefix+rating.BookID.String())
+
+	return nil
+}
+
+func (r *RatingRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (*models.RatingModel, error) {
+	return getOrLoad(ctx, r.cache, ratingsKeyPrefix+bookID.String(), "reader:"+readerID.String(), r.ttl,
+		func(ctx context.Context) (*models.RatingModel, error) {
+			return r.next.GetByReaderAndBook(ctx, readerID, bookID)
+		})
+}
+
+func (r *RatingRepo) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]*models.RatingModel, error) {
+	return getOrLoad(ctx, r.cache, ratingsKeyPrefix+bookID.String(), fmt.Sprintf("page:%d:%d", limit, offset), r.ttl,
+		func(ctx context.Context) ([]*models.RatingModel, error) {
+			return r.next.GetByBookID(ctx, bookID, limit, offset)
+		})
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"sync"
+)
+
+type pendingKey struct{}
+
+type pendingInvalidation struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (p *pendingInvalidation) add(keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, keys...)
+}
+
+// TransactionManager сбрасывает записи кэша, затронутые транзакцией, после
+// ее завершения. Сброс выполняется и при откате: лишний промах безопаснее
+// устаревших данных
+type TransactionManager struct {
+	cache *Cache
+	next  transact.ITransactionManager
+}
+
+func NewTransactionManager(next transact.ITransactionManager, cache *Cache) transact.ITransactionManager {
+	return &TransactionManager{cache: cache, next: next}
+}
+
+func (tm *TransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	// во вложенной транзакции сброс выполнит внешняя
+	if _, ok := ctx.Value(pendingKey{}).(*pendingInvalidation); ok {
+		return tm.next.Do(ctx, fn)
+	}
+
+	pending := &pendingInvalidation{}
+	err := tm.next.Do(context.WithValue(ctx, pendingKey{}, pending), fn)
+	tm.cache.invalidate(context.WithoutCancel(ctx), pending.keys...)
+
+	return err
+}
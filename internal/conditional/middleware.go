@@ -0,0 +1,276 @@
+package conditional
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ErrMsgPreconditionFailed = "resource has been modified"
+	ErrMsgPreconditionCheck  = "error checking precondition"
+)
+
+// resourceRule задает ресурс маршрута: prefix и значение параметра пути param.
+// Для сущностей с версией в БД (entity) ETag строится по этой версии
+type resourceRule struct {
+	prefix string
+	param  string
+	entity string
+}
+
+// readRules - маршруты чтения, ответ которых определяется версией ресурса
+var readRules = map[string]resourceRule{
+	"/api/v1/books":                                    {prefix: booksResource},
+	"/api/v1/books/:id":                                {prefix: bookResourcePrefix, param: "id", entity: optimistic.EntityBook},
+	"/api/v1/books/:id/ratings":                        {prefix: ratingsResourcePrefix, param: "id"},
+	"/api/v1/books/:id/ratings/avg":                    {prefix: ratingsResourcePrefix, param: "id"},
+	"/api/v1/readers/:id/reservations":                 {prefix: reservationsResourcePrefix, param: "id"},
+	"/api/v1/readers/:id/reservations/:reservation_id": {prefix: reservationResourcePrefix, param: "reservation_id", entity: optimistic.EntityReservation},
+}
+
+// writeRules - маршруты записи, принимающие If-Match. ETag ресурса для них -
+// ETag ответа на GET того же пути без параметров запроса
+var writeRules = map[string]resourceRule{
+	"/api/v1/books/:id": {prefix: bookResourcePrefix, param: "id", entity: optimistic.EntityBook},
+	"/api/v1/readers/:id/reservations/:reservation_id": {prefix: reservationResourcePrefix, param: "reservation_id", entity: optimistic.EntityReservation},
+}
+
+// Middleware добавляет к ответам на чтение ресурсов сильный ETag и
+// Last-Modified и отвечает 304 на If-None-Match и If-Modified-Since.
+// Обработчик чтения выполняется всегда, чтобы не обходить его проверки
+// доступа: экономится только передача ответа. ETag книги и брони строится по
+// версии строки в БД, а If-Match задает ожидаемую версию для записи
+// (optimistic.Expect): запись с устаревшей версией отклоняется проверкой
+// версии в той же транзакции и получает 412
+func Middleware(store IVersionStore, versions optimistic.IVersionRepo, routes *middleware.RouteMatcher, logger *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := routes.Match(c.Request.Method, c.Request.URL.Path)
+
+		switch c.Request.Method {
+		case http.MethodGet:
+			if rule, ok := readRules[route]; ok {
+				serveRead(c, store, versions, rule, route)
+				return
+			}
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if rule, ok := writeRules[route]; ok {
+				serveWrite(c, versions, rule, route, logger)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func serveRead(c *gin.Context, store IVersionStore, versions optimistic.IVersionRepo, rule resourceRule, route string) {
+	ctx := c.Request.Context()
+	resource := rule.resource(route, c.Request.URL.Path)
+
+	// версия читается до обработчика: если ресурс изменится во время запроса,
+	// клиент получит новые данные со старым ETag и при следующем запросе
+	// просто скачает их еще раз. Неизвестная версия создается после ответа,
+	// чтобы не заводить версии для несуществующих ресурсов
+	modified, isKnown, err := store.Get(ctx, resource)
+	if err != nil {
+		c.Next()
+		return
+	}
+
+	var (
+		ID      uuid.UUID
+		version int64
+	)
+	if rule.entity != "" {
+		var found bool
+		if ID, version, found, err = rule.version(ctx, versions, route, c.Request.URL.Path); err != nil || !found {
+			c.Next()
+			return
+		}
+		// версия, прочитанная обработчиком вместе с данными, точнее: данные
+		// могут прийти из реплики
+		ctx = optimistic.WithTracking(ctx)
+		c.Request = c.Request.WithContext(ctx)
+	}
+
+	writer := middleware.NewBufferedWriter(c.Writer)
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	if writer.Status() != http.StatusOK {
+		writer.Send()
+		return
+	}
+	if !isKnown {
+		_, _ = store.Ensure(ctx, resource)
+		writer.Send()
+		return
+	}
+
+	tag := entityTag(resource, strconv.FormatInt(modified.UnixNano(), 10), c.Request.URL.RawQuery)
+	if rule.entity != "" {
+		if tracked, ok := optimistic.Version(ctx, rule.entity, ID); ok {
+			version = tracked
+		}
+		tag = versionTag(resource, version, c.Request.URL.RawQuery)
+	}
+	setValidators(writer.Header(), tag, modified)
+
+	if isNotModified(c.Request, tag, modified) {
+		writer.Header().Del("Content-Length")
+		writer.ResponseWriter.WriteHeader(http.StatusNotModified)
+		writer.ResponseWriter.WriteHeaderNow()
+		return
+	}
+
+	writer.Send()
+}
+
+func serveWrite(c *gin.Context, versions optimistic.IVersionRepo, rule resourceRule, route string, logger *logrus.Entry) {
+	ctx := c.Request.Context()
+	resource := rule.resource(route, c.Request.URL.Path)
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.Next()
+		return
+	}
+
+	ID, version, found, err := rule.version(ctx, versions, route, c.Request.URL.Path)
+	if err != nil {
+		logging.FromContext(ctx, logger).Errorf("error checking If-Match: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{ErrorMsg: ErrMsgPreconditionCheck})
+		return
+	}
+	if !found || !matchTags(ifMatch, versionTag(resource, version, ""), true) {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, dto.ErrorResponse{ErrorMsg: ErrMsgPreconditionFailed})
+		return
+	}
+
+	// запись пройдет, только если версия в БД все еще равна проверенной:
+	// параллельный запрос с тем же ETag получит 412
+	ctx = optimistic.Expect(ctx, rule.entity, ID, version)
+	c.Request = c.Request.WithContext(ctx)
+
+	writer := middleware.NewBufferedWriter(c.Writer)
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	if optimistic.PreconditionFailed(ctx) {
+		c.JSON(http.StatusPreconditionFailed, dto.ErrorResponse{ErrorMsg: ErrMsgPreconditionFailed})
+		return
+	}
+
+	// новый ETag позволяет клиенту сразу отправить следующее изменение
+	if status := writer.Status(); status >= 200 && status < 300 && c.Request.Method != http.MethodDelete {
+		if version, ok := optimistic.Version(ctx, rule.entity, ID); ok {
+			writer.Header().Set("ETag", versionTag(resource, version, ""))
+		}
+	}
+
+	writer.Send()
+}
+
+func (r resourceRule) resource(route, path string) string {
+	return r.prefix + r.value(route, path)
+}
+
+// value возвращает значение параметра пути param
+func (r resourceRule) value(route, path string) string {
+	if r.param == "" {
+		return ""
+	}
+
+	routeSegments, pathSegments := strings.Split(route, "/"), strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, segment := range routeSegments {
+		if segment == ":"+r.param && i < len(pathSegments) {
+			return pathSegments[i]
+		}
+	}
+
+	return ""
+}
+
+// version читает версию сущности ресурса из мастера. Некорректный
+// идентификатор означает, что сущности нет
+func (r resourceRule) version(ctx context.Context, versions optimistic.IVersionRepo, route, path string) (uuid.UUID, int64, bool, error) {
+	ID, err := uuid.Parse(r.value(route, path))
+	if err != nil {
+		return uuid.Nil, 0, false, nil
+	}
+
+	_, version, found, err := versions.Get(ctx, r.entity, optimistic.FieldID, ID)
+	return ID, version, found, err
+}
+
+// versionTag строит ETag по версии сущности в БД
+func versionTag(resource string, version int64, query string) string {
+	return entityTag(resource, "v"+strconv.FormatInt(version, 10), query)
+}
+
+// entityTag строит сильный ETag: одинаковые версия ресурса и параметры
+// запроса дают побайтно одинаковый ответ
+func entityTag(resource, version, query string) string {
+	sum := sha256.Sum256([]byte(resource + "\n" + version + "\n" + query))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+func setValidators(header http.Header, tag string, version time.Time) {
+	header.Set("ETag", tag)
+	header.Set("Last-Modified", version.UTC().Format(http.TimeFormat))
+}
+
+// isNotModified проверяет условия GET по RFC 9110: If-Modified-Since
+// учитывается, только если нет If-None-Match
+func isNotModified(r *http.Request, tag string, version time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchTags(ifNoneMatch, tag, false)
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !version.Truncate(time.Second).After(since)
+}
+
+// matchTags сравнивает tag со списком из заголовка. При строгом сравнении
+// (If-Match) слабые ETag не совпадают ни с чем, при слабом (If-None-Match)
+// префикс W/ не учитывается
+func matchTags(header, tag string, strong bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		weakTag, isWeak := strings.CutPrefix(candidate, "W/")
+		if isWeak {
+			if strong {
+				continue
+			}
+			candidate = weakTag
+		}
+
+		if candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package conditional
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+)
+
+// ресурсы, версии которых определяют ETag и Last-Modified ответов
+const (
+	booksResource              = "books"
+	bookResourcePrefix         = "book:"
+	ratingsResourcePrefix      = "ratings:"
+	reservationResourcePrefix  = "reservation:"
+	reservationsResourcePrefix = "reservations:"
+)
+
+// BookRepo обновляет версию книги и каталога при изменении книги; каталог
+// зависит от любой книги, поэтому у него одна версия на все запросы
+type BookRepo struct {
+	intfRepo.IBookRepo
+	store IVersionStore
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, store IVersionStore) intfRepo.IBookRepo {
+	return &BookRepo{IBookRepo: next, store: store}
+}
+
+func (r *BookRepo) Create(ctx context.Context, book *models.BookModel) error {
+	if err := r.IBookRepo.Create(ctx, book); err != nil {
+		return err
+	}
+	touch(ctx, r.store, bookResourcePrefix+book.ID.String(), booksResource)
+
+	return nil
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	if err := r.IBookRepo.Delete(ctx, ID); err != nil {
+		return err
+	}
+	touch(ctx, r.store, bookResourcePrefix+ID.String(), booksResource)
+
+	return nil
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) error {
+	if err := r.IBookRepo.Update(ctx, book); err != nil {
+		return err
+	}
+	touch(ctx, r.store, bookResourcePrefix+book.ID.String(), booksResource)
+
+	return nil
+}
+
+// TouchBook обновляет версию книги и каталога после изменения книги в обход
+// репозитория (например, восстановления удаленной книги)
+func TouchBook(ctx context.Context, store IVersionStore, bookID uuid.UUID) {
+	touch(ctx, store, bookResourcePrefix+bookID.String(), booksResource)
+}
+
+// RatingRepo обновляет версию отзывов книги и их агрегатов при добавлении отзыва
+type RatingRepo struct {
+	intfRepo.IRatingRepo
+	store IVersionStore
+}
+
+func NewRatingRepo(next intfRepo.IRatingRepo, store IVersionStore) intfRepo.IRatingRepo {
+	return &RatingRepo{IRatingRepo: next, store: store}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, rating *models.RatingModel) error {
+	if err := r.IRatingRepo.Create(ctx, rating); err != nil {
+		return err
+	}
+	touch(ctx, r.store, ratingsResourcePrefix+rating.BookID.String())
+
+	return nil
+}
+
+// ReservationRepo обновляет версию брони и списка броней читателя
+type ReservationRepo struct {
+	intfRepo.IReservationRepo
+	store IVersionStore
+}
+
+func NewReservationRepo(next intfRepo.IReservationRepo, store IVersionStore) intfRepo.IReservationRepo {
+	return &ReservationRepo{IReservationRepo: next, store: store}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, reservation *models.ReservationModel) error {
+	if err := r.IReservationRepo.Create(ctx, reservation); err != nil {
+		return err
+	}
+	touch(ctx, r.store, reservationResourcePrefix+reservation.ID.String(),
+		reservationsResourcePrefix+reservation.ReaderID.String())
+
+	return nil
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, reservation *models.ReservationModel) error {
+	if err := r.IReservationRepo.Update(ctx, reservation); err != nil {
+		return err
+	}
+	touch(ctx, r.store, reservationResourcePrefix+reservation.ID.String(),
+		reservationsResourcePrefix+reservation.ReaderID.String())
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package conditional
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"sync"
+)
+
+type pendingKey struct{}
+
+type pendingTouches struct {
+	mu        sync.Mutex
+	resources []string
+}
+
+func (p *pendingTouches) add(resources ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resources = append(p.resources, resources...)
+}
+
+// touch отмечает изменение ресурсов. Внутри транзакции отметка откладывается
+// до ее завершения: иначе параллельный запрос получил бы новый ETag вместе
+// со старыми данными и закэшировал бы их у клиента
+func touch(ctx context.Context, store IVersionStore, resources ...string) {
+	if pending, ok := ctx.Value(pendingKey{}).(*pendingTouches); ok {
+		pending.add(resources...)
+		return
+	}
+
+	_ = store.Touch(ctx, resources...)
+}
+
+// TransactionManager отмечает изменение ресурсов, затронутых транзакцией,
+// после ее завершения. Отметка выполняется и при откате: лишний ответ
+// целиком безопаснее ответа 304 на измененные данные
+type TransactionManager struct {
+	store IVersionStore
+	next  transact.ITransactionManager
+}
+
+func NewTransactionManager(next transact.ITransactionManager, store IVersionStore) transact.ITransactionManager {
+	return &TransactionManager{store: store, next: next}
+}
+
+func (tm *TransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	// во вложенной транзакции отметку выполнит внешняя
+	if _, ok := ctx.Value(pendingKey{}).(*pendingTouches); ok {
+		return tm.next.Do(ctx, fn)
+	}
+
+	pending := &pendingTouches{}
+	err := tm.next.Do(context.WithValue(ctx, pendingKey{}, pending), fn)
+	_ = tm.store.Touch(context.WithoutCancel(ctx), pending.resources...)
+
+	return err
+}
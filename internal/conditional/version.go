@@ -0,0 +1,101 @@
+package conditional
+
+import (
+	"context"
+	"errors"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+const (
+	versionKeyPrefix = "conditional:version:"
+	// versionTTL - срок хранения версии ресурса без изменений; после него
+	// версия создается заново, и клиенты один раз получат ответ целиком
+	versionTTL = 30 * 24 * time.Hour
+)
+
+// IVersionStore хранит время последнего изменения ресурсов. Модели внешнего
+// модуля сервисов не содержат updated_at, поэтому версия ведется отдельно и
+// обновляется декораторами репозиториев при каждой записи
+type IVersionStore interface {
+	// Get возвращает версию ресурса; ok = false, если версия неизвестна
+	Get(ctx context.Context, resource string) (version time.Time, ok bool, err error)
+	// Ensure возвращает версию ресурса, создавая ее, если она неизвестна
+	Ensure(ctx context.Context, resource string) (time.Time, error)
+	// Touch отмечает изменение ресурсов
+	Touch(ctx context.Context, resources ...string) error
+}
+
+type RedisVersionStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisVersionStore(client *redis.Client, logger *logrus.Entry) IVersionStore {
+	return &RedisVersionStore{client: client, logger: logger}
+}
+
+func (s *RedisVersionStore) Get(ctx context.Context, resource string) (time.Time, bool, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	nanos, err := s.client.Get(ctx, versionKeyPrefix+resource).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		logger.Errorf("error getting resource version: %v", err)
+		return time.Time{}, false, err
+	}
+
+	return time.Unix(0, nanos), true, nil
+}
+
+func (s *RedisVersionStore) Ensure(ctx context.Context, resource string) (time.Time, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	now := time.Now()
+	isCreated, err := s.client.SetNX(ctx, versionKeyPrefix+resource, strconv.FormatInt(now.UnixNano(), 10), versionTTL).Result()
+	if err != nil {
+		logger.Errorf("error creating resource version: %v", err)
+		return time.Time{}, err
+	}
+	if isCreated {
+		return now, nil
+	}
+
+	version, ok, err := s.Get(ctx, resource)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		// версия истекла между SETNX и GET
+		return s.Ensure(ctx, resource)
+	}
+
+	return version, nil
+}
+
+func (s *RedisVersionStore) Touch(ctx context.Context, resources ...string) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx, s.logger)
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, resource := range resources {
+			pipe.Set(ctx, versionKeyPrefix+resource, version, versionTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error touching resource versions: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,101 +1,421 @@
-package config
-
-import (
-	"github.com/spf13/viper"
-	"os"
-	"time"
-)
-
-type Config struct {
-	Auth     AuthConfig
-	Postgres PostgresConfig
-	Redis    RedisConfig
-	Port     string
-	Mongo    MongoConfig
-	DBType   string
-}
-
-type AuthConfig struct {
-	JWT          JWTConfig
-	PasswordSalt string
-}
-
-type JWTConfig struct {
-	AccessTokenTTL  time.Duration
-	RefreshTokenTTL time.Duration
-	SigningKey      string
-}
-
-type PostgresConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
-type RedisConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DB       int
-}
-
-type MongoConfig struct {
-	URI      string
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-}
-
-func Init(configsDir string) (*Config, error) {
-	viper.AddConfigPath(configsDir)
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
-	}
-
-	var cfg Config
-	if err := viper.UnmarshalKey("auth", &cfg.Auth.JWT); err != nil {
-		return nil, err
-	}
-	if err := viper.UnmarshalKey("db.type", &cfg.DBType); err != nil {
-		return nil, err
-	}
-
-	setFromEnv(&cfg)
-
-	return &cfg, nil
-}
-
-func setFromEnv(cfg *Config) {
-	cfg.Postgres.Host = os.Getenv("POSTGRES_HOST")
-	cfg.Postgres.Port = os.Getenv("POSTGRES_PORT")
-	cfg.Postgres.DBName = os.Getenv("POSTGRES_DB_NAME")
-	cfg.Postgres.Username = os.Getenv("POSTGRES_DB_USER")
-	cfg.Postgres.Password = os.Getenv("POSTGRES_DB_PASSWORD")
-	cfg.Postgres.SSLMode = os.Getenv("POSTGRES_SSL_MODE")
-
-	cfg.Mongo.URI = os.Getenv("MONGO_URI")
-	cfg.Mongo.Host = os.Getenv("MONGO_DB_HOST")
-	cfg.Mongo.Port = os.Getenv("MONGO_DB_PORT")
-	cfg.Mongo.DBName = os.Getenv("MONGO_DB_NAME")
-	cfg.Mongo.Username = os.Getenv("MONGO_DB_USER")
-	cfg.Mongo.Password = os.Getenv("MONGO_DB_PASSWORD")
-
-	cfg.Redis.Host = os.Getenv("REDIS_HOST")
-	cfg.Redis.Port = os.Getenv("REDIS_PORT")
-	cfg.Redis.Username = os.Getenv("REDIS_USER")
-	cfg.Redis.Password = os.Getenv("REDIS_USER_PASSWORD")
-
-	cfg.Auth.PasswordSalt = os.Getenv("PASSWORD_SALT")
-	cfg.Auth.JWT.SigningKey = os.Getenv("JWT_SIGNING_KEY")
-
-	cfg.Port = os.Getenv("APP_PORT")
-}
+package config
+
+import (
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Auth     AuthConfig
+	Postgres PostgresConfig
+	Redis    RedisConfig
+	Port     string
+	Mongo    MongoConfig
+	Memory   MemoryConfig
+	DBType   DBType `mapstructure:"-"`
+
+	PasswordReset  PasswordResetConfig
+	SMS            SMSConfig
+	SignInThrottle SignInThrottleConfig
+	TwoFactor      TwoFactorConfig
+	RateLimit      RateLimitConfig
+	Tracing        TracingConfig
+	Logging        LoggingConfig
+	Server         ServerConfig
+	Startup        StartupConfig
+	Cache          CacheConfig
+	Idempotency    IdempotencyConfig
+	BookDeletion   DeletionConfig
+	ReaderDeletion DeletionConfig
+	Migrations     MigrationsConfig
+
+	// unknownKeys - параметры из файла, окружения или флагов, которых нет в
+	// конфигурации; Validate сообщает о них как об ошибках
+	unknownKeys []string
+}
+
+// DBType - хранилище данных приложения, параметр db.type
+type DBType string
+
+const (
+	DBTypePostgres DBType = "postgres"
+	DBTypeMongo    DBType = "mongo"
+	// DBTypeMemory хранит данные и Redis в памяти процесса: для локальной
+	// разработки и тестов без контейнеров с БД
+	DBTypeMemory DBType = "memory"
+)
+
+// AuthConfig - секция auth; параметры JWT лежат прямо в ней
+type AuthConfig struct {
+	JWT             JWTConfig `mapstructure:",squash"`
+	PasswordSalt    string
+	PasswordHashing PasswordHashingConfig
+}
+
+// JWTConfig - параметры access- и refresh-токенов. LegacyTokensUntil - момент
+// в формате RFC 3339, до которого принимаются токены без kid, подписанные
+// auth.signingKey; если он не задан, такие токены отклоняются
+type JWTConfig struct {
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	SigningKey        string
+	ActiveKeyID       string
+	Keys              []JWTKeyConfig
+	LegacyTokensUntil string
+}
+
+type PasswordHashingConfig struct {
+	Algorithm  string
+	Argon2     Argon2Config
+	BcryptCost int
+}
+
+type Argon2Config struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+type JWTKeyConfig struct {
+	ID             string
+	Algorithm      string
+	SecretEnv      string
+	PrivateKeyFile string
+}
+
+type PasswordResetConfig struct {
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendInterval time.Duration
+}
+
+type SignInThrottleConfig struct {
+	FailureWindow   time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Phone           SignInLimitsConfig
+	IP              SignInLimitsConfig
+}
+
+type SignInLimitsConfig struct {
+	FreeAttempts int
+	MaxFailures  int
+}
+
+type TwoFactorConfig struct {
+	EnforcedRoles   []string
+	ChallengeTTL    time.Duration
+	MaxAttempts     int
+	MaxFailures     int
+	LockoutDuration time.Duration
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Default RateLimitValueConfig
+	Roles   map[string]RateLimitValueConfig
+	Groups  []RateLimitGroupConfig
+}
+
+type RateLimitValueConfig struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitGroupConfig struct {
+	Prefix string
+	Rate   float64
+	Burst  int
+	Roles  map[string]RateLimitValueConfig
+}
+
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+	SampleRatio float64
+	OTLP        OTLPConfig
+}
+
+type OTLPConfig struct {
+	Endpoint string
+	Insecure bool
+	Headers  map[string]string
+}
+
+type LoggingConfig struct {
+	Level    string
+	Dir      string
+	Stdout   bool
+	Rotation LogRotationConfig
+}
+
+type LogRotationConfig struct {
+	MaxSizeMB  int64
+	Interval   time.Duration
+	MaxBackups int
+}
+
+// ServerConfig - секция server. TrustedProxies - сети прокси (CIDR), от которых
+// принимаются X-Forwarded-For и X-Real-IP; от остальных адресов заголовки игнорируются
+type ServerConfig struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	TrustedProxies    []string
+}
+
+type StartupConfig struct {
+	RetryAttempts     int
+	RetryInitialDelay time.Duration
+	RetryMaxDelay     time.Duration
+}
+
+type CacheConfig struct {
+	Enabled  bool
+	Book     CacheTTLConfig
+	BookList CacheTTLConfig
+	Rating   CacheTTLConfig
+}
+
+type CacheTTLConfig struct {
+	TTL      time.Duration
+	StaleTTL time.Duration
+}
+
+type IdempotencyConfig struct {
+	Enabled bool
+	Window  time.Duration
+}
+
+// MemoryConfig - настройки хранилища в памяти. Если SnapshotPath не пуст, данные
+// загружаются из этого JSON-файла при запуске и сохраняются в него при остановке
+type MemoryConfig struct {
+	SnapshotPath string
+}
+
+// DeletionConfig - секции bookDeletion и readerDeletion: мягко удаленные записи
+// удаляются окончательно через Retention, восстановить их до этого могут
+// пользователи с ролями RestoreRoles
+type DeletionConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+	RestoreRoles  []string
+}
+
+// MigrationsConfig - секция migrations. Если Auto = true, при запуске
+// применяются непримененные встроенные миграции; иначе схему обновляет
+// команда migrate up. В обоих случаях запуск прерывается, если версия
+// схемы не совпадает с приложением
+type MigrationsConfig struct {
+	Auto bool
+}
+
+type SMSConfig struct {
+	Sender   string
+	FilePath string
+}
+
+type PostgresConfig struct {
+	Host         string
+	Port         string
+	Username     string
+	Password     string
+	DBName       string
+	SSLMode      string
+	ReplicaHosts []string
+	Replication  PostgresReplicationConfig
+}
+
+type PostgresReplicationConfig struct {
+	MaxLag           time.Duration
+	LagCheckInterval time.Duration
+	Stickiness       time.Duration
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	DB       int
+}
+
+type MongoConfig struct {
+	URI      string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	DBName   string
+}
+
+// envPrefix - префикс переменных окружения для любого параметра конфигурации:
+// rateLimit.default.rate задается через BOOKSMART_RATELIMIT_DEFAULT_RATE
+const envPrefix = "BOOKSMART"
+
+// legacyEnv - прежние имена переменных окружения из .env и docker-compose.yml.
+// Они продолжают работать, но переменная с префиксом BOOKSMART_ важнее
+var legacyEnv = map[string]string{
+	"port":                  "APP_PORT",
+	"auth.signingKey":       "JWT_SIGNING_KEY",
+	"auth.passwordSalt":     "PASSWORD_SALT",
+	"logging.level":         "LOG_LEVEL",
+	"postgres.host":         "POSTGRES_HOST",
+	"postgres.port":         "POSTGRES_PORT",
+	"postgres.dbName":       "POSTGRES_DB_NAME",
+	"postgres.username":     "POSTGRES_DB_USER",
+	"postgres.password":     "POSTGRES_DB_PASSWORD",
+	"postgres.sslMode":      "POSTGRES_SSL_MODE",
+	"postgres.replicaHosts": "POSTGRES_REPLICA_HOSTS",
+	"mongo.uri":             "MONGO_URI",
+	"mongo.host":            "MONGO_DB_HOST",
+	"mongo.port":            "MONGO_DB_PORT",
+	"mongo.dbName":          "MONGO_DB_NAME",
+	"mongo.username":        "MONGO_DB_USER",
+	"mongo.password":        "MONGO_DB_PASSWORD",
+	"redis.host":            "REDIS_HOST",
+	"redis.port":            "REDIS_PORT",
+	"redis.username":        "REDIS_USER",
+	"redis.password":        "REDIS_USER_PASSWORD",
+}
+
+// Loader собирает конфигурацию из слоев: значения по умолчанию, config.yml,
+// переменные окружения и флаги командной строки. Каждый следующий слой
+// переопределяет предыдущий
+type Loader struct {
+	v *viper.Viper
+}
+
+// NewLoader читает config.yml из configsDir и разбирает флаги args:
+// --port, --db-type, --log-level и --set key=value для любого параметра
+func NewLoader(configsDir string, args []string) (*Loader, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	v.AddConfigPath(configsDir)
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for key, legacy := range legacyEnv {
+		if err := v.BindEnv(key, envName(key), legacy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := bindFlags(v, args); err != nil {
+		return nil, err
+	}
+
+	return &Loader{v: v}, nil
+}
+
+// Load собирает и проверяет конфигурацию
+func (l *Loader) Load() (*Config, error) {
+	var (
+		cfg      Config
+		metadata mapstructure.Metadata
+	)
+	if err := l.v.Unmarshal(&cfg, func(dc *mapstructure.DecoderConfig) { dc.Metadata = &metadata }); err != nil {
+		return nil, err
+	}
+	cfg.DBType = DBType(l.v.GetString("db.type"))
+	for _, key := range metadata.Unused {
+		// путь начинается с имени поля структуры, а viper хранит ключи в
+		// нижнем регистре; секция db разбирается отдельно в поле DBType
+		if key = strings.ToLower(key); key != "db" {
+			cfg.unknownKeys = append(cfg.unknownKeys, key)
+		}
+	}
+	for _, key := range l.v.AllKeys() {
+		if strings.HasPrefix(key, "db.") && key != "db.type" {
+			cfg.unknownKeys = append(cfg.unknownKeys, key)
+		}
+	}
+	slices.Sort(cfg.unknownKeys)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Watch следит за config.yml и после каждого изменения передает в onChange
+// новую проверенную конфигурацию. Если изменение не прошло проверку, оно не
+// применяется, а ошибка передается в onError
+func (l *Loader) Watch(onChange func(*Config), onError func(error)) {
+	l.v.OnConfigChange(func(fsnotify.Event) {
+		cfg, err := l.Load()
+		if err != nil {
+			onError(err)
+			return
+		}
+		onChange(cfg)
+	})
+	l.v.WatchConfig()
+}
+
+func Init(configsDir string, args []string) (*Config, error) {
+	loader, err := NewLoader(configsDir, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.Load()
+}
+
+// Redact скрывает секрет в логах, оставляя видимым только факт его наличия
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return "[REDACTED]"
+}
+
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func bindFlags(v *viper.Viper, args []string) error {
+	flags := pflag.NewFlagSet("app", pflag.ContinueOnError)
+	flags.String("port", "", "порт HTTP-сервера")
+	flags.String("db-type", "", "тип БД: postgres | mongo | memory")
+	flags.String("log-level", "", "уровень логирования")
+	sets := flags.StringArray("set", nil, "значение любого параметра, например --set rateLimit.enabled=false")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	for key, name := range map[string]string{"port": "port", "db.type": "db-type", "logging.level": "log-level"} {
+		if err := v.BindPFlag(key, flags.Lookup(name)); err != nil {
+			return err
+		}
+	}
+
+	for _, set := range *sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --set %q: expected key=value", set)
+		}
+		v.Set(key, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+// setDefaults задает значение по умолчанию для каждого параметра. Параметр,
+// отсутствующий здесь, нельзя переопределить переменной окружения с префиксом
+// BOOKSMART_, так как viper не знает о его существовании
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("port", "8000")
+	v.SetDefault("db.type", "postgres")
+
+	v.SetDefault("auth.accessTokenTTL", 2*time.Hour)
+	v.SetDefault("auth.refreshTokenTTL", 5*time.Hour)
+	v.SetDefault("auth.signingKey", "")
+	v.SetDefault("auth.activeKeyID", "")
+	v.SetDefault("auth.legacyTokensUntil", "")
+	v.SetDefault("auth.passwordSalt", "")
+	v.SetDefault("auth.passwordHashing.algorithm", "argon2id")
+	v.SetDefault("auth.passwordHashing.argon2.memory", 64*1024)
+	v.SetDefault("auth.passwordHashing.argon2.iterations", 3)
+	v.SetDefault("auth.passwordHashing.argon2.parallelism", 2)
+	v.SetDefault("auth.passwordHashing.bcryptCost", 12)
+
+	v.SetDefault("postgres.host", "")
+	v.SetDefault("postgres.port", "5432")
+	v.SetDefault("postgres.username", "")
+	v.SetDefault("postgres.password", "")
+	v.SetDefault("postgres.dbName", "")
+	v.SetDefault("postgres.sslMode", "disable")
+	v.SetDefault("postgres.replicaHosts", []string{})
+	v.SetDefault("postgres.replication.maxLag", time.Second)
+	v.SetDefault("postgres.replication.lagCheckInterval", time.Second)
+	v.SetDefault("postgres.replication.stickiness", 5*time.Second)
+
+	v.SetDefault("mongo.uri", "")
+	v.SetDefault("mongo.host", "")
+	v.SetDefault("mongo.port", "27017")
+	v.SetDefault("mongo.username", "")
+	v.SetDefault("mongo.password", "")
+	v.SetDefault("mongo.dbName", "")
+
+	v.SetDefault("memory.snapshotPath", "")
+
+	v.SetDefault("redis.host", "")
+	v.SetDefault("redis.port", "6379")
+	v.SetDefault("redis.username", "")
+	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.db", 0)
+
+	v.SetDefault("passwordReset.codeTTL", 10*time.Minute)
+	v.SetDefault("passwordReset.maxAttempts", 5)
+	v.SetDefault("passwordReset.resendInterval", time.Minute)
+
+	v.SetDefault("sms.sender", "console")
+	v.SetDefault("sms.filePath", "logs/sms.log")
+
+	v.SetDefault("signInThrottle.failureWindow", 15*time.Minute)
+	v.SetDefault("signInThrottle.baseDelay", time.Second)
+	v.SetDefault("signInThrottle.maxDelay", time.Minute)
+	v.SetDefault("signInThrottle.lockoutDuration", 15*time.Minute)
+	v.SetDefault("signInThrottle.phone.freeAttempts", 3)
+	v.SetDefault("signInThrottle.phone.maxFailures", 10)
+	v.SetDefault("signInThrottle.ip.freeAttempts", 10)
+	v.SetDefault("signInThrottle.ip.maxFailures", 50)
+
+	v.SetDefault("twoFactor.enforcedRoles", []string{})
+	v.SetDefault("twoFactor.challengeTTL", 5*time.Minute)
+	v.SetDefault("twoFactor.maxAttempts", 5)
+	v.SetDefault("twoFactor.maxFailures", 10)
+	v.SetDefault("twoFactor.lockoutDuration", 15*time.Minute)
+
+	v.SetDefault("rateLimit.enabled", true)
+	v.SetDefault("rateLimit.default.rate", 10)
+	v.SetDefault("rateLimit.default.burst", 30)
+
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.serviceName", "booksmart")
+	v.SetDefault("tracing.sampleRatio", 1.0)
+	v.SetDefault("tracing.otlp.endpoint", "")
+	v.SetDefault("tracing.otlp.insecure", true)
+
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.dir", "logs")
+	v.SetDefault("logging.stdout", true)
+	v.SetDefault("logging.rotation.maxSizeMB", 100)
+	v.SetDefault("logging.rotation.interval", 24*time.Hour)
+	v.SetDefault("logging.rotation.maxBackups", 7)
+
+	v.SetDefault("server.readHeaderTimeout", 5*time.Second)
+	v.SetDefault("server.readTimeout", 15*time.Second)
+	v.SetDefault("server.writeTimeout", 30*time.Second)
+	v.SetDefault("server.idleTimeout", 60*time.Second)
+	v.SetDefault("server.shutdownTimeout", 20*time.Second)
+
+	v.SetDefault("startup.retryAttempts", 10)
+	v.SetDefault("startup.retryInitialDelay", 500*time.Millisecond)
+	v.SetDefault("startup.retryMaxDelay", 10*time.Second)
+
+	v.SetDefault("cache.enabled", true)
+	v.SetDefault("cache.book.ttl", time.Minute)
+	v.SetDefault("cache.book.staleTTL", 30*time.Second)
+	v.SetDefault("cache.bookList.ttl", 30*time.Second)
+	v.SetDefault("cache.bookList.staleTTL", 30*time.Second)
+	v.SetDefault("cache.rating.ttl", time.Minute)
+	v.SetDefault("cache.rating.staleTTL", 30*time.Second)
+
+	v.SetDefault("idempotency.enabled", true)
+	v.SetDefault("idempotency.window", 24*time.Hour)
+
+	v.SetDefault("bookDeletion.retention", 90*24*time.Hour)
+	v.SetDefault("bookDeletion.purgeInterval", time.Hour)
+	v.SetDefault("bookDeletion.restoreRoles", []string{"Admin"})
+
+	v.SetDefault("readerDeletion.retention", 30*24*time.Hour)
+	v.SetDefault("readerDeletion.purgeInterval", time.Hour)
+	v.SetDefault("readerDeletion.restoreRoles", []string{"Admin"})
+
+	v.SetDefault("migrations.auto", false)
+}
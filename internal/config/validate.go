@@ -0,0 +1,228 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikitalystsev/BookSmart/internal/tracing"
+	"github.com/nikitalystsev/BookSmart/pkg/passwordhash"
+	"github.com/nikitalystsev/BookSmart/pkg/sms"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"github.com/sirupsen/logrus"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// validator собирает все ошибки конфигурации, чтобы при запуске они были
+// видны сразу, а не по одной
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) required(value, field string) {
+	v.check(value != "", field, "is required")
+}
+
+func (v *validator) oneOf(value, field string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) port(value, field string) {
+	port, err := strconv.Atoi(value)
+	v.check(err == nil && port > 0 && port <= 65535, field, "must be a port number, got %q", value)
+}
+
+// Validate проверяет конфигурацию целиком и возвращает все найденные ошибки
+// с путями к параметрам, например "rateLimit.default.rate: must be positive"
+func (cfg *Config) Validate() error {
+	v := &validator{}
+
+	for _, key := range cfg.unknownKeys {
+		v.check(false, key, "unknown parameter")
+	}
+
+	v.port(cfg.Port, "port")
+	v.oneOf(string(cfg.DBType), "db.type", string(DBTypePostgres), string(DBTypeMongo), string(DBTypeMemory))
+
+	switch cfg.DBType {
+	case DBTypePostgres:
+		v.required(cfg.Postgres.Host, "postgres.host")
+		v.port(cfg.Postgres.Port, "postgres.port")
+		v.required(cfg.Postgres.DBName, "postgres.dbName")
+		v.required(cfg.Postgres.Username, "postgres.username")
+		if len(cfg.Postgres.ReplicaHosts) > 0 {
+			replicationCfg := cfg.Postgres.Replication
+			v.check(replicationCfg.MaxLag > 0, "postgres.replication.maxLag", "must be positive")
+			v.check(replicationCfg.LagCheckInterval > 0, "postgres.replication.lagCheckInterval", "must be positive")
+			v.check(replicationCfg.Stickiness >= replicationCfg.MaxLag, "postgres.replication.stickiness", "must not be less than maxLag")
+		}
+	case DBTypeMongo:
+		v.required(cfg.Mongo.URI, "mongo.uri")
+		v.required(cfg.Mongo.DBName, "mongo.dbName")
+	}
+
+	// для хранилища в памяти Redis запускается внутри процесса
+	if cfg.DBType != DBTypeMemory {
+		v.required(cfg.Redis.Host, "redis.host")
+		v.port(cfg.Redis.Port, "redis.port")
+		v.check(cfg.Redis.DB >= 0, "redis.db", "must not be negative")
+	}
+
+	cfg.Auth.validate(v)
+
+	resetCfg := cfg.PasswordReset
+	v.check(resetCfg.CodeTTL > 0, "passwordReset.codeTTL", "must be positive")
+	v.check(resetCfg.MaxAttempts > 0, "passwordReset.maxAttempts", "must be positive")
+	v.check(resetCfg.ResendInterval >= 0, "passwordReset.resendInterval", "must not be negative")
+
+	v.oneOf(cfg.SMS.Sender, "sms.sender", sms.ConsoleSenderType, sms.FileSenderType)
+	if cfg.SMS.Sender == sms.FileSenderType {
+		v.required(cfg.SMS.FilePath, "sms.filePath")
+	}
+
+	throttleCfg := cfg.SignInThrottle
+	v.check(throttleCfg.FailureWindow > 0, "signInThrottle.failureWindow", "must be positive")
+	v.check(throttleCfg.BaseDelay > 0, "signInThrottle.baseDelay", "must be positive")
+	v.check(throttleCfg.MaxDelay >= throttleCfg.BaseDelay, "signInThrottle.maxDelay", "must not be less than baseDelay")
+	v.check(throttleCfg.LockoutDuration > 0, "signInThrottle.lockoutDuration", "must be positive")
+	checkLimits := func(limits SignInLimitsConfig, field string) {
+		v.check(limits.FreeAttempts >= 0, field+".freeAttempts", "must not be negative")
+		v.check(limits.MaxFailures > limits.FreeAttempts, field+".maxFailures", "must be greater than freeAttempts")
+	}
+	checkLimits(throttleCfg.Phone, "signInThrottle.phone")
+	checkLimits(throttleCfg.IP, "signInThrottle.ip")
+
+	v.check(cfg.TwoFactor.ChallengeTTL > 0, "twoFactor.challengeTTL", "must be positive")
+	v.check(cfg.TwoFactor.MaxAttempts > 0, "twoFactor.maxAttempts", "must be positive")
+	v.check(cfg.TwoFactor.MaxFailures > 0, "twoFactor.maxFailures", "must be positive")
+	v.check(cfg.TwoFactor.LockoutDuration > 0, "twoFactor.lockoutDuration", "must be positive")
+
+	cfg.RateLimit.validate(v)
+
+	v.oneOf(cfg.Tracing.Exporter, "tracing.exporter", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout)
+	v.check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sampleRatio", "must be between 0 and 1")
+
+	_, err := logrus.ParseLevel(cfg.Logging.Level)
+	v.check(err == nil, "logging.level", "unknown level %q", cfg.Logging.Level)
+	v.required(cfg.Logging.Dir, "logging.dir")
+	v.check(cfg.Logging.Rotation.MaxSizeMB >= 0, "logging.rotation.maxSizeMB", "must not be negative")
+	v.check(cfg.Logging.Rotation.Interval >= 0, "logging.rotation.interval", "must not be negative")
+	v.check(cfg.Logging.Rotation.MaxBackups >= 0, "logging.rotation.maxBackups", "must not be negative")
+
+	serverCfg := cfg.Server
+	v.check(serverCfg.ReadHeaderTimeout >= 0, "server.readHeaderTimeout", "must not be negative")
+	v.check(serverCfg.ReadTimeout >= 0, "server.readTimeout", "must not be negative")
+	v.check(serverCfg.WriteTimeout >= 0, "server.writeTimeout", "must not be negative")
+	v.check(serverCfg.IdleTimeout >= 0, "server.idleTimeout", "must not be negative")
+	v.check(serverCfg.ShutdownTimeout > 0, "server.shutdownTimeout", "must be positive")
+	for _, cidr := range serverCfg.TrustedProxies {
+		_, err := netip.ParsePrefix(cidr)
+		v.check(err == nil, "server.trustedProxies", "invalid CIDR %q", cidr)
+	}
+
+	v.check(cfg.Startup.RetryAttempts > 0, "startup.retryAttempts", "must be positive")
+	v.check(cfg.Startup.RetryInitialDelay > 0, "startup.retryInitialDelay", "must be positive")
+	v.check(cfg.Startup.RetryMaxDelay >= cfg.Startup.RetryInitialDelay, "startup.retryMaxDelay", "must not be less than retryInitialDelay")
+
+	if cfg.Cache.Enabled {
+		checkTTL := func(ttl CacheTTLConfig, field string) {
+			v.check(ttl.TTL > 0, field+".ttl", "must be positive")
+			v.check(ttl.StaleTTL >= 0, field+".staleTTL", "must not be negative")
+		}
+		checkTTL(cfg.Cache.Book, "cache.book")
+		checkTTL(cfg.Cache.BookList, "cache.bookList")
+		checkTTL(cfg.Cache.Rating, "cache.rating")
+	}
+
+	if cfg.Idempotency.Enabled {
+		v.check(cfg.Idempotency.Window > 0, "idempotency.window", "must be positive")
+	}
+
+	cfg.BookDeletion.validate(v, "bookDeletion")
+	cfg.ReaderDeletion.validate(v, "readerDeletion")
+
+	if len(v.errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(v.errs...))
+	}
+
+	return nil
+}
+
+func (cfg *DeletionConfig) validate(v *validator, section string) {
+	v.check(cfg.Retention > 0, section+".retention", "must be positive")
+	v.check(cfg.PurgeInterval > 0, section+".purgeInterval", "must be positive")
+	v.check(len(cfg.RestoreRoles) > 0, section+".restoreRoles", "must not be empty")
+}
+
+func (cfg *AuthConfig) validate(v *validator) {
+	jwtCfg := cfg.JWT
+	v.check(jwtCfg.AccessTokenTTL > 0, "auth.accessTokenTTL", "must be positive")
+	v.check(jwtCfg.RefreshTokenTTL >= jwtCfg.AccessTokenTTL, "auth.refreshTokenTTL", "must not be less than accessTokenTTL")
+	if jwtCfg.LegacyTokensUntil != "" {
+		_, err := time.Parse(time.RFC3339, jwtCfg.LegacyTokensUntil)
+		v.check(err == nil, "auth.legacyTokensUntil", "must be an RFC 3339 time, got %q", jwtCfg.LegacyTokensUntil)
+	}
+
+	if len(jwtCfg.Keys) == 0 {
+		v.required(jwtCfg.SigningKey, "auth.signingKey")
+	} else {
+		ids := make([]string, 0, len(jwtCfg.Keys))
+		for i, key := range jwtCfg.Keys {
+			field := fmt.Sprintf("auth.keys[%d]", i)
+			v.required(key.ID, field+".id")
+			v.check(!slices.Contains(ids, key.ID), field+".id", "duplicate key id %q", key.ID)
+			ids = append(ids, key.ID)
+
+			switch key.Algorithm {
+			case token.HS256, "":
+				v.required(key.SecretEnv, field+".secretEnv")
+			case token.RS256, token.EdDSA:
+				v.required(key.PrivateKeyFile, field+".privateKeyFile")
+			default:
+				v.oneOf(key.Algorithm, field+".algorithm", token.HS256, token.RS256, token.EdDSA)
+			}
+		}
+		v.check(slices.Contains(ids, jwtCfg.ActiveKeyID), "auth.activeKeyID", "must be one of auth.keys, got %q", jwtCfg.ActiveKeyID)
+	}
+
+	hashingCfg := cfg.PasswordHashing
+	v.oneOf(hashingCfg.Algorithm, "auth.passwordHashing.algorithm", passwordhash.Argon2id, passwordhash.Bcrypt)
+	v.check(hashingCfg.Argon2.Memory > 0, "auth.passwordHashing.argon2.memory", "must be positive")
+	v.check(hashingCfg.Argon2.Iterations > 0, "auth.passwordHashing.argon2.iterations", "must be positive")
+	v.check(hashingCfg.Argon2.Parallelism > 0, "auth.passwordHashing.argon2.parallelism", "must be positive")
+	v.check(hashingCfg.BcryptCost >= 4 && hashingCfg.BcryptCost <= 31, "auth.passwordHashing.bcryptCost", "must be between 4 and 31")
+}
+
+func (cfg *RateLimitConfig) validate(v *validator) {
+	if !cfg.Enabled {
+		return
+	}
+
+	checkValue := func(value RateLimitValueConfig, field string) {
+		v.check(value.Rate > 0, field+".rate", "must be positive")
+		v.check(value.Burst > 0, field+".burst", "must be positive")
+	}
+
+	checkValue(cfg.Default, "rateLimit.default")
+	for role, value := range cfg.Roles {
+		checkValue(value, "rateLimit.roles."+role)
+	}
+	for i, group := range cfg.Groups {
+		field := fmt.Sprintf("rateLimit.groups[%d]", i)
+		v.check(strings.HasPrefix(group.Prefix, "/"), field+".prefix", "must start with /")
+		checkValue(RateLimitValueConfig{Rate: group.Rate, Burst: group.Burst}, field)
+		for role, value := range group.Roles {
+			checkValue(value, field+".roles."+role)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ErrorResponse struct {
+	ErrorMsg string `json:"error_msg"`
+}
+
+type SimilarBookOutputDTO struct {
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	Publisher      string    `json:"publisher"`
+	CopiesNumber   uint      `json:"copies_number"`
+	Rarity         string    `json:"rarity"`
+	Genre          string    `json:"genre"`
+	PublishingYear uint      `json:"publishing_year"`
+	Language       string    `json:"language"`
+	AgeLimit       uint      `json:"age_limit"`
+	Score          float64   `json:"score"`
+}
+
+type BookOutputDTO struct {
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	Publisher      string    `json:"publisher"`
+	CopiesNumber   uint      `json:"copies_number"`
+	Rarity         string    `json:"rarity"`
+	Genre          string    `json:"genre"`
+	PublishingYear uint      `json:"publishing_year"`
+	Language       string    `json:"language"`
+	AgeLimit       uint      `json:"age_limit"`
+}
+
+type ReadingListInputDTO struct {
+	Name string `json:"name"`
+}
+
+type ReadingListBookInputDTO struct {
+	BookID uuid.UUID `json:"book_id"`
+}
+
+type ReadingListOrderInputDTO struct {
+	BookIDs []uuid.UUID `json:"book_ids"`
+}
+
+type ReadingListOutputDTO struct {
+	ID         uuid.UUID   `json:"id"`
+	Name       string      `json:"name"`
+	BookIDs    []uuid.UUID `json:"book_ids"`
+	ShareToken string      `json:"share_token,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type SharedReadingListOutputDTO struct {
+	Name    string      `json:"name"`
+	BookIDs []uuid.UUID `json:"book_ids"`
+}
+
+type ShareTokenOutputDTO struct {
+	ShareToken string `json:"share_token"`
+}
+
+type ReaderFioInputDTO struct {
+	Fio string `json:"fio"`
+}
+
+type ReaderPhoneNumberInputDTO struct {
+	Password    string `json:"password"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type ReaderPasswordInputDTO struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+}
+
+type ReaderPasswordConfirmInputDTO struct {
+	Password string `json:"password"`
+}
+
+type ReaderProfileOutputDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Fio         string    `json:"fio"`
+	PhoneNumber string    `json:"phone_number"`
+	Age         uint      `json:"age"`
+}
+
+type PasswordResetRequestInputDTO struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+type PasswordResetConfirmInputDTO struct {
+	PhoneNumber string `json:"phone_number"`
+	Code        string `json:"code"`
+	NewPassword string `json:"new_password"`
+}
+
+type LogoutInputDTO struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type SessionOutputDTO struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+type JWKOutputDTO struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSOutputDTO struct {
+	Keys []*JWKOutputDTO `json:"keys"`
+}
+
+type TwoFactorChallengeOutputDTO struct {
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+	ChallengeToken     string `json:"challenge_token"`
+}
+
+// TwoFactorSignInOutputDTO - ответ входа после проверки второго фактора: поля
+// ответа обычного входа и коды восстановления, если 2FA включалась при входе
+type TwoFactorSignInOutputDTO struct {
+	AccessToken   string    `json:"access_token"`
+	RefreshToken  string    `json:"refresh_token"`
+	ExpiredAt     int64     `json:"expired_at"`
+	ReaderID      uuid.UUID `json:"reader_id"`
+	RecoveryCodes []string  `json:"recovery_codes,omitempty"`
+}
+
+type TwoFactorChallengeInputDTO struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorChallengeEnrollInputDTO struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type TwoFactorEnrollmentOutputDTO struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeInputDTO struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesOutputDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type LivenessOutputDTO struct {
+	Status string `json:"status"`
+}
+
+type DependencyStatusDTO struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type ReadinessOutputDTO struct {
+	Status string                          `json:"status"`
+	Checks map[string]*DependencyStatusDTO `json:"checks"`
+}
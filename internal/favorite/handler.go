@@ -0,0 +1,124 @@
+package favorite
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+	"strconv"
+)
+
+type Handler struct {
+	favoriteBookService IFavoriteBookService
+	tokenManager        auth.ITokenManager
+}
+
+func NewHandler(favoriteBookService IFavoriteBookService, tokenManager auth.ITokenManager) *Handler {
+	return &Handler{favoriteBookService: favoriteBookService, tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		favoriteBooks := api.Group("/readers/:id/favorite_books",
+			middleware.Authenticate(h.tokenManager), middleware.RequireSameReader())
+		{
+			favoriteBooks.GET("", h.getFavoriteBooks)
+			favoriteBooks.DELETE("/:book_id", h.deleteFavoriteBook)
+		}
+	}
+}
+
+// @Summary Метод получения избранных книг читателя
+// @Security ApiKeyAuth
+// @Tags reader_favorite_books
+// @ID getFavoriteBooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param page_number query string true "Номер страницы"
+// @Success 200 {array} dto.BookOutputDTO "Успешное получение избранных книг"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Избранные книги не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/favorite_books [get]
+func (h *Handler) getFavoriteBooks(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	pageNumber, err := strconv.Atoi(c.Query("page_number"))
+	if err != nil || pageNumber < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: "invalid page number"})
+		return
+	}
+
+	books, err := h.favoriteBookService.GetByReaderID(c.Request.Context(), readerID,
+		FavoriteBooksPageLimit, (pageNumber-1)*FavoriteBooksPageLimit)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	booksDTO := make([]*dto.BookOutputDTO, len(books))
+	for i, book := range books {
+		booksDTO[i] = &dto.BookOutputDTO{
+			ID:             book.ID,
+			Title:          book.Title,
+			Author:         book.Author,
+			Publisher:      book.Publisher,
+			CopiesNumber:   book.CopiesNumber,
+			Rarity:         book.Rarity,
+			Genre:          book.Genre,
+			PublishingYear: book.PublishingYear,
+			Language:       book.Language,
+			AgeLimit:       book.AgeLimit,
+		}
+	}
+
+	c.JSON(http.StatusOK, booksDTO)
+}
+
+// @Summary Метод удаления книги из избранного
+// @Security ApiKeyAuth
+// @Tags reader_favorite_books
+// @ID deleteFavoriteBook
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param book_id path string true "Идентификатор книги"
+// @Success 200 "Успешное удаление книги из избранного"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Книги нет в избранном"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/favorite_books/{book_id} [delete]
+func (h *Handler) deleteFavoriteBook(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	bookID, err := uuid.Parse(c.Param("book_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err = h.favoriteBookService.Delete(c.Request.Context(), readerID, bookID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, ErrFavoriteBookDoesNotExists) {
+		status = http.StatusNotFound
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
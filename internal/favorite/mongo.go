@@ -0,0 +1,132 @@
+package favorite
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	favoriteBooksCollection = "favorite_books"
+	bookCollection          = "book"
+)
+
+type MongoRepo struct {
+	collection *mongo.Collection
+	logger     *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) IFavoriteBookRepo {
+	return &MongoRepo{collection: db.Collection(favoriteBooksCollection), logger: logger}
+}
+
+func (r *MongoRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting favorite books by reader ID")
+
+	// удаленные книги отбрасываются до $skip и $limit, чтобы страницы были полными
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"reader_id": toBinary(readerID)}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         bookCollection,
+			"localField":   "book_id",
+			"foreignField": "_id",
+			"as":           "book",
+		}}},
+		{{Key: "$unwind", Value: "$book"}},
+		{{Key: "$match", Value: bson.M{"book.deleted_at": nil}}},
+		{{Key: "$sort", Value: bson.D{{Key: "book.title", Value: 1}, {Key: "book._id", Value: 1}}}},
+		{{Key: "$skip", Value: int64(offset)}},
+		{{Key: "$limit", Value: int64(limit)}},
+		{{Key: "$replaceWith", Value: "$book"}},
+	})
+	if err != nil {
+		logger.Errorf("error selecting favorite books: %v", err)
+		return nil, err
+	}
+
+	var documents []bookDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		logger.Errorf("error decoding favorite books: %v", err)
+		return nil, err
+	}
+
+	if len(documents) == 0 {
+		logger.Warn("favorite books not found")
+		return nil, ErrFavoriteBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(documents))
+	for i := range documents {
+		if books[i], err = documents[i].model(); err != nil {
+			logger.Errorf("error converting favorite book: %v", err)
+			return nil, err
+		}
+	}
+
+	logger.Infof("found %d favorite books", len(books))
+
+	return books, nil
+}
+
+func (r *MongoRepo) Delete(ctx context.Context, readerID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting favorite book")
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"reader_id": toBinary(readerID), "book_id": toBinary(bookID)})
+	if err != nil {
+		logger.Errorf("error deleting favorite book: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		logger.Warn("favorite book not found")
+		return ErrFavoriteBookDoesNotExists
+	}
+
+	logger.Info("successfully deleted favorite book")
+
+	return nil
+}
+
+// bookDocument - документ коллекции book
+type bookDocument struct {
+	ID             primitive.Binary `bson:"_id"`
+	Title          string           `bson:"title"`
+	Author         string           `bson:"author"`
+	Publisher      string           `bson:"publisher"`
+	CopiesNumber   uint             `bson:"copies_number"`
+	Rarity         string           `bson:"rarity"`
+	Genre          string           `bson:"genre"`
+	PublishingYear uint             `bson:"publishing_year"`
+	Language       string           `bson:"language"`
+	AgeLimit       uint             `bson:"age_limit"`
+}
+
+func (d *bookDocument) model() (*models.BookModel, error) {
+	ID, err := uuid.FromBytes(d.ID.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BookModel{
+		ID:             ID,
+		Title:          d.Title,
+		Author:         d.Author,
+		Publisher:      d.Publisher,
+		CopiesNumber:   d.CopiesNumber,
+		Rarity:         d.Rarity,
+		Genre:          d.Genre,
+		PublishingYear: d.PublishingYear,
+		Language:       d.Language,
+		AgeLimit:       d.AgeLimit,
+	}, nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
@@ -0,0 +1,113 @@
+package favorite
+
+import (
+	"context"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) IFavoriteBookRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting favorite books by reader ID")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select b.id, b.title, b.author, b.publisher, b.copies_number, b.rarity, b.genre,
+			         b.publishing_year, b.language, b.age_limit
+			  from bs.favorite_books f join bs.book b on b.id = f.book_id
+			  where f.reader_id = $1 and b.deleted_at is null
+			  order by b.title, b.id
+			  limit $2 offset $3`
+
+	var rows []bookRow
+	if err := tr.SelectContext(ctx, &rows, query, readerID, limit, offset); err != nil {
+		logger.Errorf("error selecting favorite books: %v", err)
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		logger.Warn("favorite books not found")
+		return nil, ErrFavoriteBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(rows))
+	for i := range rows {
+		books[i] = rows[i].model()
+	}
+
+	logger.Infof("found %d favorite books", len(books))
+
+	return books, nil
+}
+
+func (r *PostgresRepo) Delete(ctx context.Context, readerID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting favorite book")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `delete from bs.favorite_books where reader_id = $1 and book_id = $2`
+
+	result, err := tr.ExecContext(ctx, query, readerID, bookID)
+	if err != nil {
+		logger.Errorf("error deleting favorite book: %v", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("error deleting favorite book: %v", err)
+		return err
+	}
+	if rows == 0 {
+		logger.Warn("favorite book not found")
+		return ErrFavoriteBookDoesNotExists
+	}
+
+	logger.Info("successfully deleted favorite book")
+
+	return nil
+}
+
+// bookRow - строка bs.book; модель сервисов не содержит тегов db
+type bookRow struct {
+	ID             uuid.UUID `db:"id"`
+	Title          string    `db:"title"`
+	Author         string    `db:"author"`
+	Publisher      string    `db:"publisher"`
+	CopiesNumber   uint      `db:"copies_number"`
+	Rarity         string    `db:"rarity"`
+	Genre          string    `db:"genre"`
+	PublishingYear uint      `db:"publishing_year"`
+	Language       string    `db:"language"`
+	AgeLimit       uint      `db:"age_limit"`
+}
+
+func (r *bookRow) model() *models.BookModel {
+	return &models.BookModel{
+		ID:             r.ID,
+		Title:          r.Title,
+		Author:         r.Author,
+		Publisher:      r.Publisher,
+		CopiesNumber:   r.CopiesNumber,
+		Rarity:         r.Rarity,
+		Genre:          r.Genre,
+		PublishingYear: r.PublishingYear,
+		Language:       r.Language,
+		AgeLimit:       r.AgeLimit,
+	}
+}
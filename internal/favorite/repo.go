@@ -0,0 +1,21 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+)
+
+var ErrFavoriteBookDoesNotExists = errors.New("favorite book does not exists")
+
+// IFavoriteBookRepo читает и удаляет избранные книги читателя. Добавление и
+// проверка книги остаются в IReaderRepo
+type IFavoriteBookRepo interface {
+	// GetByReaderID возвращает страницу избранных книг читателя, упорядоченных
+	// по названию, одним запросом вместе с книгами. Удаленные книги в страницу
+	// не входят
+	GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error)
+	// Delete удаляет книгу из избранного читателя
+	Delete(ctx context.Context, readerID, bookID uuid.UUID) error
+}
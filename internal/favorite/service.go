@@ -0,0 +1,60 @@
+package favorite
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const FavoriteBooksPageLimit = 10
+
+type IFavoriteBookService interface {
+	GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error)
+	Delete(ctx context.Context, readerID, bookID uuid.UUID) error
+}
+
+type FavoriteBookService struct {
+	favoriteBookRepo IFavoriteBookRepo
+	logger           *logrus.Entry
+}
+
+func NewFavoriteBookService(
+	favoriteBookRepo IFavoriteBookRepo,
+	logger *logrus.Entry,
+) IFavoriteBookService {
+	return &FavoriteBookService{
+		favoriteBookRepo: favoriteBookRepo,
+		logger:           logger,
+	}
+}
+
+func (fbs *FavoriteBookService) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, fbs.logger)
+	logger.Info("attempting to get favorite books by reader ID")
+
+	books, err := fbs.favoriteBookRepo.GetByReaderID(ctx, readerID, limit, offset)
+	if err != nil {
+		logger.Errorf("error getting favorite books: %v", err)
+		return nil, err
+	}
+
+	logger.Info("favorite books successfully retrieved")
+
+	return books, nil
+}
+
+func (fbs *FavoriteBookService) Delete(ctx context.Context, readerID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, fbs.logger)
+	logger.Info("attempting to delete favorite book")
+
+	if err := fbs.favoriteBookRepo.Delete(ctx, readerID, bookID); err != nil {
+		logger.Errorf("error deleting favorite book: %v", err)
+		return err
+	}
+
+	logger.Info("favorite book successfully deleted")
+
+	return nil
+}
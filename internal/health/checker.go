@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	DefaultCheckTimeout = 2 * time.Second
+)
+
+// Check проверяет доступность одной зависимости
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Result - состояние зависимости и время ее проверки
+type Result struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Err     error
+}
+
+type Report struct {
+	Ready   bool
+	Results []Result
+}
+
+// Checker опрашивает зависимости параллельно, каждую не дольше timeout
+type Checker struct {
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{Ready: true, Results: make([]Result, len(c.checks))}
+
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Results[i] = c.run(ctx, nc)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Status != StatusUp {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, nc namedCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := nc.check(ctx)
+	result := Result{Name: nc.name, Status: StatusUp, Latency: time.Since(start), Err: err}
+	if err != nil {
+		result.Status = StatusDown
+	}
+
+	return result
+}
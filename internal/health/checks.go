@@ -0,0 +1,27 @@
+package health
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func PostgresCheck(db *sqlx.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+func MongoCheck(client *mongo.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}
+}
+
+func RedisCheck(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"net/http"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+type Handler struct {
+	checker *Checker
+}
+
+func NewHandler(checker *Checker) *Handler {
+	return &Handler{checker: checker}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	router.GET("/healthz", h.liveness)
+	router.GET("/readyz", h.readiness)
+}
+
+// @Summary Метод проверки, что процесс приложения жив
+// @Tags health
+// @ID liveness
+// @Produce json
+// @Success 200 {object} dto.LivenessOutputDTO "Приложение работает"
+// @Router /healthz [get]
+func (h *Handler) liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, &dto.LivenessOutputDTO{Status: statusOK})
+}
+
+// @Summary Метод проверки готовности экземпляра принимать запросы
+// @Tags health
+// @ID readiness
+// @Produce json
+// @Success 200 {object} dto.ReadinessOutputDTO "Все зависимости доступны"
+// @Failure 503 {object} dto.ReadinessOutputDTO "Недоступна хотя бы одна зависимость"
+// @Router /readyz [get]
+func (h *Handler) readiness(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+
+	readinessDTO := &dto.ReadinessOutputDTO{
+		Status: statusOK,
+		Checks: make(map[string]*dto.DependencyStatusDTO, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		dependency := &dto.DependencyStatusDTO{
+			Status:    result.Status,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Err != nil {
+			dependency.Error = result.Err.Error()
+		}
+		readinessDTO.Checks[result.Name] = dependency
+	}
+
+	status := http.StatusOK
+	if !report.Ready {
+		readinessDTO.Status = statusUnavailable
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, readinessDTO)
+}
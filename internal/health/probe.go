@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const probeTimeout = 5 * time.Second
+
+// Probe запрашивает /readyz запущенного приложения. Используется командой
+// `app healthcheck`, чтобы healthcheck контейнера не зависел от утилит образа
+func Probe(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	return nil
+}
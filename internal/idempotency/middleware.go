@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderReplayed       = "Idempotent-Replayed"
+
+	ErrMsgInvalidKey       = "invalid idempotency key"
+	ErrMsgKeyReused        = "idempotency key was already used for a different request"
+	ErrMsgRequestInProcess = "request with this idempotency key is still being processed"
+
+	maxKeyLength = 255
+	// processingTTL - срок, на который ключ занимается обрабатываемым запросом;
+	// если экземпляр упадет, не сохранив ответ, ключ освободится сам
+	processingTTL = time.Minute
+)
+
+// routes - POST-маршруты, принимающие ключ идемпотентности
+var routes = map[string]bool{
+	"/api/v1/readers/:id/reservations": true,
+	"/api/v1/readers/:id/lib_cards":    true,
+	"/api/v1/books/:id/ratings":        true,
+}
+
+// Middleware сохраняет первый ответ на запрос с заголовком Idempotency-Key и
+// на window возвращает его на повторы с тем же ключом и телом, не вызывая
+// обработчик. Ключ с другим запросом отклоняется с 422, повтор во время
+// обработки первого запроса - с 409. Ключи разделены по клиентам: читателю
+// из access-токена или IP. Ответы 5xx не сохраняются, такой запрос можно
+// повторить. При недоступности Redis запросы обрабатываются без проверки ключа
+func Middleware(store IRecordStore, matcher *middleware.RouteMatcher, window time.Duration, logger *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" || c.Request.Method != http.MethodPost || !routes[matcher.Match(c.Request.Method, c.Request.URL.Path)] {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: ErrMsgInvalidKey})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logging.FromContext(ctx, logger).Warnf("error reading request body: %v", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: "error reading request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		client, _ := session.ClientIdentity(c.Request)
+		storeKey := client + ":" + key
+		fingerprint := requestFingerprint(c.Request, body)
+
+		record, isReserved, err := store.Reserve(ctx, storeKey, fingerprint, processingTTL)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if !isReserved {
+			replay(c, record, fingerprint)
+			return
+		}
+
+		writer := middleware.NewBufferedWriter(c.Writer)
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// ответ записывается и при отключившемся клиенте: иначе ключ останется
+		// в обработке, и повтор запроса получит 409 до истечения processingTTL
+		storeCtx := context.WithoutCancel(ctx)
+		if status := writer.Status(); status >= http.StatusInternalServerError {
+			_ = store.Release(storeCtx, storeKey)
+		} else {
+			_ = store.Save(storeCtx, storeKey, &Record{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				Status:      status,
+				ContentType: writer.Header().Get("Content-Type"),
+				Body:        writer.Body(),
+			}, window)
+		}
+
+		writer.Send()
+	}
+}
+
+func replay(c *gin.Context, record *Record, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{ErrorMsg: ErrMsgKeyReused})
+	case !record.Completed:
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{ErrorMsg: ErrMsgRequestInProcess})
+	default:
+		c.Header(HeaderReplayed, "true")
+		c.Abort()
+		if len(record.Body) == 0 {
+			c.Status(record.Status)
+			return
+		}
+		c.Data(record.Status, record.ContentType, record.Body)
+	}
+}
+
+// requestFingerprint отличает запросы с одним ключом: путь содержит
+// идентификаторы читателя или книги, тело - данные запроса
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,109 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const keyPrefix = "idempotency:"
+
+// Record - запрос с ключом идемпотентности. Пока обработка не завершена,
+// Completed = false и ответа нет
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IRecordStore хранит запросы с ключами идемпотентности, общие для всех
+// экземпляров приложения
+type IRecordStore interface {
+	// Reserve занимает ключ под новый запрос на ttl. Если ключ уже занят,
+	// возвращается его запись и isReserved = false
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (record *Record, isReserved bool, err error)
+	// Save сохраняет ответ на запрос на ttl
+	Save(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release освобождает ключ, чтобы запрос можно было повторить
+	Release(ctx context.Context, key string) error
+}
+
+type RedisRecordStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisRecordStore(client *redis.Client, logger *logrus.Entry) IRecordStore {
+	return &RedisRecordStore{client: client, logger: logger}
+}
+
+func (s *RedisRecordStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	data, err := json.Marshal(&Record{Fingerprint: fingerprint})
+	if err != nil {
+		logger.Errorf("error encoding idempotency record: %v", err)
+		return nil, false, err
+	}
+
+	isReserved, err := s.client.SetNX(ctx, keyPrefix+key, data, ttl).Result()
+	if err != nil {
+		logger.Errorf("error reserving idempotency key: %v", err)
+		return nil, false, err
+	}
+	if isReserved {
+		return nil, true, nil
+	}
+
+	data, err = s.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// запись истекла между SETNX и GET
+		return s.Reserve(ctx, key, fingerprint, ttl)
+	}
+	if err != nil {
+		logger.Errorf("error getting idempotency record: %v", err)
+		return nil, false, err
+	}
+
+	var record Record
+	if err = json.Unmarshal(data, &record); err != nil {
+		logger.Errorf("error decoding idempotency record: %v", err)
+		return nil, false, err
+	}
+
+	return &record, false, nil
+}
+
+func (s *RedisRecordStore) Save(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		logger.Errorf("error encoding idempotency record: %v", err)
+		return err
+	}
+
+	if err = s.client.Set(ctx, keyPrefix+key, data, ttl).Err(); err != nil {
+		logger.Errorf("error saving idempotency record: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisRecordStore) Release(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, keyPrefix+key).Err(); err != nil {
+		logger.Errorf("error releasing idempotency key: %v", err)
+		return err
+	}
+
+	return nil
+}
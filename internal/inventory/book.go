@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+)
+
+// BookRepo делает изменение числа экземпляров в транзакции атомарным.
+// Сервис бронирования читает книгу, проверяет CopiesNumber и записывает всю
+// книгу с уменьшенным значением; две параллельные транзакции при этом могли
+// взять один последний экземпляр. Декоратор запоминает число экземпляров,
+// прочитанное в транзакции, и переводит Update в условное изменение на
+// разницу: вторая транзакция получит errs.ErrBookNoCopiesNum и откатится.
+// Adjust сам увеличивает версию книги, поэтому при изменении только числа
+// экземпляров книга больше не записывается и параллельные брони не конфликтуют
+// по версии. Вне транзакции Update записывает значение как есть (правка книги
+// администратором)
+type BookRepo struct {
+	intfRepo.IBookRepo
+	copies ICopiesRepo
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, copies ICopiesRepo) intfRepo.IBookRepo {
+	return &BookRepo{IBookRepo: next, copies: copies}
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	book, err := r.IBookRepo.GetByID(ctx, ID)
+	if err != nil || book == nil {
+		return book, err
+	}
+
+	if seen, ok := ctx.Value(seenKey{}).(*seenCopies); ok {
+		seen.set(*book)
+	}
+
+	return book, nil
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) error {
+	seen, ok := ctx.Value(seenKey{}).(*seenCopies)
+	if !ok {
+		return r.IBookRepo.Update(ctx, book)
+	}
+
+	read, ok := seen.get(book.ID)
+	if !ok || read.CopiesNumber == book.CopiesNumber {
+		return r.IBookRepo.Update(ctx, book)
+	}
+
+	copiesNumber, version, err := r.copies.Adjust(ctx, book.ID, int(book.CopiesNumber)-int(read.CopiesNumber))
+	if err != nil {
+		return err
+	}
+	optimistic.Advance(ctx, optimistic.EntityBook, book.ID, version)
+
+	// остальные поля не менялись: Adjust уже записал все изменение
+	unchanged := read
+	unchanged.CopiesNumber = book.CopiesNumber
+	if *book == unchanged {
+		read.CopiesNumber = copiesNumber
+		seen.set(read)
+		return nil
+	}
+
+	// строка уже заблокирована Adjust до конца транзакции, поэтому запись
+	// остальных полей не перетрет чужое изменение числа экземпляров
+	updated := *book
+	updated.CopiesNumber = copiesNumber
+	if err = r.IBookRepo.Update(ctx, &updated); err != nil {
+		return err
+	}
+	seen.set(updated)
+
+	return nil
+}
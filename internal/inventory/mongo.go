@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const bookCollection = "book"
+
+type MongoRepo struct {
+	collection *mongo.Collection
+	logger     *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) ICopiesRepo {
+	return &MongoRepo{collection: db.Collection(bookCollection), logger: logger}
+}
+
+func (r *MongoRepo) Adjust(ctx context.Context, bookID uuid.UUID, delta int) (uint, int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("adjusting book copies number")
+
+	filter := bson.M{"_id": toBinary(bookID)}
+	if delta < 0 {
+		filter["copies_number"] = bson.M{"$gte": -delta}
+	}
+
+	var document struct {
+		CopiesNumber uint  `bson:"copies_number"`
+		Version      int64 `bson:"version"`
+	}
+	err := r.collection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"copies_number": int64(delta), "version": int64(1)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Warn("no book copies available")
+		return 0, 0, errs.ErrBookNoCopiesNum
+	}
+	if err != nil {
+		logger.Errorf("error adjusting book copies number: %v", err)
+		return 0, 0, err
+	}
+
+	logger.Info("successfully adjusted book copies number")
+
+	return document.CopiesNumber, document.Version, nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
@@ -0,0 +1,55 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) ICopiesRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresRepo) Adjust(ctx context.Context, bookID uuid.UUID, delta int) (uint, int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("adjusting book copies number")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	// условие проверяется по строке, заблокированной обновлением, поэтому
+	// параллельная транзакция, взявшая последний экземпляр, увидит уже новое значение
+	query := `update bs.book 
+			  set copies_number = copies_number + $2, version = version + 1
+			  where id = $1 and copies_number + $2 >= 0
+			  returning copies_number, version`
+
+	var result struct {
+		CopiesNumber uint  `db:"copies_number"`
+		Version      int64 `db:"version"`
+	}
+	err := tr.GetContext(ctx, &result, query, bookID, delta)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Warn("no book copies available")
+		return 0, 0, errs.ErrBookNoCopiesNum
+	}
+	if err != nil {
+		logger.Errorf("error adjusting book copies number: %v", err)
+		return 0, 0, err
+	}
+
+	logger.Info("successfully adjusted book copies number")
+
+	return result.CopiesNumber, result.Version, nil
+}
@@ -0,0 +1,15 @@
+package inventory
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+// ICopiesRepo атомарно изменяет число экземпляров книги условным обновлением,
+// без чтения и последующей записи
+type ICopiesRepo interface {
+	// Adjust изменяет число экземпляров на delta, увеличивает версию книги и
+	// возвращает новые значения. Если экземпляров меньше -delta, ничего не
+	// меняется и возвращается errs.ErrBookNoCopiesNum
+	Adjust(ctx context.Context, bookID uuid.UUID, delta int) (copiesNumber uint, version int64, err error)
+}
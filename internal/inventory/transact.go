@@ -0,0 +1,49 @@
+package inventory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"sync"
+)
+
+type seenKey struct{}
+
+// seenCopies - книги, прочитанные в транзакции, с учетом уже записанного
+// изменения числа экземпляров
+type seenCopies struct {
+	mu    sync.Mutex
+	books map[uuid.UUID]models.BookModel
+}
+
+func (s *seenCopies) get(bookID uuid.UUID) (models.BookModel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	book, ok := s.books[bookID]
+	return book, ok
+}
+
+func (s *seenCopies) set(book models.BookModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books[book.ID] = book
+}
+
+// TransactionManager заводит для каждой транзакции учет прочитанных книг,
+// по которому BookRepo вычисляет изменение числа экземпляров
+type TransactionManager struct {
+	next transact.ITransactionManager
+}
+
+func NewTransactionManager(next transact.ITransactionManager) transact.ITransactionManager {
+	return &TransactionManager{next: next}
+}
+
+func (tm *TransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	if _, ok := ctx.Value(seenKey{}).(*seenCopies); ok {
+		return tm.next.Do(ctx, fn)
+	}
+
+	return tm.next.Do(context.WithValue(ctx, seenKey{}, &seenCopies{books: make(map[uuid.UUID]models.BookModel)}), fn)
+}
@@ -0,0 +1,47 @@
+package jwks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"net/http"
+)
+
+type Handler struct {
+	tokenManager *token.Manager
+}
+
+func NewHandler(tokenManager *token.Manager) *Handler {
+	return &Handler{tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	router.GET("/.well-known/jwks.json", h.getJWKS)
+}
+
+// @Summary Метод получения открытых ключей для проверки access-токенов
+// @Tags auth
+// @ID getJWKS
+// @Produce json
+// @Success 200 {object} dto.JWKSOutputDTO "Успешное получение ключей"
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(c *gin.Context) {
+	keys := h.tokenManager.JWKS()
+
+	jwksDTO := &dto.JWKSOutputDTO{Keys: make([]*dto.JWKOutputDTO, len(keys))}
+	for i, key := range keys {
+		jwksDTO.Keys[i] = &dto.JWKOutputDTO{
+			Kty: key.Kty,
+			Use: key.Use,
+			Alg: key.Alg,
+			Kid: key.Kid,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		}
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwksDTO)
+}
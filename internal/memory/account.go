@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type AccountRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewAccountRepo(store *Store, logger *logrus.Entry) account.IAccountRepo {
+	return &AccountRepo{store: store, logger: logger}
+}
+
+func (r *AccountRepo) Update(ctx context.Context, model *models.ReaderModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[model.ID]
+	if !ok {
+		logger.Warn("reader not found")
+		return errs.ErrReaderDoesNotExists
+	}
+	record.Fio = model.Fio
+	record.PhoneNumber = model.PhoneNumber
+	record.Password = model.Password
+	r.store.data.Readers[model.ID] = record
+
+	logger.Info("successfully updated reader")
+
+	return nil
+}
+
+func (r *AccountRepo) ReplacePasswordHash(ctx context.Context, readerID uuid.UUID, oldHash, newHash string) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("replacing reader password hash")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[readerID]
+	if !ok || record.Password != oldHash {
+		logger.Warn("reader password changed meanwhile, hash not replaced")
+		return nil
+	}
+	record.Password = newHash
+	r.store.data.Readers[readerID] = record
+
+	logger.Info("successfully replaced reader password hash")
+
+	return nil
+}
+
+// Delete удаляет читателя так же, как Postgres: отзывы и бронирования
+// переходят анонимному читателю, списки чтения и настройки 2FA удаляются
+// вместе с читателем, как по каскадным внешним ключам
+func (r *AccountRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	d := r.store.data
+	for ID, record := range d.Ratings {
+		if record.ReaderID == readerID {
+			record.ReaderID = account.AnonymousReaderID
+			d.Ratings[ID] = record
+		}
+	}
+	for ID, record := range d.Reservations {
+		if record.ReaderID == readerID {
+			record.ReaderID = account.AnonymousReaderID
+			d.Reservations[ID] = record
+		}
+	}
+
+	delete(d.FavoriteBooks, readerID)
+	for ID, record := range d.LibCards {
+		if record.ReaderID == readerID {
+			delete(d.LibCards, ID)
+		}
+	}
+	for ID, record := range d.ReadingLists {
+		if record.ReaderID == readerID {
+			delete(d.ReadingLists, ID)
+		}
+	}
+	delete(d.TwoFactors, readerID)
+	delete(d.Readers, readerID)
+
+	logger.Info("successfully deleted reader")
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/inventory"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+type BookRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewBookRepo(store *Store, logger *logrus.Entry) intfRepo.IBookRepo {
+	return &BookRepo{store: store, logger: logger}
+}
+
+func (r *BookRepo) Create(ctx context.Context, model *models.BookModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting book into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var record book
+	record.setModel(model)
+	r.store.data.Books[model.ID] = record
+
+	logger.Info("successfully inserted book")
+
+	return nil
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting book by ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[ID]
+	if !ok {
+		logger.Warn("book not found")
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	return record.model(), nil
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting book by title")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, record := range r.store.data.Books {
+		if record.Title == title {
+			return record.model(), nil
+		}
+	}
+
+	logger.Warn("book not found")
+
+	return nil, errs.ErrBookDoesNotExists
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.store.data.Books[ID]; !ok {
+		logger.Warn("book not found")
+		return errs.ErrBookDoesNotExists
+	}
+	deleteBook(r.store.data, ID)
+
+	logger.Info("successfully deleted book")
+
+	return nil
+}
+
+func (r *BookRepo) Update(ctx context.Context, model *models.BookModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[model.ID]
+	if !ok {
+		logger.Warn("book not found")
+		return errs.ErrBookDoesNotExists
+	}
+	record.setModel(model)
+	r.store.data.Books[model.ID] = record
+
+	logger.Info("successfully updated book")
+
+	return nil
+}
+
+// GetByParams ищет строковые параметры подстрокой без учета регистра, а
+// числовые - точным совпадением; пустые и нулевые параметры не учитываются.
+// Книги упорядочены по названию, чтобы страницы не пересекались
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting books by params")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records := make([]book, 0)
+	for _, record := range r.store.data.Books {
+		if matchesParams(&record, params) {
+			records = append(records, record)
+		}
+	}
+	sortBooks(records)
+
+	records = page(records, int(params.Limit), params.Offset)
+	if len(records) == 0 {
+		logger.Warn("books not found")
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(records))
+	for i := range records {
+		books[i] = records[i].model()
+	}
+
+	return books, nil
+}
+
+// sortBooks упорядочивает книги по названию, чтобы страницы не пересекались
+func sortBooks(records []book) {
+	slices.SortFunc(records, func(a, b book) int {
+		return cmp.Or(cmp.Compare(a.Title, b.Title), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+}
+
+func matchesParams(record *book, params *dto.BookParamsDTO) bool {
+	contains := func(value, param string) bool {
+		return param == "" || strings.Contains(strings.ToLower(value), strings.ToLower(param))
+	}
+	equals := func(value, param uint) bool {
+		return param == 0 || value == param
+	}
+
+	return contains(record.Title, params.Title) &&
+		contains(record.Author, params.Author) &&
+		contains(record.Publisher, params.Publisher) &&
+		contains(record.Rarity, params.Rarity) &&
+		contains(record.Genre, params.Genre) &&
+		contains(record.Language, params.Language) &&
+		equals(record.CopiesNumber, params.CopiesNumber) &&
+		equals(record.PublishingYear, params.PublishingYear) &&
+		equals(record.AgeLimit, params.AgeLimit)
+}
+
+// deleteBook удаляет книгу вместе со ссылающимися на нее данными, как
+// каскадные внешние ключи в Postgres
+func deleteBook(d *data, ID uuid.UUID) {
+	for ratingID, record := range d.Ratings {
+		if record.BookID == ID {
+			delete(d.Ratings, ratingID)
+		}
+	}
+	for reservationID, record := range d.Reservations {
+		if record.BookID == ID {
+			delete(d.Reservations, reservationID)
+		}
+	}
+	for readerID, bookIDs := range d.FavoriteBooks {
+		if slices.Contains(bookIDs, ID) {
+			d.FavoriteBooks[readerID] = slices.DeleteFunc(slices.Clone(bookIDs), func(bookID uuid.UUID) bool { return bookID == ID })
+		}
+	}
+	for listID, record := range d.ReadingLists {
+		if slices.Contains(record.BookIDs, ID) {
+			record.BookIDs = slices.DeleteFunc(slices.Clone(record.BookIDs), func(bookID uuid.UUID) bool { return bookID == ID })
+			d.ReadingLists[listID] = record
+		}
+	}
+	delete(d.Books, ID)
+}
+
+// page возвращает страницу из limit записей начиная с offset; limit = 0 -
+// без ограничения
+func page[T any](records []T, limit, offset int) []T {
+	if offset >= len(records) {
+		return nil
+	}
+	records = records[max(offset, 0):]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+
+	return records
+}
+
+type CopiesRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewCopiesRepo(store *Store, logger *logrus.Entry) inventory.ICopiesRepo {
+	return &CopiesRepo{store: store, logger: logger}
+}
+
+func (r *CopiesRepo) Adjust(ctx context.Context, bookID uuid.UUID, delta int) (uint, int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("adjusting book copies number")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[bookID]
+	if !ok || int(record.CopiesNumber)+delta < 0 {
+		logger.Warn("no book copies available")
+		return 0, 0, errs.ErrBookNoCopiesNum
+	}
+	record.CopiesNumber = uint(int(record.CopiesNumber) + delta)
+	record.Version++
+	r.store.data.Books[bookID] = record
+
+	logger.Info("successfully adjusted book copies number")
+
+	return record.CopiesNumber, record.Version, nil
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type DeletedBookRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewDeletedBookRepo(store *Store, logger *logrus.Entry) softdelete.IDeletedBookRepo {
+	return &DeletedBookRepo{store: store, logger: logger}
+}
+
+func (r *DeletedBookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[ID]
+	if !ok || record.DeletedAt != nil {
+		logger.Warn("book not found")
+		return errs.ErrBookDoesNotExists
+	}
+	now := time.Now()
+	record.DeletedAt = &now
+	r.store.data.Books[ID] = record
+
+	logger.Info("successfully soft deleted book")
+
+	return nil
+}
+
+func (r *DeletedBookRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[ID]
+	if !ok || record.DeletedAt == nil {
+		logger.Warn("deleted book not found")
+		return softdelete.ErrDeletedBookDoesNotExists
+	}
+	record.DeletedAt = nil
+	r.store.data.Books[ID] = record
+
+	logger.Info("successfully restored book")
+
+	return nil
+}
+
+func (r *DeletedBookRepo) GetDeleted(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	deleted := make(map[uuid.UUID]bool)
+	for _, ID := range IDs {
+		if record, ok := r.store.data.Books[ID]; ok && record.DeletedAt != nil {
+			deleted[ID] = true
+		}
+	}
+
+	return deleted, nil
+}
+
+func (r *DeletedBookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting available books by params")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records := make([]book, 0)
+	for _, record := range r.store.data.Books {
+		if record.DeletedAt == nil && matchesParams(&record, params) {
+			records = append(records, record)
+		}
+	}
+	sortBooks(records)
+
+	records = page(records, int(params.Limit), params.Offset)
+	if len(records) == 0 {
+		logger.Warn("books not found")
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(records))
+	for i := range records {
+		books[i] = records[i].model()
+	}
+
+	return books, nil
+}
+
+func (r *DeletedBookRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	reserved := make(map[uuid.UUID]bool)
+	for _, record := range r.store.data.Reservations {
+		if record.State != impl.ReservationClosed {
+			reserved[record.BookID] = true
+		}
+	}
+
+	var IDs []uuid.UUID
+	for ID, record := range r.store.data.Books {
+		if record.DeletedAt != nil && record.DeletedAt.Before(before) && !reserved[ID] {
+			IDs = append(IDs, ID)
+		}
+	}
+
+	return IDs, nil
+}
+
+func (r *DeletedBookRepo) Purge(ctx context.Context, ID uuid.UUID, before time.Time) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("purging book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Books[ID]
+	if !ok || record.DeletedAt == nil || !record.DeletedAt.Before(before) {
+		logger.Info("book was restored, skipping it")
+		return nil
+	}
+	deleteBook(r.store.data, ID)
+
+	logger.Info("successfully purged book")
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type DeletedReaderRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewDeletedReaderRepo(store *Store, logger *logrus.Entry) softdelete.IDeletedReaderRepo {
+	return &DeletedReaderRepo{store: store, logger: logger}
+}
+
+func (r *DeletedReaderRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting reader")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[ID]
+	if !ok || record.DeletedAt != nil {
+		logger.Warn("reader not found")
+		return errs.ErrReaderDoesNotExists
+	}
+	now := time.Now()
+	record.DeletedAt = &now
+	r.store.data.Readers[ID] = record
+
+	logger.Info("successfully soft deleted reader")
+
+	return nil
+}
+
+func (r *DeletedReaderRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring reader")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[ID]
+	if !ok || record.DeletedAt == nil {
+		logger.Warn("deleted reader not found")
+		return softdelete.ErrDeletedReaderDoesNotExists
+	}
+	record.DeletedAt = nil
+	r.store.data.Readers[ID] = record
+
+	logger.Info("successfully restored reader")
+
+	return nil
+}
+
+func (r *DeletedReaderRepo) IsDeleted(ctx context.Context, ID uuid.UUID) (bool, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[ID]
+
+	return ok && record.DeletedAt != nil, nil
+}
+
+func (r *DeletedReaderRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var IDs []uuid.UUID
+	for ID, record := range r.store.data.Readers {
+		if record.DeletedAt != nil && record.DeletedAt.Before(before) {
+			IDs = append(IDs, ID)
+		}
+	}
+
+	return IDs, nil
+}
+
+func (r *DeletedReaderRepo) IsPurgeable(ctx context.Context, ID uuid.UUID, before time.Time) (bool, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[ID]
+
+	return ok && record.DeletedAt != nil && record.DeletedAt.Before(before), nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/internal/favorite"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+type FavoriteBookRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewFavoriteBookRepo(store *Store, logger *logrus.Entry) favorite.IFavoriteBookRepo {
+	return &FavoriteBookRepo{store: store, logger: logger}
+}
+
+func (r *FavoriteBookRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting favorite books by reader ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var books []book
+	for _, bookID := range r.store.data.FavoriteBooks[readerID] {
+		if record, ok := r.store.data.Books[bookID]; ok && record.DeletedAt == nil {
+			books = append(books, record)
+		}
+	}
+	slices.SortFunc(books, func(a, b book) int {
+		return cmp.Or(cmp.Compare(a.Title, b.Title), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	books = page(books, limit, offset)
+	if len(books) == 0 {
+		logger.Warn("favorite books not found")
+		return nil, favorite.ErrFavoriteBookDoesNotExists
+	}
+
+	favoriteBooks := make([]*models.BookModel, len(books))
+	for i := range books {
+		favoriteBooks[i] = books[i].model()
+	}
+
+	logger.Infof("found %d favorite books", len(favoriteBooks))
+
+	return favoriteBooks, nil
+}
+
+func (r *FavoriteBookRepo) Delete(ctx context.Context, readerID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting favorite book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	bookIDs := r.store.data.FavoriteBooks[readerID]
+	if !slices.Contains(bookIDs, bookID) {
+		logger.Warn("favorite book not found")
+		return favorite.ErrFavoriteBookDoesNotExists
+	}
+	r.store.data.FavoriteBooks[readerID] = slices.DeleteFunc(slices.Clone(bookIDs), func(ID uuid.UUID) bool { return ID == bookID })
+
+	logger.Info("successfully deleted favorite book")
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type LibCardRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewLibCardRepo(store *Store, logger *logrus.Entry) intfRepo.ILibCardRepo {
+	return &LibCardRepo{store: store, logger: logger}
+}
+
+func (r *LibCardRepo) Create(ctx context.Context, model *models.LibCardModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting lib card into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var record libCard
+	record.setModel(model)
+	r.store.data.LibCards[model.ID] = record
+
+	logger.Info("successfully inserted lib card")
+
+	return nil
+}
+
+func (r *LibCardRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*models.LibCardModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting lib card by reader ID")
+
+	return r.getOne(ctx, func(record *libCard) bool { return record.ReaderID == readerID })
+}
+
+func (r *LibCardRepo) GetByNum(ctx context.Context, libCardNum string) (*models.LibCardModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting lib card by num")
+
+	return r.getOne(ctx, func(record *libCard) bool { return record.LibCardNum == libCardNum })
+}
+
+func (r *LibCardRepo) Update(ctx context.Context, model *models.LibCardModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating lib card")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.LibCards[model.ID]
+	if !ok {
+		logger.Warn("lib card not found")
+		return errs.ErrLibCardDoesNotExists
+	}
+	record.setModel(model)
+	r.store.data.LibCards[model.ID] = record
+
+	logger.Info("successfully updated lib card")
+
+	return nil
+}
+
+func (r *LibCardRepo) getOne(ctx context.Context, match func(*libCard) bool) (*models.LibCardModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, record := range r.store.data.LibCards {
+		if match(&record) {
+			return record.model(time.Now()), nil
+		}
+	}
+
+	logger.Warn("lib card not found")
+
+	return nil, errs.ErrLibCardDoesNotExists
+}
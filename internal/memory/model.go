@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/internal/readinglist"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"slices"
+	"time"
+)
+
+// Записи хранилища повторяют строки таблиц Postgres вместе со служебными
+// столбцами version и deleted_at, которых нет в моделях
+
+type book struct {
+	ID             uuid.UUID  `json:"id"`
+	Title          string     `json:"title"`
+	Author         string     `json:"author"`
+	Publisher      string     `json:"publisher"`
+	CopiesNumber   uint       `json:"copies_number"`
+	Rarity         string     `json:"rarity"`
+	Genre          string     `json:"genre"`
+	PublishingYear uint       `json:"publishing_year"`
+	Language       string     `json:"language"`
+	AgeLimit       uint       `json:"age_limit"`
+	Version        int64      `json:"version"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+type reader struct {
+	ID          uuid.UUID  `json:"id"`
+	Fio         string     `json:"fio"`
+	PhoneNumber string     `json:"phone_number"`
+	Age         uint       `json:"age"`
+	Password    string     `json:"password"`
+	Role        string     `json:"role"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type libCard struct {
+	ID           uuid.UUID `json:"id"`
+	ReaderID     uuid.UUID `json:"reader_id"`
+	LibCardNum   string    `json:"lib_card_num"`
+	Validity     int       `json:"validity"`
+	IssueDate    time.Time `json:"issue_date"`
+	ActionStatus bool      `json:"action_status"`
+	Version      int64     `json:"version"`
+}
+
+type reservation struct {
+	ID         uuid.UUID `json:"id"`
+	ReaderID   uuid.UUID `json:"reader_id"`
+	BookID     uuid.UUID `json:"book_id"`
+	IssueDate  time.Time `json:"issue_date"`
+	ReturnDate time.Time `json:"return_date"`
+	State      string    `json:"state"`
+	Version    int64     `json:"version"`
+}
+
+type rating struct {
+	ID       uuid.UUID `json:"id"`
+	ReaderID uuid.UUID `json:"reader_id"`
+	BookID   uuid.UUID `json:"book_id"`
+	Review   string    `json:"review"`
+	Rating   int       `json:"rating"`
+}
+
+type refreshToken struct {
+	ReaderID  uuid.UUID `json:"reader_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type readingList struct {
+	ID         uuid.UUID   `json:"id"`
+	ReaderID   uuid.UUID   `json:"reader_id"`
+	Name       string      `json:"name"`
+	ShareToken string      `json:"share_token,omitempty"`
+	BookIDs    []uuid.UUID `json:"book_ids"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type twoFactor struct {
+	ReaderID      uuid.UUID `json:"reader_id"`
+	Secret        string    `json:"secret"`
+	Enabled       bool      `json:"enabled"`
+	RecoveryCodes []string  `json:"recovery_codes"`
+	LastUsedStep  int64     `json:"last_used_step"`
+}
+
+type coReservation struct {
+	BookID       uuid.UUID `json:"book_id"`
+	ReadersCount uint      `json:"readers_count"`
+}
+
+func anonymousReader() reader {
+	return reader{
+		ID:          account.AnonymousReaderID,
+		Fio:         account.AnonymousReaderFio,
+		PhoneNumber: "00000000000",
+		Role:        "Reader",
+	}
+}
+
+func (b *book) model() *models.BookModel {
+	return &models.BookModel{
+		ID:             b.ID,
+		Title:          b.Title,
+		Author:         b.Author,
+		Publisher:      b.Publisher,
+		CopiesNumber:   b.CopiesNumber,
+		Rarity:         b.Rarity,
+		Genre:          b.Genre,
+		PublishingYear: b.PublishingYear,
+		Language:       b.Language,
+		AgeLimit:       b.AgeLimit,
+	}
+}
+
+// setModel переносит поля модели в запись, не трогая служебные поля
+func (b *book) setModel(model *models.BookModel) {
+	b.ID = model.ID
+	b.Title = model.Title
+	b.Author = model.Author
+	b.Publisher = model.Publisher
+	b.CopiesNumber = model.CopiesNumber
+	b.Rarity = model.Rarity
+	b.Genre = model.Genre
+	b.PublishingYear = model.PublishingYear
+	b.Language = model.Language
+	b.AgeLimit = model.AgeLimit
+}
+
+func (r *reader) model() *models.ReaderModel {
+	return &models.ReaderModel{
+		ID:          r.ID,
+		Fio:         r.Fio,
+		PhoneNumber: r.PhoneNumber,
+		Age:         r.Age,
+		Password:    r.Password,
+		Role:        r.Role,
+	}
+}
+
+func newReader(model *models.ReaderModel) reader {
+	return reader{
+		ID:          model.ID,
+		Fio:         model.Fio,
+		PhoneNumber: model.PhoneNumber,
+		Age:         model.Age,
+		Password:    model.Password,
+		Role:        model.Role,
+	}
+}
+
+// model считает действительность билета так же, как lib_card_view:
+// билет действует validity дней с даты выдачи
+func (l *libCard) model(now time.Time) *models.LibCardModel {
+	return &models.LibCardModel{
+		ID:           l.ID,
+		ReaderID:     l.ReaderID,
+		LibCardNum:   l.LibCardNum,
+		Validity:     l.Validity,
+		IssueDate:    l.IssueDate,
+		ActionStatus: l.ActionStatus && now.Before(l.IssueDate.AddDate(0, 0, l.Validity)),
+	}
+}
+
+func (l *libCard) setModel(model *models.LibCardModel) {
+	l.ID = model.ID
+	l.ReaderID = model.ReaderID
+	l.LibCardNum = model.LibCardNum
+	l.Validity = model.Validity
+	l.IssueDate = model.IssueDate
+	l.ActionStatus = model.ActionStatus
+}
+
+// model считает состояние так же, как reservation_view: незакрытое
+// бронирование после даты возврата просрочено
+func (r *reservation) model(today time.Time) *models.ReservationModel {
+	return &models.ReservationModel{
+		ID:         r.ID,
+		ReaderID:   r.ReaderID,
+		BookID:     r.BookID,
+		IssueDate:  r.IssueDate,
+		ReturnDate: r.ReturnDate,
+		State:      r.state(today),
+	}
+}
+
+func (r *reservation) state(today time.Time) string {
+	if r.State != impl.ReservationClosed && r.ReturnDate.Before(today) {
+		return impl.ReservationExpired
+	}
+
+	return r.State
+}
+
+func (r *reservation) setModel(model *models.ReservationModel) {
+	r.ID = model.ID
+	r.ReaderID = model.ReaderID
+	r.BookID = model.BookID
+	r.IssueDate = model.IssueDate
+	r.ReturnDate = model.ReturnDate
+	r.State = model.State
+}
+
+func (r *rating) model() *models.RatingModel {
+	return &models.RatingModel{
+		ID:       r.ID,
+		ReaderID: r.ReaderID,
+		BookID:   r.BookID,
+		Review:   r.Review,
+		Rating:   r.Rating,
+	}
+}
+
+func newRating(model *models.RatingModel) rating {
+	return rating{
+		ID:       model.ID,
+		ReaderID: model.ReaderID,
+		BookID:   model.BookID,
+		Review:   model.Review,
+		Rating:   model.Rating,
+	}
+}
+
+func (r *readingList) model() *readinglist.ReadingListModel {
+	return &readinglist.ReadingListModel{
+		ID:         r.ID,
+		ReaderID:   r.ReaderID,
+		Name:       r.Name,
+		ShareToken: r.ShareToken,
+		BookIDs:    slices.Clone(r.BookIDs),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func newReadingList(model *readinglist.ReadingListModel) readingList {
+	return readingList{
+		ID:         model.ID,
+		ReaderID:   model.ReaderID,
+		Name:       model.Name,
+		ShareToken: model.ShareToken,
+		BookIDs:    slices.Clone(model.BookIDs),
+		CreatedAt:  model.CreatedAt,
+	}
+}
+
+func (t *twoFactor) model() *twofactor.TwoFactorModel {
+	return &twofactor.TwoFactorModel{
+		ReaderID:           t.ReaderID,
+		Secret:             t.Secret,
+		Enabled:            t.Enabled,
+		RecoveryCodeHashes: slices.Clone(t.RecoveryCodes),
+		LastUsedStep:       t.LastUsedStep,
+	}
+}
+
+func newTwoFactor(model *twofactor.TwoFactorModel) twoFactor {
+	return twoFactor{
+		ReaderID:      model.ReaderID,
+		Secret:        model.Secret,
+		Enabled:       model.Enabled,
+		RecoveryCodes: slices.Clone(model.RecoveryCodeHashes),
+		LastUsedStep:  model.LastUsedStep,
+	}
+}
+
+// today - начало текущих суток, с которым сравнивается дата возврата
+func today(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
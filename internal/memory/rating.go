@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+type RatingRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewRatingRepo(store *Store, logger *logrus.Entry) intfRepo.IRatingRepo {
+	return &RatingRepo{store: store, logger: logger}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, model *models.RatingModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting rating into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.store.data.Ratings[model.ID] = newRating(model)
+
+	logger.Info("successfully inserted rating")
+
+	return nil
+}
+
+func (r *RatingRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (*models.RatingModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting rating by reader and book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, record := range r.store.data.Ratings {
+		if record.ReaderID == readerID && record.BookID == bookID {
+			return record.model(), nil
+		}
+	}
+
+	logger.Warn("rating not found")
+
+	return nil, errs.ErrRatingDoesNotExists
+}
+
+// GetByBookID возвращает отзывы о книге в порядке идентификаторов, чтобы
+// страницы не пересекались
+func (r *RatingRepo) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]*models.RatingModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting ratings by book ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records := make([]rating, 0)
+	for _, record := range r.store.data.Ratings {
+		if record.BookID == bookID {
+			records = append(records, record)
+		}
+	}
+	slices.SortFunc(records, func(a, b rating) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	records = page(records, limit, offset)
+	if len(records) == 0 {
+		logger.Warn("ratings not found")
+		return nil, errs.ErrRatingDoesNotExists
+	}
+
+	ratings := make([]*models.RatingModel, len(records))
+	for i := range records {
+		ratings[i] = records[i].model()
+	}
+
+	return ratings, nil
+}
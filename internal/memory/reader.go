@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"time"
+)
+
+type ReaderRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewReaderRepo(store *Store, logger *logrus.Entry) intfRepo.IReaderRepo {
+	return &ReaderRepo{store: store, logger: logger}
+}
+
+func (r *ReaderRepo) Create(ctx context.Context, model *models.ReaderModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting reader into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.store.data.Readers[model.ID] = newReader(model)
+
+	logger.Info("successfully inserted reader")
+
+	return nil
+}
+
+func (r *ReaderRepo) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader by phone number")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, record := range r.store.data.Readers {
+		if record.PhoneNumber == phoneNumber {
+			return record.model(), nil
+		}
+	}
+
+	logger.Warn("reader not found")
+
+	return nil, errs.ErrReaderDoesNotExists
+}
+
+func (r *ReaderRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader by ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Readers[ID]
+	if !ok {
+		logger.Warn("reader not found")
+		return nil, errs.ErrReaderDoesNotExists
+	}
+
+	return record.model(), nil
+}
+
+func (r *ReaderRepo) IsFavorite(ctx context.Context, readerID, bookID uuid.UUID) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("checking favorite book")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	return slices.Contains(r.store.data.FavoriteBooks[readerID], bookID), nil
+}
+
+func (r *ReaderRepo) AddToFavorites(ctx context.Context, readerID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("adding book to favorites")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	bookIDs := r.store.data.FavoriteBooks[readerID]
+	if !slices.Contains(bookIDs, bookID) {
+		r.store.data.FavoriteBooks[readerID] = append(slices.Clone(bookIDs), bookID)
+	}
+
+	logger.Info("successfully added book to favorites")
+
+	return nil
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, ID uuid.UUID, token string, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("saving refresh token")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for key, record := range r.store.data.RefreshTokens {
+		if !now.Before(record.ExpiresAt) {
+			delete(r.store.data.RefreshTokens, key)
+		}
+	}
+	r.store.data.RefreshTokens[token] = refreshToken{ReaderID: ID, ExpiresAt: now.Add(ttl)}
+
+	logger.Info("successfully saved refresh token")
+
+	return nil
+}
+
+func (r *ReaderRepo) GetByRefreshToken(ctx context.Context, token string) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader by refresh token")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stored, ok := r.store.data.RefreshTokens[token]
+	if !ok || !time.Now().Before(stored.ExpiresAt) {
+		logger.Warn("refresh token not found")
+		return nil, errs.ErrReaderDoesNotExists
+	}
+
+	record, ok := r.store.data.Readers[stored.ReaderID]
+	if !ok {
+		logger.Warn("reader not found")
+		return nil, errs.ErrReaderDoesNotExists
+	}
+
+	return record.model(), nil
+}
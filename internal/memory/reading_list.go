@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/readinglist"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+type ReadingListRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewReadingListRepo(store *Store, logger *logrus.Entry) readinglist.IReadingListRepo {
+	return &ReadingListRepo{store: store, logger: logger}
+}
+
+func (r *ReadingListRepo) Create(ctx context.Context, model *readinglist.ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting reading list into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.store.data.ReadingLists[model.ID] = newReadingList(model)
+
+	logger.Info("successfully inserted reading list")
+
+	return nil
+}
+
+func (r *ReadingListRepo) GetByID(ctx context.Context, ID uuid.UUID) (*readinglist.ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by ID")
+
+	return r.getOne(ctx, func(record *readingList) bool { return record.ID == ID })
+}
+
+func (r *ReadingListRepo) GetByShareToken(ctx context.Context, shareToken string) (*readinglist.ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by share token")
+
+	return r.getOne(ctx, func(record *readingList) bool { return record.ShareToken != "" && record.ShareToken == shareToken })
+}
+
+func (r *ReadingListRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*readinglist.ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading lists by reader ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records := make([]readingList, 0)
+	for _, record := range r.store.data.ReadingLists {
+		if record.ReaderID == readerID {
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		logger.Warn("reading lists not found")
+		return nil, readinglist.ErrReadingListDoesNotExists
+	}
+	slices.SortFunc(records, func(a, b readingList) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	readingLists := make([]*readinglist.ReadingListModel, len(records))
+	for i := range records {
+		readingLists[i] = records[i].model()
+	}
+
+	return readingLists, nil
+}
+
+func (r *ReadingListRepo) Update(ctx context.Context, model *readinglist.ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reading list")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.store.data.ReadingLists[model.ID]; ok {
+		r.store.data.ReadingLists[model.ID] = newReadingList(model)
+	}
+
+	logger.Info("successfully updated reading list")
+
+	return nil
+}
+
+func (r *ReadingListRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reading list")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	delete(r.store.data.ReadingLists, ID)
+
+	logger.Info("successfully deleted reading list")
+
+	return nil
+}
+
+func (r *ReadingListRepo) getOne(ctx context.Context, match func(*readingList) bool) (*readinglist.ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, record := range r.store.data.ReadingLists {
+		if match(&record) {
+			return record.model(), nil
+		}
+	}
+
+	logger.Warn("reading list not found")
+
+	return nil, readinglist.ErrReadingListDoesNotExists
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+	"time"
+)
+
+type ReservationRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewReservationRepo(store *Store, logger *logrus.Entry) intfRepo.IReservationRepo {
+	return &ReservationRepo{store: store, logger: logger}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, model *models.ReservationModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting reservation into store")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var record reservation
+	record.setModel(model)
+	r.store.data.Reservations[model.ID] = record
+
+	logger.Info("successfully inserted reservation")
+
+	return nil
+}
+
+func (r *ReservationRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) ([]*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reservations by reader and book")
+
+	return r.find(ctx, func(record *reservation, _ time.Time) bool {
+		return record.ReaderID == readerID && record.BookID == bookID
+	})
+}
+
+func (r *ReservationRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reservation by ID")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Reservations[ID]
+	if !ok {
+		logger.Warn("reservation not found")
+		return nil, errs.ErrReservationDoesNotExists
+	}
+
+	return record.model(today(time.Now())), nil
+}
+
+func (r *ReservationRepo) GetByBookID(ctx context.Context, bookID uuid.UUID) ([]*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reservations by book ID")
+
+	reservations, err := r.find(ctx, func(record *reservation, _ time.Time) bool { return record.BookID == bookID })
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		logger.Warn("reservations not found")
+		return nil, errs.ErrReservationDoesNotExists
+	}
+
+	return reservations, nil
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, model *models.ReservationModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reservation")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.Reservations[model.ID]
+	if !ok {
+		logger.Warn("reservation not found")
+		return errs.ErrReservationDoesNotExists
+	}
+	record.setModel(model)
+	r.store.data.Reservations[model.ID] = record
+
+	logger.Info("successfully updated reservation")
+
+	return nil
+}
+
+func (r *ReservationRepo) GetExpiredByReaderID(ctx context.Context, readerID uuid.UUID) ([]*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting expired reservations by reader ID")
+
+	return r.find(ctx, func(record *reservation, today time.Time) bool {
+		return record.ReaderID == readerID && record.state(today) == impl.ReservationExpired
+	})
+}
+
+func (r *ReservationRepo) GetActiveByReaderID(ctx context.Context, readerID uuid.UUID) ([]*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting active reservations by reader ID")
+
+	return r.find(ctx, func(record *reservation, today time.Time) bool {
+		state := record.state(today)
+		return record.ReaderID == readerID && (state == impl.ReservationIssued || state == impl.ReservationExtended)
+	})
+}
+
+func (r *ReservationRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.ReservationModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reservations by reader ID")
+
+	reservations, err := r.find(ctx, func(record *reservation, _ time.Time) bool { return record.ReaderID == readerID })
+	if err != nil {
+		return nil, err
+	}
+
+	return page(reservations, limit, offset), nil
+}
+
+// find возвращает подходящие бронирования, начиная с последних выданных
+func (r *ReservationRepo) find(ctx context.Context, match func(*reservation, time.Time) bool) ([]*models.ReservationModel, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	now := today(time.Now())
+
+	records := make([]reservation, 0)
+	for _, record := range r.store.data.Reservations {
+		if match(&record, now) {
+			records = append(records, record)
+		}
+	}
+	slices.SortFunc(records, func(a, b reservation) int {
+		return cmp.Or(b.IssueDate.Compare(a.IssueDate), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	var reservations []*models.ReservationModel
+	for i := range records {
+		reservations = append(reservations, records[i].model(now))
+	}
+
+	return reservations, nil
+}
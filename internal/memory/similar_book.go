@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/internal/similar"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+type SimilarBookRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewSimilarBookRepo(store *Store, logger *logrus.Entry) similar.ISimilarBookRepo {
+	return &SimilarBookRepo{store: store, logger: logger}
+}
+
+func (r *SimilarBookRepo) Refresh(ctx context.Context) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("refreshing book co-reservations")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	readerBooks := make(map[uuid.UUID]map[uuid.UUID]struct{})
+	for _, record := range r.store.data.Reservations {
+		if _, ok := readerBooks[record.ReaderID]; !ok {
+			readerBooks[record.ReaderID] = make(map[uuid.UUID]struct{})
+		}
+		readerBooks[record.ReaderID][record.BookID] = struct{}{}
+	}
+
+	counts := make(map[uuid.UUID]map[uuid.UUID]uint)
+	for _, reserved := range readerBooks {
+		for bookID := range reserved {
+			for coBookID := range reserved {
+				if bookID == coBookID {
+					continue
+				}
+				if _, ok := counts[bookID]; !ok {
+					counts[bookID] = make(map[uuid.UUID]uint)
+				}
+				counts[bookID][coBookID]++
+			}
+		}
+	}
+
+	coReservations := make(map[uuid.UUID][]coReservation, len(counts))
+	for bookID, coBooks := range counts {
+		records := make([]coReservation, 0, len(coBooks))
+		for coBookID, readersCount := range coBooks {
+			records = append(records, coReservation{BookID: coBookID, ReadersCount: readersCount})
+		}
+		slices.SortFunc(records, func(a, b coReservation) int {
+			return cmp.Or(cmp.Compare(b.ReadersCount, a.ReadersCount), strings.Compare(a.BookID.String(), b.BookID.String()))
+		})
+		coReservations[bookID] = records[:min(len(records), similar.MaxCoReservedPerBook)]
+	}
+	r.store.data.CoReservations = coReservations
+
+	return nil
+}
+
+func (r *SimilarBookRepo) GetByBook(ctx context.Context, model *models.BookModel, limit, offset int) ([]*similar.ScoredBook, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting similar books")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	scores := make(map[uuid.UUID]float64)
+	for _, record := range r.store.data.CoReservations[model.ID] {
+		scores[record.BookID] += similar.CoReservationWeight * float64(record.ReadersCount)
+	}
+
+	author := similar.Normalize(model.Author)
+	genres := similar.Genres(model.Genre)
+	for _, record := range r.store.data.Books {
+		if author != "" && similar.Normalize(record.Author) == author {
+			scores[record.ID] += similar.SameAuthorWeight
+		}
+		for _, genre := range similar.Genres(record.Genre) {
+			if slices.Contains(genres, genre) {
+				scores[record.ID] += similar.SharedGenreWeight
+			}
+		}
+	}
+	delete(scores, model.ID)
+
+	similarBooks := make([]*similar.ScoredBook, 0, len(scores))
+	for bookID, score := range scores {
+		if record, ok := r.store.data.Books[bookID]; ok && record.DeletedAt == nil {
+			similarBooks = append(similarBooks, &similar.ScoredBook{Book: record.model(), Score: score})
+		}
+	}
+	slices.SortFunc(similarBooks, func(a, b *similar.ScoredBook) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), strings.Compare(a.Book.ID.String(), b.Book.ID.String()))
+	})
+
+	return page(similarBooks, limit, offset), nil
+}
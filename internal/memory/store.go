@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// data - содержимое хранилища. Записи хранятся по значению, а срезы в них
+// при изменении заменяются копией, поэтому для отката транзакции
+// достаточно поверхностной копии словарей
+type data struct {
+	Books         map[uuid.UUID]book        `json:"books"`
+	Readers       map[uuid.UUID]reader      `json:"readers"`
+	LibCards      map[uuid.UUID]libCard     `json:"lib_cards"`
+	Reservations  map[uuid.UUID]reservation `json:"reservations"`
+	Ratings       map[uuid.UUID]rating      `json:"ratings"`
+	FavoriteBooks map[uuid.UUID][]uuid.UUID `json:"favorite_books"`
+	RefreshTokens map[string]refreshToken   `json:"refresh_tokens"`
+	ReadingLists  map[uuid.UUID]readingList `json:"reading_lists"`
+	TwoFactors    map[uuid.UUID]twoFactor   `json:"two_factors"`
+	// CoReservations - таблица совместных бронирований по книгам
+	CoReservations map[uuid.UUID][]coReservation `json:"co_reservations"`
+}
+
+func newData() *data {
+	return &data{
+		Books:          map[uuid.UUID]book{},
+		Readers:        map[uuid.UUID]reader{},
+		LibCards:       map[uuid.UUID]libCard{},
+		Reservations:   map[uuid.UUID]reservation{},
+		Ratings:        map[uuid.UUID]rating{},
+		FavoriteBooks:  map[uuid.UUID][]uuid.UUID{},
+		RefreshTokens:  map[string]refreshToken{},
+		ReadingLists:   map[uuid.UUID]readingList{},
+		TwoFactors:     map[uuid.UUID]twoFactor{},
+		CoReservations: map[uuid.UUID][]coReservation{},
+	}
+}
+
+func (d *data) clone() *data {
+	return &data{
+		Books:          maps.Clone(d.Books),
+		Readers:        maps.Clone(d.Readers),
+		LibCards:       maps.Clone(d.LibCards),
+		Reservations:   maps.Clone(d.Reservations),
+		Ratings:        maps.Clone(d.Ratings),
+		FavoriteBooks:  maps.Clone(d.FavoriteBooks),
+		RefreshTokens:  maps.Clone(d.RefreshTokens),
+		ReadingLists:   maps.Clone(d.ReadingLists),
+		TwoFactors:     maps.Clone(d.TwoFactors),
+		CoReservations: maps.Clone(d.CoReservations),
+	}
+}
+
+// Store хранит данные всех репозиториев в памяти процесса. Транзакции
+// выполняются по одной: транзакция держит хранилище до фиксации или
+// отката, поэтому их изоляция - serializable. Вне транзакции каждый
+// вызов репозитория атомарен сам по себе
+type Store struct {
+	// sem - мьютекс, захват которого можно прервать отменой контекста
+	sem    chan struct{}
+	data   *data
+	logger *logrus.Entry
+}
+
+func NewStore(logger *logrus.Entry) *Store {
+	s := &Store{sem: make(chan struct{}, 1), data: newData(), logger: logger}
+	s.data.Readers[account.AnonymousReaderID] = anonymousReader()
+
+	return s
+}
+
+// TrFactory создает транзакции хранилища для manager.New, поэтому с ним
+// работает тот же transact.ITransactionManager, что и с Postgres и Mongo
+func (s *Store) TrFactory(ctx context.Context, _ trm.Settings) (context.Context, trm.Transaction, error) {
+	if err := s.lock(ctx); err != nil {
+		return ctx, nil, err
+	}
+
+	return ctx, &transaction{store: s, backup: s.data.clone(), closed: make(chan struct{})}, nil
+}
+
+// acquire захватывает хранилище на время одного вызова репозитория. Внутри
+// транзакции хранилище уже захвачено, и acquire ничего не делает
+func (s *Store) acquire(ctx context.Context) (func(), error) {
+	if tr := trmcontext.DefaultManager.Default(ctx); tr != nil && tr.IsActive() && tr.Transaction() == s {
+		return func() {}, nil
+	}
+
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.unlock, nil
+}
+
+func (s *Store) lock(ctx context.Context) error {
+	select {
+	case s.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Store) unlock() {
+	<-s.sem
+}
+
+// Load заменяет данные хранилища содержимым снимка. Если файла нет,
+// хранилище остается пустым
+func (s *Store) Load(ctx context.Context, path string) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Infof("loading memory snapshot from %s", path)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info("memory snapshot not found, starting with empty store")
+		return nil
+	}
+	if err != nil {
+		logger.Errorf("error reading memory snapshot: %v", err)
+		return err
+	}
+
+	loaded := newData()
+	if err = json.Unmarshal(content, loaded); err != nil {
+		logger.Errorf("error decoding memory snapshot: %v", err)
+		return err
+	}
+	if _, ok := loaded.Readers[account.AnonymousReaderID]; !ok {
+		loaded.Readers[account.AnonymousReaderID] = anonymousReader()
+	}
+
+	if err = s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.unlock()
+
+	s.data = loaded
+
+	logger.Info("successfully loaded memory snapshot")
+
+	return nil
+}
+
+// Save записывает снимок хранилища в JSON-файл. Файл заменяется целиком,
+// поэтому прерванное сохранение не портит предыдущий снимок
+func (s *Store) Save(ctx context.Context, path string) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Infof("saving memory snapshot to %s", path)
+
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	s.unlock()
+	if err != nil {
+		logger.Errorf("error encoding memory snapshot: %v", err)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		logger.Errorf("error creating memory snapshot: %v", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		logger.Errorf("error writing memory snapshot: %v", err)
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		logger.Errorf("error writing memory snapshot: %v", err)
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		logger.Errorf("error replacing memory snapshot: %v", err)
+		return err
+	}
+
+	logger.Info("successfully saved memory snapshot")
+
+	return nil
+}
+
+// transaction держит хранилище захваченным; откат возвращает копию данных,
+// снятую при начале транзакции
+type transaction struct {
+	store  *Store
+	backup *data
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (t *transaction) Transaction() interface{} {
+	return t.store
+}
+
+func (t *transaction) Commit(context.Context) error {
+	if !t.close() {
+		return trm.ErrAlreadyClosed
+	}
+
+	return nil
+}
+
+func (t *transaction) Rollback(context.Context) error {
+	backup := t.backup
+	if !t.close(func() { t.store.data = backup }) {
+		return trm.ErrAlreadyClosed
+	}
+
+	return nil
+}
+
+func (t *transaction) IsActive() bool {
+	select {
+	case <-t.closed:
+		return false
+	default:
+		return true
+	}
+}
+
+func (t *transaction) Closed() <-chan struct{} {
+	return t.closed
+}
+
+// close завершает транзакцию и отпускает хранилище; before выполняется,
+// пока хранилище еще захвачено. Возвращает false, если транзакция уже
+// завершена
+func (t *transaction) close(before ...func()) bool {
+	closed := false
+	t.once.Do(func() {
+		for _, fn := range before {
+			fn()
+		}
+		t.backup = nil
+		close(t.closed)
+		t.store.unlock()
+		closed = true
+	})
+
+	return closed
+}
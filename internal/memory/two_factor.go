@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"slices"
+)
+
+type TwoFactorRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewTwoFactorRepo(store *Store, logger *logrus.Entry) twofactor.ITwoFactorRepo {
+	return &TwoFactorRepo{store: store, logger: logger}
+}
+
+func (r *TwoFactorRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*twofactor.TwoFactorModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader two-factor settings")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.TwoFactors[readerID]
+	if !ok {
+		logger.Warn("reader two-factor settings not found")
+		return nil, twofactor.ErrTwoFactorNotEnabled
+	}
+
+	return record.model(), nil
+}
+
+func (r *TwoFactorRepo) Save(ctx context.Context, model *twofactor.TwoFactorModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("saving reader two-factor settings")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.store.data.TwoFactors[model.ReaderID] = newTwoFactor(model)
+
+	logger.Info("successfully saved reader two-factor settings")
+
+	return nil
+}
+
+func (r *TwoFactorRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader two-factor settings")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	delete(r.store.data.TwoFactors, readerID)
+
+	logger.Info("successfully deleted reader two-factor settings")
+
+	return nil
+}
+
+func (r *TwoFactorRepo) UseStep(ctx context.Context, readerID uuid.UUID, step int64) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader two-factor last used step")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.TwoFactors[readerID]
+	if !ok || record.LastUsedStep >= step {
+		return false, nil
+	}
+
+	record.LastUsedStep = step
+	r.store.data.TwoFactors[readerID] = record
+
+	return true, nil
+}
+
+func (r *TwoFactorRepo) UseRecoveryCode(ctx context.Context, readerID uuid.UUID, codeHash string) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("removing reader recovery code")
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.TwoFactors[readerID]
+	if !ok {
+		return false, nil
+	}
+
+	index := slices.Index(record.RecoveryCodes, codeHash)
+	if index < 0 {
+		return false, nil
+	}
+
+	record.RecoveryCodes = slices.Delete(slices.Clone(record.RecoveryCodes), index, index+1)
+	r.store.data.TwoFactors[readerID] = record
+
+	return true, nil
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// VersionRepo хранит версии в записях книг, читательских билетов и бронирований
+type VersionRepo struct {
+	store  *Store
+	logger *logrus.Entry
+}
+
+func NewVersionRepo(store *Store, logger *logrus.Entry) optimistic.IVersionRepo {
+	return &VersionRepo{store: store, logger: logger}
+}
+
+func (r *VersionRepo) Get(ctx context.Context, entity, field string, value uuid.UUID) (uuid.UUID, int64, bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return uuid.Nil, 0, false, err
+	}
+	defer unlock()
+
+	d := r.store.data
+	switch {
+	case entity == optimistic.EntityBook && field == optimistic.FieldID:
+		record, ok := d.Books[value]
+		return value, record.Version, ok, nil
+	case entity == optimistic.EntityLibCard && field == optimistic.FieldID:
+		record, ok := d.LibCards[value]
+		return value, record.Version, ok, nil
+	case entity == optimistic.EntityLibCard && field == optimistic.FieldReaderID:
+		for ID, record := range d.LibCards {
+			if record.ReaderID == value {
+				return ID, record.Version, true, nil
+			}
+		}
+		return uuid.Nil, 0, false, nil
+	case entity == optimistic.EntityReservation && field == optimistic.FieldID:
+		record, ok := d.Reservations[value]
+		return value, record.Version, ok, nil
+	}
+
+	err = fmt.Errorf("unknown version field %s.%s", entity, field)
+	logger.Errorf("error getting %s version: %v", entity, err)
+
+	return uuid.Nil, 0, false, err
+}
+
+func (r *VersionRepo) CompareAndSwap(ctx context.Context, entity string, ID uuid.UUID, expected int64) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	version, found, err := r.increment(ctx, entity, ID, func(version int64) bool { return version == expected })
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+	if !found {
+		logger.Warnf("%s was modified concurrently", entity)
+		return 0, optimistic.ErrConcurrentModification
+	}
+
+	return version, nil
+}
+
+func (r *VersionRepo) Increment(ctx context.Context, entity string, ID uuid.UUID) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	version, _, err := r.increment(ctx, entity, ID, func(int64) bool { return true })
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// increment увеличивает версию сущности, если она удовлетворяет matches.
+// found = false, если сущности нет или версия не подошла
+func (r *VersionRepo) increment(ctx context.Context, entity string, ID uuid.UUID, matches func(int64) bool) (int64, bool, error) {
+	unlock, err := r.store.acquire(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer unlock()
+
+	d := r.store.data
+	switch entity {
+	case optimistic.EntityBook:
+		record, ok := d.Books[ID]
+		if !ok || !matches(record.Version) {
+			return 0, false, nil
+		}
+		record.Version++
+		d.Books[ID] = record
+		return record.Version, true, nil
+	case optimistic.EntityLibCard:
+		record, ok := d.LibCards[ID]
+		if !ok || !matches(record.Version) {
+			return 0, false, nil
+		}
+		record.Version++
+		d.LibCards[ID] = record
+		return record.Version, true, nil
+	case optimistic.EntityReservation:
+		record, ok := d.Reservations[ID]
+		if !ok || !matches(record.Version) {
+			return 0, false, nil
+		}
+		record.Version++
+		d.Reservations[ID] = record
+		return record.Version, true, nil
+	}
+
+	return 0, false, fmt.Errorf("unknown versioned entity %s", entity)
+}
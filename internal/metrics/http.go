@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	signInRoute          = "/api/v1/auth/sign-in"
+	twoFactorSignInRoute = "/api/v1/auth/sign-in/2fa"
+)
+
+// HTTPMiddleware измеряет время обработки запросов и считает попытки входа.
+// Вход, на который выдан challenge, считается успешным только после проверки
+// кода второго фактора
+func (m *Metrics) HTTPMiddleware(matcher *middleware.RouteMatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		if c.Request.Method == http.MethodPost && c.Request.URL.Path == signInRoute {
+			c.Request = c.Request.WithContext(twofactor.WithChallengeFlag(c.Request.Context()))
+		}
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = matcher.Match(c.Request.Method, c.Request.URL.Path)
+		}
+		status := c.Writer.Status()
+
+		m.httpRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+
+		if c.Request.Method == http.MethodPost && (route == signInRoute || route == twoFactorSignInRoute) {
+			m.observeSignIn(route, status, twofactor.ChallengeIssued(c.Request.Context()))
+		}
+	}
+}
+
+func (m *Metrics) observeSignIn(route string, status int, challenged bool) {
+	switch {
+	case status == http.StatusOK && challenged:
+		m.signIns.WithLabelValues("challenge").Inc()
+	case status == http.StatusOK:
+		m.signIns.WithLabelValues("success").Inc()
+	case route == signInRoute && (status == http.StatusNotFound || status == http.StatusConflict),
+		route == twoFactorSignInRoute && status == http.StatusUnauthorized:
+		m.signIns.WithLabelValues("failure").Inc()
+	case status == http.StatusTooManyRequests:
+		m.signIns.WithLabelValues("throttled").Inc()
+	default:
+		m.signIns.WithLabelValues("error").Inc()
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "booksmart"
+
+// Metrics - метрики приложения: HTTP-запросы, вызовы репозиториев и
+// бизнес-события. Все метрики регистрируются в переданном реестре
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequestDuration  *prometheus.HistogramVec
+	repoCallDuration     *prometheus.HistogramVec
+	reservationsCreated  prometheus.Counter
+	reservationsExtended prometheus.Counter
+	signIns              *prometheus.CounterVec
+}
+
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: registry,
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repoCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repo_call_duration_seconds",
+			Help:      "Repository call latency by backend, repository, method and result.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"backend", "repo", "method", "result"}),
+		reservationsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_created_total",
+			Help:      "Number of created reservations.",
+		}),
+		reservationsExtended: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_extended_total",
+			Help:      "Number of reservation extensions.",
+		}),
+		signIns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sign_ins_total",
+			Help:      "Number of sign-in attempts by result.",
+		}, []string{"result"}),
+	}
+
+	registry.MustRegister(
+		m.httpRequestDuration,
+		m.repoCallDuration,
+		m.reservationsCreated,
+		m.reservationsExtended,
+		m.signIns,
+	)
+
+	return m
+}
+
+// NewRegistry возвращает реестр со стандартными метриками Go-рантайма и процесса
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPoolCollector отдает статистику пула соединений клиента Redis
+type RedisPoolCollector struct {
+	client *redis.Client
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+func NewRedisPoolCollector(client *redis.Client) *RedisPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", name), help, nil, nil)
+	}
+
+	return &RedisPoolCollector{
+		client:     client,
+		hits:       desc("hits_total", "Number of times a free connection was found in the pool."),
+		misses:     desc("misses_total", "Number of times a free connection was not found in the pool."),
+		timeouts:   desc("timeouts_total", "Number of times a wait for a connection timed out."),
+		totalConns: desc("total_connections", "Number of connections in the pool."),
+		idleConns:  desc("idle_connections", "Number of idle connections in the pool."),
+		staleConns: desc("stale_connections_total", "Number of stale connections removed from the pool."),
+	}
+}
+
+func (c *RedisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+func (c *RedisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
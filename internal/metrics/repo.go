@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"time"
+)
+
+// Декораторы репозиториев измеряют время каждого вызова; backend в метке
+// отличает реализации для Postgres и Mongo
+
+type repoObserver struct {
+	metrics *Metrics
+	backend string
+	repo    string
+}
+
+// observe вызывается через defer с моментом начала вызова и указателем на
+// возвращаемую ошибку, значение которой известно только после return
+func (o *repoObserver) observe(method string, start time.Time, err *error) {
+	result := "ok"
+	if *err != nil {
+		result = "error"
+	}
+
+	o.metrics.repoCallDuration.
+		WithLabelValues(o.backend, o.repo, method, result).
+		Observe(time.Since(start).Seconds())
+}
+
+type BookRepo struct {
+	repoObserver
+	next intfRepo.IBookRepo
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, m *Metrics, backend string) intfRepo.IBookRepo {
+	return &BookRepo{repoObserver: repoObserver{metrics: m, backend: backend, repo: "book"}, next: next}
+}
+
+func (r *BookRepo) Create(ctx context.Context, book *models.BookModel) (err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, book)
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (book *models.BookModel, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, ID)
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (book *models.BookModel, err error) {
+	defer r.observe("GetByTitle", time.Now(), &err)
+	return r.next.GetByTitle(ctx, title)
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, ID)
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, book)
+}
+
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) (books []*models.BookModel, err error) {
+	defer r.observe("GetByParams", time.Now(), &err)
+	return r.next.GetByParams(ctx, params)
+}
+
+type LibCardRepo struct {
+	repoObserver
+	next intfRepo.ILibCardRepo
+}
+
+func NewLibCardRepo(next intfRepo.ILibCardRepo, m *Metrics, backend string) intfRepo.ILibCardRepo {
+	return &LibCardRepo{repoObserver: repoObserver{metrics: m, backend: backend, repo: "lib_card"}, next: next}
+}
+
+func (r *LibCardRepo) Create(ctx context.Context, libCard *models.LibCardModel) (err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, libCard)
+}
+
+func (r *LibCardRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (libCard *models.LibCardModel, err error) {
+	defer r.observe("GetByReaderID", time.Now(), &err)
+	return r.next.GetByReaderID(ctx, readerID)
+}
+
+func (r *LibCardRepo) GetByNum(ctx context.Context, libCardNum string) (libCard *models.LibCardModel, err error) {
+	defer r.observe("GetByNum", time.Now(), &err)
+	return r.next.GetByNum(ctx, libCardNum)
+}
+
+func (r *LibCardRepo) Update(ctx context.Context, libCard *models.LibCardModel) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, libCard)
+}
+
+type RatingRepo struct {
+	repoObserver
+	next intfRepo.IRatingRepo
+}
+
+func NewRatingRepo(next intfRepo.IRatingRepo, m *Metrics, backend string) intfRepo.IRatingRepo {
+	return &RatingRepo{repoObserver: repoObserver{metrics: m, backend: backend, repo: "rating"}, next: next}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, rating *models.RatingModel) (err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, rating)
+}
+
+func (r *RatingRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (rating *models.RatingModel, err error) {
+	defer r.observe("GetByReaderAndBook", time.Now(), &err)
+	return r.next.GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *RatingRepo) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) (ratings []*models.RatingModel, err error) {
+	defer r.observe("GetByBookID", time.Now(), &err)
+	return r.next.GetByBookID(ctx, bookID, limit, offset)
+}
+
+type ReaderRepo struct {
+	repoObserver
+	next intfRepo.IReaderRepo
+}
+
+func NewReaderRepo(next intfRepo.IReaderRepo, m *Metrics, backend string) intfRepo.IReaderRepo {
+	return &ReaderRepo{repoObserver: repoObserver{metrics: m, backend: backend, repo: "reader"}, next: next}
+}
+
+func (r *ReaderRepo) Create(ctx context.Context, reader *models.ReaderModel) (err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, reader)
+}
+
+func (r *ReaderRepo) GetByPhoneNumber(ctx context.Context, phoneNumber string) (reader *models.ReaderModel, err error) {
+	defer r.observe("GetByPhoneNumber", time.Now(), &err)
+	return r.next.GetByPhoneNumber(ctx, phoneNumber)
+}
+
+func (r *ReaderRepo) GetByID(ctx context.Context, id uuid.UUID) (reader *models.ReaderModel, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *ReaderRepo) IsFavorite(ctx context.Context, readerID, bookID uuid.UUID) (isFavorite bool, err error) {
+	defer r.observe("IsFavorite", time.Now(), &err)
+	return r.next.IsFavorite(ctx, readerID, bookID)
+}
+
+func (r *ReaderRepo) AddToFavorites(ctx context.Context, readerID, bookID uuid.UUID) (err error) {
+	defer r.observe("AddToFavorites", time.Now(), &err)
+	return r.next.AddToFavorites(ctx, readerID, bookID)
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, id uuid.UUID, token string, ttl time.Duration) (err error) {
+	defer r.observe("SaveRefreshToken", time.Now(), &err)
+	return r.next.SaveRefreshToken(ctx, id, token, ttl)
+}
+
+func (r *ReaderRepo) GetByRefreshToken(ctx context.Context, token string) (reader *models.ReaderModel, err error) {
+	defer r.observe("GetByRefreshToken", time.Now(), &err)
+	return r.next.GetByRefreshToken(ctx, token)
+}
+
+type ReservationRepo struct {
+	repoObserver
+	next intfRepo.IReservationRepo
+}
+
+func NewReservationRepo(next intfRepo.IReservationRepo, m *Metrics, backend string) intfRepo.IReservationRepo {
+	return &ReservationRepo{repoObserver: repoObserver{metrics: m, backend: backend, repo: "reservation"}, next: next}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, reservation *models.ReservationModel) (err error) {
+	defer r.observe("Create", time.Now(), &err)
+	if err = r.next.Create(ctx, reservation); err == nil {
+		r.metrics.reservationsCreated.Inc()
+	}
+
+	return err
+}
+
+func (r *ReservationRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	defer r.observe("GetByReaderAndBook", time.Now(), &err)
+	return r.next.GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *ReservationRepo) GetByID(ctx context.Context, ID uuid.UUID) (reservation *models.ReservationModel, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, ID)
+}
+
+func (r *ReservationRepo) GetByBookID(ctx context.Context, bookID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	defer r.observe("GetByBookID", time.Now(), &err)
+	return r.next.GetByBookID(ctx, bookID)
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, reservation *models.ReservationModel) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	if err = r.next.Update(ctx, reservation); err == nil && reservation.State == impl.ReservationExtended {
+		r.metrics.reservationsExtended.Inc()
+	}
+
+	return err
+}
+
+func (r *ReservationRepo) GetExpiredByReaderID(ctx context.Context, readerID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	defer r.observe("GetExpiredByReaderID", time.Now(), &err)
+	return r.next.GetExpiredByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetActiveByReaderID(ctx context.Context, readerID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	defer r.observe("GetActiveByReaderID", time.Now(), &err)
+	return r.next.GetActiveByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) (reservations []*models.ReservationModel, err error) {
+	defer r.observe("GetByReaderID", time.Now(), &err)
+	return r.next.GetByReaderID(ctx, readerID, limit, offset)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	authorizationHeader = "Authorization"
+
+	ReaderIDCtx    = "readerID"
+	RoleCtx        = "role"
+	AccessTokenCtx = "accessToken"
+)
+
+var (
+	errEmptyAuthHeader   = errors.New("empty auth header")
+	errInvalidAuthHeader = errors.New("invalid auth header")
+	errEmptyToken        = errors.New("token is empty")
+)
+
+// Authenticate проверяет access-токен из заголовка Authorization и кладет
+// идентификатор и роль читателя в контекст запроса
+func Authenticate(tokenManager auth.ITokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken, err := parseAuthHeader(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{ErrorMsg: err.Error()})
+			return
+		}
+
+		readerID, role, err := tokenManager.Parse(accessToken)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{ErrorMsg: err.Error()})
+			return
+		}
+
+		c.Set(ReaderIDCtx, readerID)
+		c.Set(RoleCtx, role)
+		c.Set(AccessTokenCtx, accessToken)
+
+		c.Next()
+	}
+}
+
+// RequireSameReader запрещает доступ к ресурсам чужого читателя (параметр пути :id)
+func RequireSameReader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		readerID, ok := GetReaderID(c)
+		if !ok || readerID.String() != c.Param("id") {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{ErrorMsg: "access denied"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole пропускает только читателей с одной из ролей roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := GetRole(c)
+		if !ok || !slices.Contains(roles, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{ErrorMsg: "access denied"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func GetReaderID(c *gin.Context) (uuid.UUID, bool) {
+	value, ok := c.Get(ReaderIDCtx)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	readerID, err := uuid.Parse(value.(string))
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return readerID, true
+}
+
+func GetRole(c *gin.Context) (string, bool) {
+	value, ok := c.Get(RoleCtx)
+	if !ok {
+		return "", false
+	}
+
+	return value.(string), true
+}
+
+func GetAccessToken(c *gin.Context) string {
+	return c.GetString(AccessTokenCtx)
+}
+
+func parseAuthHeader(c *gin.Context) (string, error) {
+	header := c.GetHeader(authorizationHeader)
+	if header == "" {
+		return "", errEmptyAuthHeader
+	}
+
+	headerParts := strings.Split(header, " ")
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return "", errInvalidAuthHeader
+	}
+
+	if headerParts[1] == "" {
+		return "", errEmptyToken
+	}
+
+	return headerParts[1], nil
+}
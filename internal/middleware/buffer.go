@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// BufferedWriter задерживает ответ обработчика до вызова Send, чтобы после
+// обработчика можно было добавить заголовки, заменить или сохранить ответ
+type BufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func NewBufferedWriter(writer gin.ResponseWriter) *BufferedWriter {
+	return &BufferedWriter{ResponseWriter: writer}
+}
+
+func (w *BufferedWriter) WriteHeader(code int) {
+	if w.body.Len() == 0 {
+		w.status = code
+	}
+}
+
+func (w *BufferedWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *BufferedWriter) Write(data []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(data)
+}
+
+func (w *BufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.WriteString(s)
+}
+
+func (w *BufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *BufferedWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *BufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+// Flush ничего не делает: ответ отправляется целиком после обработчика
+func (w *BufferedWriter) Flush() {}
+
+// Body возвращает тело ответа, записанное обработчиком
+func (w *BufferedWriter) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Send отправляет задержанный ответ
+func (w *BufferedWriter) Send() {
+	w.ResponseWriter.WriteHeader(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"strings"
+)
+
+type clientCtxKey struct{}
+
+// Client - читатель, от имени которого пришел запрос. Для анонимных запросов
+// и запросов с невалидным токеном поля пустые
+type Client struct {
+	ReaderID string
+	Role     string
+}
+
+// IdentifyClient разбирает access-токен один раз на запрос и кладет читателя
+// и роль в контекст для общих middleware (логи, лимиты, реплики,
+// идемпотентность). Запрос с невалидным токеном не отклоняется: авторизацию
+// выполняют Authenticate и обработчики внешнего модуля
+func IdentifyClient(tokenManager auth.ITokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var client Client
+		if accessToken, ok := strings.CutPrefix(c.GetHeader(authorizationHeader), "Bearer "); ok && accessToken != "" {
+			if readerID, role, err := tokenManager.Parse(accessToken); err == nil {
+				client = Client{ReaderID: readerID, Role: role}
+			}
+		}
+
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), clientCtxKey{}, client))
+
+		c.Next()
+	}
+}
+
+// ClientFromContext возвращает читателя, определенного IdentifyClient
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientCtxKey{}).(Client)
+
+	return client
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID берет идентификатор запроса из X-Request-ID, если его передал
+// клиент или прокси, или создает новый и возвращает его в ответе. Поля
+// запроса кладутся в контекст, и логгеры из logging.FromContext добавляют их
+// к каждой записи. Читатель берется из IdentifyClient, так как авторизация
+// внешнего модуля выполняется позже, уже в роутере
+func RequestID(matcher *RouteMatcher, logger *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"route":      matcher.Match(c.Request.Method, c.Request.URL.Path),
+		}
+		if client := ClientFromContext(c.Request.Context()); client.ReaderID != "" {
+			fields["reader_id"] = client.ReaderID
+		}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			fields["trace_id"] = span.TraceID().String()
+		}
+
+		ctx := logging.WithFields(c.Request.Context(), fields)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		logging.FromContext(ctx, logger).WithFields(logrus.Fields{
+			"status":   c.Writer.Status(),
+			"duration": time.Since(start).String(),
+		}).Info("request completed")
+	}
+}
+
+// validRequestID отсекает пустые, слишком длинные и непечатные значения,
+// которые нельзя без опаски писать в логи и заголовки
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
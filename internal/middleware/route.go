@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+const unmatchedRoute = "unmatched"
+
+// RouteMatcher находит шаблон маршрута по пути запроса. Метрики и трассировка
+// работают до роутера, где c.FullPath() еще пуст, а сам путь в метке или имени
+// спана не годится: идентификаторы в путях дали бы бесконечное число значений
+type RouteMatcher struct {
+	routes map[string][][]string
+}
+
+func NewRouteMatcher(routes gin.RoutesInfo) *RouteMatcher {
+	matcher := &RouteMatcher{routes: make(map[string][][]string)}
+	for _, route := range routes {
+		matcher.routes[route.Method] = append(matcher.routes[route.Method], splitPath(route.Path))
+	}
+
+	return matcher
+}
+
+// Match возвращает шаблон маршрута; как и в gin, статический сегмент
+// важнее параметра, поэтому выбирается совпадение с большим числом статических сегментов
+func (rm *RouteMatcher) Match(method, path string) string {
+	segments := splitPath(path)
+
+	var best []string
+	bestStatic := -1
+	for _, route := range rm.routes[method] {
+		if !matchSegments(route, segments) {
+			continue
+		}
+		if static := staticSegments(route); static > bestStatic {
+			best, bestStatic = route, static
+		}
+	}
+
+	if bestStatic < 0 {
+		return unmatchedRoute
+	}
+
+	return "/" + strings.Join(best, "/")
+}
+
+func matchSegments(route, segments []string) bool {
+	for i, part := range route {
+		if strings.HasPrefix(part, "*") {
+			return true
+		}
+		if i >= len(segments) || (!strings.HasPrefix(part, ":") && part != segments[i]) {
+			return false
+		}
+	}
+
+	return len(route) == len(segments)
+}
+
+func staticSegments(route []string) int {
+	static := 0
+	for _, part := range route {
+		if !strings.HasPrefix(part, ":") && !strings.HasPrefix(part, "*") {
+			static++
+		}
+	}
+
+	return static
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,217 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mongodb"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/nikitalystsev/BookSmart/migrations"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io/fs"
+	"os"
+)
+
+// ErrSchemaVersionMismatch - версия схемы БД не совпадает с последней
+// встроенной миграцией или последняя миграция прервалась
+var ErrSchemaVersionMismatch = errors.New("database schema version does not match the application")
+
+// Status - состояние схемы БД. Version = 0, если миграции не применялись.
+// Dirty = true, если миграция Version прервалась и схему нужно исправить вручную
+type Status struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+// Migrator применяет встроенные миграции. На время миграции golang-migrate
+// берет блокировку (pg_advisory_lock в Postgres, запись в коллекции
+// migrate_advisory_lock в Mongo), поэтому экземпляры, запущенные
+// одновременно, применяют миграции по очереди
+type Migrator struct {
+	migrate *migrate.Migrate
+	latest  uint
+	logger  *logrus.Entry
+}
+
+// MigrationsTable - таблица (в Mongo коллекция) с версией схемы приложения.
+// Миграции компонентов, которые migrate.sh применяет до миграций приложения,
+// хранят свою версию в schema_migrations той же БД, поэтому у приложения
+// отдельная таблица: иначе версии компонентов и приложения затирали бы друг друга
+const MigrationsTable = "app_schema_migrations"
+
+// NewPostgresMigrator создает мигратор для Postgres. Версия хранится в
+// таблице MigrationsTable
+func NewPostgresMigrator(db *sql.DB, logger *logrus.Entry) (*Migrator, error) {
+	driver, err := postgres.WithInstance(db, &postgres.Config{MigrationsTable: MigrationsTable})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(migrations.Postgres, "postgres", "postgres", driver, logger)
+}
+
+// NewMongoMigrator создает мигратор для Mongo. Версия хранится в коллекции
+// MigrationsTable. Клиент остается открытым после Close мигратора
+func NewMongoMigrator(client *mongo.Client, dbName string, logger *logrus.Entry) (*Migrator, error) {
+	driver, err := mongodb.WithInstance(client, &mongodb.Config{
+		DatabaseName:         dbName,
+		MigrationsCollection: MigrationsTable,
+		Locking:              mongodb.Locking{Enabled: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(migrations.Mongo, "mongo", "mongodb", sharedClient{Driver: driver}, logger)
+}
+
+func newMigrator(fsys fs.FS, dir, databaseName string, driver database.Driver, logger *logrus.Entry) (*Migrator, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := latestVersion(src)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, databaseName, driver)
+	if err != nil {
+		return nil, err
+	}
+	m.Log = migrateLogger{logger: logger}
+
+	return &Migrator{migrate: m, latest: latest, logger: logger}, nil
+}
+
+// Up применяет все непримененные миграции
+func (m *Migrator) Up() error {
+	m.logger.Info("applying database migrations")
+
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		m.logger.Errorf("error applying database migrations: %v", err)
+		return err
+	}
+
+	m.logger.Info("database schema is up to date")
+
+	return nil
+}
+
+// Down откатывает последнюю примененную миграцию
+func (m *Migrator) Down() error {
+	m.logger.Info("rolling back last database migration")
+
+	if err := m.migrate.Steps(-1); err != nil {
+		m.logger.Errorf("error rolling back database migration: %v", err)
+		return err
+	}
+
+	m.logger.Info("successfully rolled back database migration")
+
+	return nil
+}
+
+// To приводит схему к версии version, применяя или откатывая миграции;
+// version = 0 откатывает все миграции
+func (m *Migrator) To(version uint) error {
+	m.logger.Infof("migrating database schema to version %d", version)
+
+	var err error
+	if version == 0 {
+		err = m.migrate.Down()
+	} else {
+		err = m.migrate.Migrate(version)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		m.logger.Errorf("error migrating database schema: %v", err)
+		return err
+	}
+
+	m.logger.Infof("database schema is at version %d", version)
+
+	return nil
+}
+
+func (m *Migrator) Status() (*Status, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return &Status{Latest: m.latest}, nil
+	}
+	if err != nil {
+		m.logger.Errorf("error getting database schema version: %v", err)
+		return nil, err
+	}
+
+	return &Status{Version: version, Dirty: dirty, Latest: m.latest}, nil
+}
+
+// Check возвращает ErrSchemaVersionMismatch, если схема не совпадает с
+// последней встроенной миграцией
+func (m *Migrator) Check() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w: migration %d is dirty", ErrSchemaVersionMismatch, status.Version)
+	}
+	if status.Version != status.Latest {
+		return fmt.Errorf("%w: schema version is %d, expected %d", ErrSchemaVersionMismatch, status.Version, status.Latest)
+	}
+
+	return nil
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// latestVersion возвращает номер последней миграции источника
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// sharedClient не отключает клиент Mongo приложения при закрытии мигратора
+type sharedClient struct {
+	database.Driver
+}
+
+func (sharedClient) Close() error {
+	return nil
+}
+
+type migrateLogger struct {
+	logger *logrus.Entry
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Infof(format, v...)
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,39 @@
+package optimistic
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+const ErrMsgConcurrentModification = "resource was modified by another request, retry the request"
+
+// Middleware заводит учет версий для запросов на запись и отвечает 409, если
+// запись была отклонена из-за конфликта версий. Внешние обработчики не знают
+// ErrConcurrentModification и отвечают на нее как на любую ошибку, поэтому
+// их ответ об ошибке заменяется. Чтения версии не требуют, их запросы
+// проходят без учета
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		ctx := WithTracking(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := middleware.NewBufferedWriter(c.Writer)
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if Conflicted(ctx) && writer.Status() >= http.StatusBadRequest {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{ErrorMsg: ErrMsgConcurrentModification})
+			return
+		}
+
+		writer.Send()
+	}
+}
@@ -0,0 +1,113 @@
+package optimistic
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoFields - имена полей документов для полей поиска версии
+var mongoFields = map[string]string{
+	FieldID:       "_id",
+	FieldReaderID: "reader_id",
+}
+
+type versionDocument struct {
+	ID      primitive.Binary `bson:"_id"`
+	Version int64            `bson:"version"`
+}
+
+// MongoRepo хранит версию в поле version. У документов, созданных до
+// появления версий, поля нет, и их версия считается нулевой
+type MongoRepo struct {
+	db     *mongo.Database
+	logger *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) IVersionRepo {
+	return &MongoRepo{db: db, logger: logger}
+}
+
+func (r *MongoRepo) Get(ctx context.Context, entity, field string, value uuid.UUID) (uuid.UUID, int64, bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	var document versionDocument
+	err := r.db.Collection(entity).FindOne(ctx,
+		bson.M{mongoFields[field]: toBinary(value)},
+		options.FindOne().SetProjection(bson.M{"_id": 1, "version": 1}),
+	).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return uuid.Nil, 0, false, nil
+	}
+	if err != nil {
+		logger.Errorf("error getting %s version: %v", entity, err)
+		return uuid.Nil, 0, false, err
+	}
+
+	ID, err := uuid.FromBytes(document.ID.Data)
+	if err != nil {
+		logger.Errorf("error decoding %s id: %v", entity, err)
+		return uuid.Nil, 0, false, err
+	}
+
+	return ID, document.Version, true, nil
+}
+
+func (r *MongoRepo) CompareAndSwap(ctx context.Context, entity string, ID uuid.UUID, expected int64) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	filter := bson.M{"_id": toBinary(ID), "version": expected}
+	if expected == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	version, err := r.increment(ctx, entity, filter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Warnf("%s was modified concurrently", entity)
+		return 0, ErrConcurrentModification
+	}
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *MongoRepo) Increment(ctx context.Context, entity string, ID uuid.UUID) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	version, err := r.increment(ctx, entity, bson.M{"_id": toBinary(ID)})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *MongoRepo) increment(ctx context.Context, entity string, filter bson.M) (int64, error) {
+	var document versionDocument
+	err := r.db.Collection(entity).FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"version": int64(1)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&document)
+	if err != nil {
+		return 0, err
+	}
+
+	return document.Version, nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
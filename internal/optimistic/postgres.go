@@ -0,0 +1,94 @@
+package optimistic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) IVersionRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresRepo) Get(ctx context.Context, entity, field string, value uuid.UUID) (uuid.UUID, int64, bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	// entity и field - константы пакета, а не данные запроса
+	query := fmt.Sprintf(`select id, version from bs.%s where %s = $1`, entity, field)
+
+	var row struct {
+		ID      uuid.UUID `db:"id"`
+		Version int64     `db:"version"`
+	}
+	err := tr.GetContext(ctx, &row, query, value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, 0, false, nil
+	}
+	if err != nil {
+		logger.Errorf("error getting %s version: %v", entity, err)
+		return uuid.Nil, 0, false, err
+	}
+
+	return row.ID, row.Version, true, nil
+}
+
+func (r *PostgresRepo) CompareAndSwap(ctx context.Context, entity string, ID uuid.UUID, expected int64) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := fmt.Sprintf(`update bs.%s
+			  set version = version + 1
+			  where id = $1 and version = $2
+			  returning version`, entity)
+
+	var version int64
+	err := tr.GetContext(ctx, &version, query, ID, expected)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Warnf("%s was modified concurrently", entity)
+		return 0, ErrConcurrentModification
+	}
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *PostgresRepo) Increment(ctx context.Context, entity string, ID uuid.UUID) (int64, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := fmt.Sprintf(`update bs.%s
+			  set version = version + 1
+			  where id = $1
+			  returning version`, entity)
+
+	var version int64
+	err := tr.GetContext(ctx, &version, query, ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Errorf("error updating %s version: %v", entity, err)
+		return 0, err
+	}
+
+	return version, nil
+}
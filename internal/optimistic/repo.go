@@ -0,0 +1,163 @@
+package optimistic
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+)
+
+// Декораторы репозиториев запоминают версию сущности при чтении и выполняют
+// Update, только если версия с тех пор не изменилась: иначе возвращается
+// ErrConcurrentModification. Версия читается до данных, поэтому она не может
+// оказаться новее прочитанных данных: параллельная запись между двумя
+// запросами приведет к лишнему конфликту, но не к потере изменения. Декораторы
+// оборачивают репозитории конкретной БД (мастера или реплики), чтобы версия и
+// данные читались из одного источника. Если сущность не читалась в запросе
+// или транзакции, Update только увеличивает версию
+
+type versioned struct {
+	entity   string
+	versions IVersionRepo
+	tm       transact.ITransactionManager
+}
+
+// remember запоминает версию сущности, у которой field = value
+func (v *versioned) remember(ctx context.Context, field string, value uuid.UUID) {
+	t := trackingFrom(ctx)
+	if t == nil {
+		return
+	}
+
+	ID, version, found, err := v.versions.Get(ctx, v.entity, field, value)
+	if err != nil || !found {
+		return
+	}
+
+	t.remember(v.entity, ID, version)
+}
+
+// update меняет версию и выполняет запись в одной транзакции: до ее
+// завершения строка заблокирована, и параллельная запись дождется новой версии
+func (v *versioned) update(ctx context.Context, ID uuid.UUID, write func(context.Context) error) error {
+	return v.tm.Do(ctx, func(ctx context.Context) error {
+		t := trackingFrom(ctx)
+
+		var (
+			version int64
+			err     error
+		)
+		if expected, ok := t.get(v.entity, ID); ok {
+			version, err = v.versions.CompareAndSwap(ctx, v.entity, ID, expected)
+		} else {
+			version, err = v.versions.Increment(ctx, v.entity, ID)
+		}
+		if err = t.check(v.entity, ID, err); err != nil {
+			return err
+		}
+
+		if err = write(ctx); err != nil {
+			return err
+		}
+		t.set(v.entity, ID, version)
+
+		return nil
+	})
+}
+
+// CheckExpected увеличивает версию сущности, если для нее задана ожидаемая
+// версия (Expect), и отклоняет запись, если версия в БД уже другая. Нужна
+// записям в обход Update, например мягкому удалению книги
+func CheckExpected(ctx context.Context, versions IVersionRepo, entity string, ID uuid.UUID) error {
+	t := trackingFrom(ctx)
+	if !t.isExpected(entity, ID) {
+		return nil
+	}
+
+	expected, _ := t.get(entity, ID)
+	version, err := versions.CompareAndSwap(ctx, entity, ID, expected)
+	if err = t.check(entity, ID, err); err != nil {
+		return err
+	}
+	t.set(entity, ID, version)
+
+	return nil
+}
+
+// check отмечает конфликт версий. Несовпадение с ожидаемой версией клиента
+// не повторяется транзакцией и превращается в ErrPreconditionFailed
+func (t *tracking) check(entity string, ID uuid.UUID, err error) error {
+	if !errors.Is(err, ErrConcurrentModification) {
+		return err
+	}
+
+	if t.isExpected(entity, ID) {
+		t.preconditionFailed.Store(true)
+		return ErrPreconditionFailed
+	}
+	t.markConflict()
+
+	return err
+}
+
+type BookRepo struct {
+	intfRepo.IBookRepo
+	versioned
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, versions IVersionRepo, tm transact.ITransactionManager) intfRepo.IBookRepo {
+	return &BookRepo{IBookRepo: next, versioned: versioned{entity: EntityBook, versions: versions, tm: tm}}
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	r.remember(ctx, FieldID, ID)
+	return r.IBookRepo.GetByID(ctx, ID)
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) error {
+	return r.update(ctx, book.ID, func(ctx context.Context) error {
+		return r.IBookRepo.Update(ctx, book)
+	})
+}
+
+type LibCardRepo struct {
+	intfRepo.ILibCardRepo
+	versioned
+}
+
+func NewLibCardRepo(next intfRepo.ILibCardRepo, versions IVersionRepo, tm transact.ITransactionManager) intfRepo.ILibCardRepo {
+	return &LibCardRepo{ILibCardRepo: next, versioned: versioned{entity: EntityLibCard, versions: versions, tm: tm}}
+}
+
+func (r *LibCardRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*models.LibCardModel, error) {
+	r.remember(ctx, FieldReaderID, readerID)
+	return r.ILibCardRepo.GetByReaderID(ctx, readerID)
+}
+
+func (r *LibCardRepo) Update(ctx context.Context, libCard *models.LibCardModel) error {
+	return r.update(ctx, libCard.ID, func(ctx context.Context) error {
+		return r.ILibCardRepo.Update(ctx, libCard)
+	})
+}
+
+type ReservationRepo struct {
+	intfRepo.IReservationRepo
+	versioned
+}
+
+func NewReservationRepo(next intfRepo.IReservationRepo, versions IVersionRepo, tm transact.ITransactionManager) intfRepo.IReservationRepo {
+	return &ReservationRepo{IReservationRepo: next, versioned: versioned{entity: EntityReservation, versions: versions, tm: tm}}
+}
+
+func (r *ReservationRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.ReservationModel, error) {
+	r.remember(ctx, FieldID, ID)
+	return r.IReservationRepo.GetByID(ctx, ID)
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, reservation *models.ReservationModel) error {
+	return r.update(ctx, reservation.ID, func(ctx context.Context) error {
+		return r.IReservationRepo.Update(ctx, reservation)
+	})
+}
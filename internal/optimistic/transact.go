@@ -0,0 +1,179 @@
+package optimistic
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"sync"
+	"sync/atomic"
+)
+
+// maxAttempts - сколько раз транзакция выполняется при конфликте версий
+const maxAttempts = 3
+
+type (
+	trackingKey    struct{}
+	transactionKey struct{}
+)
+
+// tracking - версии сущностей, прочитанные в запросе или транзакции, и
+// отметка о конфликте версий. Ожидаемые версии из If-Match чтение не меняет
+type tracking struct {
+	mu                 sync.Mutex
+	versions           map[string]int64
+	expected           map[string]int64
+	conflict           atomic.Bool
+	preconditionFailed atomic.Bool
+}
+
+// WithTracking добавляет в контекст учет прочитанных версий. Без него
+// изменения записываются без проверки версии
+func WithTracking(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(trackingKey{}).(*tracking); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, trackingKey{}, &tracking{versions: make(map[string]int64), expected: make(map[string]int64)})
+}
+
+// Expect задает версию, с которой клиент прочитал сущность (If-Match). Update
+// сущности в запросе пройдет, только если версия в БД с тех пор не менялась,
+// иначе запись отклоняется с ErrPreconditionFailed
+func Expect(ctx context.Context, entity string, ID uuid.UUID, version int64) context.Context {
+	ctx = WithTracking(ctx)
+
+	t := trackingFrom(ctx)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.versions[entity+":"+ID.String()] = version
+	t.expected[entity+":"+ID.String()] = version
+
+	return ctx
+}
+
+// PreconditionFailed сообщает, была ли в контексте запись, отклоненная из-за
+// несовпадения с версией из Expect
+func PreconditionFailed(ctx context.Context) bool {
+	t, ok := ctx.Value(trackingKey{}).(*tracking)
+	return ok && t.preconditionFailed.Load()
+}
+
+// Version возвращает версию сущности, прочитанную в запросе вместе с ее данными
+func Version(ctx context.Context, entity string, ID uuid.UUID) (int64, bool) {
+	return trackingFrom(ctx).get(entity, ID)
+}
+
+// Advance учитывает версию, которую увеличила запись в обход Update, например
+// изменение числа экземпляров книги. Учтенная версия переносится, только
+// если между чтением и записью сущность никто не менял (version на единицу
+// больше учтенной), иначе следующий Update вернет конфликт
+func Advance(ctx context.Context, entity string, ID uuid.UUID, version int64) {
+	t := trackingFrom(ctx)
+	if tracked, ok := t.get(entity, ID); ok && tracked == version-1 {
+		t.set(entity, ID, version)
+	}
+}
+
+// Conflicted сообщает, было ли в контексте изменение, отклоненное из-за версии
+func Conflicted(ctx context.Context) bool {
+	t, ok := ctx.Value(trackingKey{}).(*tracking)
+	return ok && t.conflict.Load()
+}
+
+func trackingFrom(ctx context.Context) *tracking {
+	t, _ := ctx.Value(trackingKey{}).(*tracking)
+	return t
+}
+
+func (t *tracking) get(entity string, ID uuid.UUID) (int64, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	version, ok := t.versions[entity+":"+ID.String()]
+	return version, ok
+}
+
+func (t *tracking) set(entity string, ID uuid.UUID, version int64) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.versions[entity+":"+ID.String()] = version
+}
+
+// remember запоминает прочитанную версию, если для сущности не задана ожидаемая
+func (t *tracking) remember(entity string, ID uuid.UUID, version int64) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.expected[entity+":"+ID.String()]; !ok {
+		t.versions[entity+":"+ID.String()] = version
+	}
+}
+
+func (t *tracking) isExpected(entity string, ID uuid.UUID) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.expected[entity+":"+ID.String()]
+	return ok
+}
+
+// restore возвращает ожидаемые версии перед повтором транзакции: версии,
+// записанные откаченной попыткой, в БД не попали
+func (t *tracking) restore() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, version := range t.expected {
+		t.versions[key] = version
+	}
+}
+
+func (t *tracking) markConflict() {
+	if t != nil {
+		t.conflict.Store(true)
+	}
+}
+
+// TransactionManager заводит учет версий для транзакции и повторяет ее при
+// ErrConcurrentModification. Повтор безопасен: откаченная транзакция ничего
+// не записала, а сервис заново читает данные внутри fn. Вложенные вызовы
+// выполняются в транзакции внешнего и не повторяются
+type TransactionManager struct {
+	next transact.ITransactionManager
+}
+
+func NewTransactionManager(next transact.ITransactionManager) transact.ITransactionManager {
+	return &TransactionManager{next: next}
+}
+
+func (tm *TransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(bool); ok {
+		return tm.next.Do(ctx, fn)
+	}
+	ctx = context.WithValue(WithTracking(ctx), transactionKey{}, true)
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		trackingFrom(ctx).restore()
+		if err = tm.next.Do(ctx, fn); !errors.Is(err, ErrConcurrentModification) {
+			// конфликт снят повтором и не должен влиять на ответ
+			trackingFrom(ctx).conflict.Store(false)
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,42 @@
+package optimistic
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+)
+
+// ErrConcurrentModification - сущность изменили после того, как ее прочитал
+// запрос: изменение отклоняется, чтобы не перетереть чужую запись
+var ErrConcurrentModification = errors.New("entity was modified concurrently")
+
+// ErrPreconditionFailed - версия сущности не совпала с ожидаемой версией из
+// If-Match. В отличие от ErrConcurrentModification транзакция не повторяется:
+// клиент сам должен перечитать сущность
+var ErrPreconditionFailed = errors.New("entity version does not match the expected one")
+
+// Сущности с версией; значения совпадают с именами таблиц и коллекций
+const (
+	EntityBook        = "book"
+	EntityLibCard     = "lib_card"
+	EntityReservation = "reservation"
+)
+
+// Поля, по которым ищется версия сущности
+const (
+	FieldID       = "id"
+	FieldReaderID = "reader_id"
+)
+
+// IVersionRepo хранит версии сущностей рядом с их данными. Модели внешнего
+// модуля не содержат версии, поэтому она читается и меняется отдельно
+type IVersionRepo interface {
+	// Get возвращает идентификатор и версию сущности, у которой field = value.
+	// Если сущности нет, found = false
+	Get(ctx context.Context, entity, field string, value uuid.UUID) (ID uuid.UUID, version int64, found bool, err error)
+	// CompareAndSwap увеличивает версию, если она равна expected, и возвращает
+	// новую. Иначе возвращается ErrConcurrentModification
+	CompareAndSwap(ctx context.Context, entity string, ID uuid.UUID, expected int64) (int64, error)
+	// Increment увеличивает версию без проверки и возвращает новую
+	Increment(ctx context.Context, entity string, ID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,97 @@
+package passwordreset
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"net/http"
+)
+
+type Handler struct {
+	passwordResetService IPasswordResetService
+}
+
+func NewHandler(passwordResetService IPasswordResetService) *Handler {
+	return &Handler{passwordResetService: passwordResetService}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		auth := api.Group("/auth")
+		{
+			auth.POST("/password-reset", h.requestPasswordReset)
+			auth.POST("/password-reset/confirm", h.confirmPasswordReset)
+		}
+	}
+}
+
+// @Summary Метод запроса кода для сброса пароля
+// @Tags auth
+// @ID requestPasswordReset
+// @Accept json
+// @Produce json
+// @Param input body dto.PasswordResetRequestInputDTO true "Номер телефона читателя"
+// @Success 202 "Код отправлен, если номер зарегистрирован"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 429 {object} dto.ErrorResponse "Код запрошен слишком часто"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/password-reset [post]
+func (h *Handler) requestPasswordReset(c *gin.Context) {
+	var input dto.PasswordResetRequestInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.passwordResetService.RequestCode(c.Request.Context(), input.PhoneNumber); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Метод установки нового пароля по одноразовому коду
+// @Tags auth
+// @ID confirmPasswordReset
+// @Accept json
+// @Produce json
+// @Param input body dto.PasswordResetConfirmInputDTO true "Номер телефона, код и новый пароль"
+// @Success 204 "Успешный сброс пароля"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или неверный код"
+// @Failure 429 {object} dto.ErrorResponse "Превышено число попыток ввода кода"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/password-reset/confirm [post]
+func (h *Handler) confirmPasswordReset(c *gin.Context) {
+	var input dto.PasswordResetConfirmInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	err := h.passwordResetService.Reset(c.Request.Context(), input.PhoneNumber, input.Code, input.NewPassword)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, errs.ErrEmptyReaderPhoneNumber),
+		errors.Is(err, errs.ErrInvalidReaderPasswordLen),
+		errors.Is(err, ErrInvalidResetCode):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrResendTooEarly),
+		errors.Is(err, ErrResetAttemptsExceeded):
+		status = http.StatusTooManyRequests
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
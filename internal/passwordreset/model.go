@@ -0,0 +1,26 @@
+package passwordreset
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	CodeLen = 6
+
+	DefaultCodeTTL        = 10 * time.Minute
+	DefaultMaxAttempts    = 5
+	DefaultResendInterval = time.Minute
+)
+
+var (
+	ErrInvalidResetCode      = errors.New("invalid or expired password reset code")
+	ErrResetAttemptsExceeded = errors.New("password reset attempts exceeded")
+	ErrResendTooEarly        = errors.New("password reset code was requested too recently")
+)
+
+// CodeModel - одноразовый код сброса пароля. Хранится только хэш кода
+type CodeModel struct {
+	CodeHash string
+	Attempts int
+}
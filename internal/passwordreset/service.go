@@ -0,0 +1,221 @@
+package passwordreset
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/hash"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/sms"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"time"
+	"unicode/utf8"
+)
+
+type IPasswordResetService interface {
+	RequestCode(ctx context.Context, phoneNumber string) error
+	Reset(ctx context.Context, phoneNumber, code, newPassword string) error
+}
+
+type PasswordResetService struct {
+	codeStore        ICodeStore
+	readerRepo       intfRepo.IReaderRepo
+	accountRepo      account.IAccountRepo
+	refreshTokenRepo account.IRefreshTokenRepo
+	hasher           hash.IPasswordHasher
+	sender           sms.ISender
+	logger           *logrus.Entry
+	codeTTL          time.Duration
+	maxAttempts      int
+	resendInterval   time.Duration
+}
+
+func NewPasswordResetService(
+	codeStore ICodeStore,
+	readerRepo intfRepo.IReaderRepo,
+	accountRepo account.IAccountRepo,
+	refreshTokenRepo account.IRefreshTokenRepo,
+	hasher hash.IPasswordHasher,
+	sender sms.ISender,
+	logger *logrus.Entry,
+	codeTTL time.Duration,
+	maxAttempts int,
+	resendInterval time.Duration,
+) IPasswordResetService {
+	if codeTTL <= 0 {
+		codeTTL = DefaultCodeTTL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if resendInterval <= 0 {
+		resendInterval = DefaultResendInterval
+	}
+
+	return &PasswordResetService{
+		codeStore:        codeStore,
+		readerRepo:       readerRepo,
+		accountRepo:      accountRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		hasher:           hasher,
+		sender:           sender,
+		logger:           logger,
+		codeTTL:          codeTTL,
+		maxAttempts:      maxAttempts,
+		resendInterval:   resendInterval,
+	}
+}
+
+// RequestCode отправляет одноразовый код на номер телефона читателя. Если
+// читателя с таким номером нет, код не отправляется, но ошибка не возвращается,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли номер
+func (prs *PasswordResetService) RequestCode(ctx context.Context, phoneNumber string) error {
+	logger := logging.FromContext(ctx, prs.logger)
+	logger.Info("attempting to request password reset code")
+
+	if phoneNumber == "" {
+		logger.Warn("empty reader phone number")
+		return errs.ErrEmptyReaderPhoneNumber
+	}
+
+	locked, err := prs.codeStore.LockResend(ctx, phoneNumber, prs.resendInterval)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		logger.Warn("password reset code was requested too recently")
+		return ErrResendTooEarly
+	}
+
+	_, err = prs.readerRepo.GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil && errors.Is(err, errs.ErrReaderDoesNotExists) {
+		logger.Warn("password reset requested for unknown phone number")
+		return nil
+	}
+	if err != nil {
+		logger.Errorf("error getting reader: %v", err)
+		return err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		logger.Errorf("error generating password reset code: %v", err)
+		return err
+	}
+
+	err = prs.codeStore.Save(ctx, phoneNumber, &CodeModel{CodeHash: hashCode(phoneNumber, code)}, prs.codeTTL)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("BookSmart: код для сброса пароля %s. Никому не сообщайте его", code)
+	if err = prs.sender.Send(ctx, phoneNumber, message); err != nil {
+		logger.Errorf("error sending password reset code: %v", err)
+		return err
+	}
+
+	logger.Info("password reset code successfully sent")
+
+	return nil
+}
+
+// Reset проверяет одноразовый код и устанавливает новый пароль. После
+// maxAttempts неверных попыток код удаляется и его нужно запросить заново
+func (prs *PasswordResetService) Reset(ctx context.Context, phoneNumber, code, newPassword string) error {
+	logger := logging.FromContext(ctx, prs.logger)
+	logger.Info("attempting to reset reader password")
+
+	if utf8.RuneCountInString(newPassword) < account.ReaderPasswordMinLen {
+		logger.Warn("invalid reader password length")
+		return errs.ErrInvalidReaderPasswordLen
+	}
+
+	if err := prs.verifyCode(ctx, phoneNumber, code); err != nil {
+		return err
+	}
+
+	reader, err := prs.readerRepo.GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		logger.Errorf("error getting reader: %v", err)
+		return err
+	}
+
+	if reader.Password, err = prs.hasher.Hash(newPassword); err != nil {
+		logger.Errorf("error hashing password: %v", err)
+		return err
+	}
+
+	if err = prs.accountRepo.Update(ctx, reader); err != nil {
+		logger.Errorf("error updating reader password: %v", err)
+		return err
+	}
+
+	if err = prs.refreshTokenRepo.DeleteByReaderID(ctx, reader.ID); err != nil {
+		logger.Errorf("error revoking reader refresh tokens: %v", err)
+		return err
+	}
+
+	logger.Info("reader password successfully reset")
+
+	return nil
+}
+
+func (prs *PasswordResetService) verifyCode(ctx context.Context, phoneNumber, code string) error {
+	logger := logging.FromContext(ctx, prs.logger)
+
+	// попытка засчитывается до сравнения кода, поэтому параллельные попытки
+	// не проходят проверку лимита по одному и тому же значению счетчика
+	stored, err := prs.codeStore.Attempt(ctx, phoneNumber)
+	if err != nil {
+		logger.Warn("password reset code not found")
+		return err
+	}
+
+	if stored.Attempts > prs.maxAttempts {
+		logger.Warn("password reset attempts exceeded")
+		_ = prs.codeStore.Delete(ctx, phoneNumber)
+		return ErrResetAttemptsExceeded
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored.CodeHash), []byte(hashCode(phoneNumber, code))) == 1 {
+		return prs.codeStore.Delete(ctx, phoneNumber)
+	}
+
+	if stored.Attempts == prs.maxAttempts {
+		logger.Warn("password reset attempts exceeded")
+		_ = prs.codeStore.Delete(ctx, phoneNumber)
+		return ErrResetAttemptsExceeded
+	}
+
+	logger.Warn("invalid password reset code")
+
+	return ErrInvalidResetCode
+}
+
+func generateCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < CodeLen; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", CodeLen, n), nil
+}
+
+func hashCode(phoneNumber, code string) string {
+	sum := sha256.Sum256([]byte(phoneNumber + ":" + code))
+
+	return hex.EncodeToString(sum[:])
+}
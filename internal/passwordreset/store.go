@@ -0,0 +1,117 @@
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	codeKeyPrefix   = "password_reset:code:"
+	resendKeyPrefix = "password_reset:resend:"
+
+	codeHashField = "code_hash"
+	attemptsField = "attempts"
+)
+
+// attemptScript увеличивает счетчик попыток только у существующего кода, не
+// меняя его TTL, и возвращает хэш кода вместе с новым счетчиком. Параллельные
+// попытки получают разные значения счетчика, поэтому лимит попыток не обойти
+var attemptScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+
+local attempts = redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+
+return {redis.call('HGET', KEYS[1], ARGV[2]), attempts}
+`)
+
+type ICodeStore interface {
+	Save(ctx context.Context, phoneNumber string, code *CodeModel, ttl time.Duration) error
+	// Attempt засчитывает попытку ввода кода и возвращает код с числом попыток,
+	// включая эту. Если кода нет, возвращается ErrInvalidResetCode
+	Attempt(ctx context.Context, phoneNumber string) (*CodeModel, error)
+	Delete(ctx context.Context, phoneNumber string) error
+	// LockResend возвращает false, если код для номера уже запрашивали в течение interval
+	LockResend(ctx context.Context, phoneNumber string, interval time.Duration) (bool, error)
+}
+
+type RedisCodeStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisCodeStore(client *redis.Client, logger *logrus.Entry) ICodeStore {
+	return &RedisCodeStore{client: client, logger: logger}
+}
+
+func (s *RedisCodeStore) Save(ctx context.Context, phoneNumber string, code *CodeModel, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	key := codeKeyPrefix + phoneNumber
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, codeHashField, code.CodeHash, attemptsField, code.Attempts)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error saving password reset code: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisCodeStore) Attempt(ctx context.Context, phoneNumber string) (*CodeModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	values, err := attemptScript.Run(ctx, s.client, []string{codeKeyPrefix + phoneNumber},
+		attemptsField, codeHashField).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidResetCode
+	}
+	if err != nil {
+		logger.Errorf("error counting password reset attempt: %v", err)
+		return nil, err
+	}
+
+	if len(values) != 2 {
+		return nil, ErrInvalidResetCode
+	}
+	codeHash, _ := values[0].(string)
+	attempts, _ := values[1].(int64)
+	if codeHash == "" {
+		return nil, ErrInvalidResetCode
+	}
+
+	return &CodeModel{CodeHash: codeHash, Attempts: int(attempts)}, nil
+}
+
+func (s *RedisCodeStore) Delete(ctx context.Context, phoneNumber string) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, codeKeyPrefix+phoneNumber).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		logger.Errorf("error deleting password reset code: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisCodeStore) LockResend(ctx context.Context, phoneNumber string, interval time.Duration) (bool, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	ok, err := s.client.SetNX(ctx, resendKeyPrefix+phoneNumber, 1, interval).Result()
+	if err != nil {
+		logger.Errorf("error locking password reset resend: %v", err)
+		return false, err
+	}
+
+	return ok, nil
+}
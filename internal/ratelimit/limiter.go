@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"math"
+	"strconv"
+	"time"
+)
+
+const bucketKeyPrefix = "rate_limit:"
+
+// tokenBucketScript пополняет корзину по времени сервера Redis, чтобы у всех
+// экземпляров приложения были одни часы, и списывает один токен, если он есть
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000))
+
+return {allowed, tostring(tokens)}
+`)
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset - время до полного восстановления корзины
+	Reset time.Duration
+	// RetryAfter - время до появления следующего токена, если запрос отклонен
+	RetryAfter time.Duration
+}
+
+type ILimiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+type RedisLimiter struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisLimiter(client *redis.Client, logger *logrus.Entry) ILimiter {
+	return &RedisLimiter{client: client, logger: logger}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	logger := logging.FromContext(ctx, l.logger)
+
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{bucketKeyPrefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		logger.Errorf("error running token bucket script: %v", err)
+		return nil, err
+	}
+
+	tokens, err := strconv.ParseFloat(values[1].(string), 64)
+	if err != nil {
+		logger.Errorf("error parsing token bucket tokens: %v", err)
+		return nil, err
+	}
+
+	result := &Result{
+		Allowed:   values[0].(int64) == 1,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return result, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const ErrMsgRateLimitExceeded = "rate limit exceeded"
+
+// Middleware ограничивает частоту запросов. Авторизованные читатели
+// ограничиваются по идентификатору и роли из access-токена, остальные
+// клиенты - по IP. При недоступности Redis запросы не ограничиваются.
+// Политика берется из policies на каждый запрос
+func Middleware(limiter ILimiter, policies *PolicyStore, logger *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, role := session.ClientIdentity(c.Request)
+		group, limit := policies.Load().Resolve(c.Request.URL.Path, role)
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), group+":"+identity, limit)
+		if err != nil {
+			logging.FromContext(c.Request.Context(), logger).Errorf("error checking rate limit: %v", err)
+			c.Next()
+			return
+		}
+
+		setHeaders(c, limit, result)
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{ErrorMsg: ErrMsgRateLimitExceeded})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setHeaders выставляет заголовки по черновику IETF RateLimit header fields
+func setHeaders(c *gin.Context, limit Limit, result *Result) {
+	window := int(math.Ceil(float64(limit.Burst) / limit.Rate))
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, window))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+const defaultGroup = "default"
+
+// Limit - параметры token bucket: Rate токенов в секунду, не больше Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Rule задает лимит для группы маршрутов с общим префиксом и, при
+// необходимости, отдельные лимиты для ролей внутри этой группы
+type Rule struct {
+	Prefix string
+	Limit  Limit
+	Roles  map[string]Limit
+}
+
+type Policy struct {
+	Default Limit
+	Roles   map[string]Limit
+	Rules   []Rule
+}
+
+// Resolve возвращает группу и лимит для запроса. Выбирается правило с самым
+// длинным подходящим префиксом; лимит роли внутри правила важнее лимита
+// правила, а общий лимит роли применяется только вне правил. Роли в картах
+// хранятся в нижнем регистре, так как viper приводит к нему ключи конфига
+func (p *Policy) Resolve(path, role string) (string, Limit) {
+	role = strings.ToLower(role)
+
+	var rule *Rule
+	for i := range p.Rules {
+		if strings.HasPrefix(path, p.Rules[i].Prefix) &&
+			(rule == nil || len(p.Rules[i].Prefix) > len(rule.Prefix)) {
+			rule = &p.Rules[i]
+		}
+	}
+
+	if rule != nil {
+		if limit, ok := rule.Roles[role]; ok {
+			return rule.Prefix, limit
+		}
+		return rule.Prefix, rule.Limit
+	}
+
+	if limit, ok := p.Roles[role]; ok {
+		return defaultGroup, limit
+	}
+
+	return defaultGroup, p.Default
+}
+
+// PolicyStore хранит действующую политику и позволяет заменить ее без
+// перезапуска, например после изменения конфигурации. Пустая политика
+// отключает ограничение частоты запросов
+type PolicyStore struct {
+	current atomic.Pointer[Policy]
+}
+
+func NewPolicyStore(policy *Policy) *PolicyStore {
+	store := &PolicyStore{}
+	store.Store(policy)
+
+	return store
+}
+
+func (s *PolicyStore) Load() *Policy {
+	return s.current.Load()
+}
+
+func (s *PolicyStore) Store(policy *Policy) {
+	s.current.Store(policy)
+}
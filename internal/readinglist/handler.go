@@ -0,0 +1,449 @@
+package readinglist
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	readingListService IReadingListService
+	tokenManager       auth.ITokenManager
+}
+
+func NewHandler(readingListService IReadingListService, tokenManager auth.ITokenManager) *Handler {
+	return &Handler{readingListService: readingListService, tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.GET("/reading_lists/shared/:share_token", h.getSharedReadingList)
+
+		readingLists := api.Group("/readers/:id/reading_lists",
+			middleware.Authenticate(h.tokenManager), middleware.RequireSameReader())
+		{
+			readingLists.GET("", h.getReadingLists)
+			readingLists.POST("", h.createReadingList)
+			readingLists.GET("/:list_id", h.getReadingListByID)
+			readingLists.PATCH("/:list_id", h.renameReadingList)
+			readingLists.DELETE("/:list_id", h.deleteReadingList)
+			readingLists.POST("/:list_id/books", h.addBookToReadingList)
+			readingLists.PUT("/:list_id/books", h.reorderReadingList)
+			readingLists.DELETE("/:list_id/books/:book_id", h.removeBookFromReadingList)
+			readingLists.POST("/:list_id/share", h.shareReadingList)
+			readingLists.DELETE("/:list_id/share", h.unshareReadingList)
+		}
+	}
+}
+
+// @Summary Метод получения списков чтения читателя
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID getReadingLists
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Success 200 {array} dto.ReadingListOutputDTO "Успешное получение списков чтения"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Списки чтения не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists [get]
+func (h *Handler) getReadingLists(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	readingLists, err := h.readingListService.GetByReaderID(c.Request.Context(), readerID)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	readingListsDTO := make([]*dto.ReadingListOutputDTO, len(readingLists))
+	for i, readingList := range readingLists {
+		readingListsDTO[i] = toOutputDTO(readingList)
+	}
+
+	c.JSON(http.StatusOK, readingListsDTO)
+}
+
+// @Summary Метод создания списка чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID createReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.ReadingListInputDTO true "Название списка чтения"
+// @Success 201 {object} dto.ReadingListOutputDTO "Успешное создание списка чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 409 {object} dto.ErrorResponse "Список чтения с таким названием уже существует"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists [post]
+func (h *Handler) createReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.ReadingListInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	readingList, err := h.readingListService.Create(c.Request.Context(), readerID, input.Name)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toOutputDTO(readingList))
+}
+
+// @Summary Метод получения списка чтения по идентификатору
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID getReadingListByID
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Success 200 {object} dto.ReadingListOutputDTO "Успешное получение списка чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id} [get]
+func (h *Handler) getReadingListByID(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	readingList, err := h.readingListService.GetByID(c.Request.Context(), readerID, listID)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOutputDTO(readingList))
+}
+
+// @Summary Метод переименования списка чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID renameReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Param input body dto.ReadingListInputDTO true "Новое название списка чтения"
+// @Success 200 "Успешное переименование списка чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 409 {object} dto.ErrorResponse "Список чтения с таким названием уже существует"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id} [patch]
+func (h *Handler) renameReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	var input dto.ReadingListInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.readingListService.Rename(c.Request.Context(), readerID, listID, input.Name); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Метод удаления списка чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID deleteReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Success 200 "Успешное удаление списка чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id} [delete]
+func (h *Handler) deleteReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	if err := h.readingListService.Delete(c.Request.Context(), readerID, listID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Метод добавления книги в список чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID addBookToReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Param input body dto.ReadingListBookInputDTO true "Идентификатор книги"
+// @Success 201 "Успешное добавление книги в список чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения или книга не найдены"
+// @Failure 409 {object} dto.ErrorResponse "Книга уже есть в списке чтения"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id}/books [post]
+func (h *Handler) addBookToReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	var input dto.ReadingListBookInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.readingListService.AddBook(c.Request.Context(), readerID, listID, input.BookID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
+
+// @Summary Метод изменения порядка книг в списке чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID reorderReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Param input body dto.ReadingListOrderInputDTO true "Идентификаторы книг в новом порядке"
+// @Success 200 "Успешное изменение порядка книг"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id}/books [put]
+func (h *Handler) reorderReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	var input dto.ReadingListOrderInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.readingListService.Reorder(c.Request.Context(), readerID, listID, input.BookIDs); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Метод удаления книги из списка чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID removeBookFromReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Param book_id path string true "Идентификатор книги"
+// @Success 200 "Успешное удаление книги из списка чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id}/books/{book_id} [delete]
+func (h *Handler) removeBookFromReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	bookID, ok := parseUUIDParam(c, "book_id")
+	if !ok {
+		return
+	}
+
+	if err := h.readingListService.RemoveBook(c.Request.Context(), readerID, listID, bookID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Метод открытия публичного доступа к списку чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID shareReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Success 200 {object} dto.ShareTokenOutputDTO "Успешное открытие доступа к списку чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id}/share [post]
+func (h *Handler) shareReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	shareToken, err := h.readingListService.Share(c.Request.Context(), readerID, listID)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ShareTokenOutputDTO{ShareToken: shareToken})
+}
+
+// @Summary Метод закрытия публичного доступа к списку чтения
+// @Security ApiKeyAuth
+// @Tags reader_reading_lists
+// @ID unshareReadingList
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param list_id path string true "Идентификатор списка чтения"
+// @Success 200 "Успешное закрытие доступа к списку чтения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/reading_lists/{list_id}/share [delete]
+func (h *Handler) unshareReadingList(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	listID, ok := parseUUIDParam(c, "list_id")
+	if !ok {
+		return
+	}
+
+	if err := h.readingListService.Unshare(c.Request.Context(), readerID, listID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Метод получения списка чтения по публичной ссылке
+// @Tags reading_lists
+// @ID getSharedReadingList
+// @Accept json
+// @Produce json
+// @Param share_token path string true "Токен публичной ссылки"
+// @Success 200 {object} dto.SharedReadingListOutputDTO "Успешное получение списка чтения"
+// @Failure 404 {object} dto.ErrorResponse "Список чтения не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/reading_lists/shared/{share_token} [get]
+func (h *Handler) getSharedReadingList(c *gin.Context) {
+	readingList, err := h.readingListService.GetByShareToken(c.Request.Context(), c.Param("share_token"))
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SharedReadingListOutputDTO{
+		Name:    readingList.Name,
+		BookIDs: readingList.BookIDs,
+	})
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, ErrEmptyReadingListName),
+		errors.Is(err, ErrInvalidReadingListNameLen),
+		errors.Is(err, ErrInvalidReadingListOrder):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrReadingListDoesNotExists),
+		errors.Is(err, errs.ErrBookDoesNotExists):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrReadingListAlreadyExist),
+		errors.Is(err, ErrDuplicateBookInReadingList),
+		errors.Is(err, ErrReadingListsLimitExceeded),
+		errors.Is(err, ErrReadingListBooksLimitExceed):
+		status = http.StatusConflict
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
+
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func toOutputDTO(readingList *ReadingListModel) *dto.ReadingListOutputDTO {
+	return &dto.ReadingListOutputDTO{
+		ID:         readingList.ID,
+		Name:       readingList.Name,
+		BookIDs:    readingList.BookIDs,
+		ShareToken: readingList.ShareToken,
+		CreatedAt:  readingList.CreatedAt,
+	}
+}
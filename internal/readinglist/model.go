@@ -0,0 +1,35 @@
+package readinglist
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	MaxReadingListsPerReader = 50
+	MaxBooksPerReadingList   = 500
+	MaxReadingListNameLen    = 100
+)
+
+var (
+	ErrReadingListDoesNotExists    = errors.New("reading list does not exists")
+	ErrReadingListAlreadyExist     = errors.New("reading list with this name already exists")
+	ErrEmptyReadingListName        = errors.New("empty reading list name")
+	ErrInvalidReadingListNameLen   = errors.New("invalid reading list name length")
+	ErrReadingListsLimitExceeded   = errors.New("reading lists limit exceeded")
+	ErrReadingListBooksLimitExceed = errors.New("reading list books limit exceeded")
+	ErrDuplicateBookInReadingList  = errors.New("book is already in the reading list")
+	ErrInvalidReadingListOrder     = errors.New("new order must contain exactly the books of the reading list")
+)
+
+// ReadingListModel - именованный упорядоченный список книг читателя.
+// Если ShareToken не пуст, список доступен на чтение по публичной ссылке
+type ReadingListModel struct {
+	ID         uuid.UUID
+	ReaderID   uuid.UUID
+	Name       string
+	ShareToken string
+	BookIDs    []uuid.UUID
+	CreatedAt  time.Time
+}
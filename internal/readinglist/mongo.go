@@ -0,0 +1,187 @@
+package readinglist
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const readingListCollection = "reading_list"
+
+type MongoRepo struct {
+	collection *mongo.Collection
+	logger     *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) IReadingListRepo {
+	return &MongoRepo{collection: db.Collection(readingListCollection), logger: logger}
+}
+
+type readingListDocument struct {
+	ID         string    `bson:"_id"`
+	ReaderID   string    `bson:"reader_id"`
+	Name       string    `bson:"name"`
+	ShareToken string    `bson:"share_token,omitempty"`
+	BookIDs    []string  `bson:"book_ids"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+func (r *MongoRepo) Create(ctx context.Context, readingList *ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting reading list into db")
+
+	if _, err := r.collection.InsertOne(ctx, toDocument(readingList)); err != nil {
+		logger.Errorf("error inserting reading list: %v", err)
+		return err
+	}
+
+	logger.Info("successfully inserted reading list")
+
+	return nil
+}
+
+func (r *MongoRepo) GetByID(ctx context.Context, ID uuid.UUID) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by ID")
+
+	return r.getOne(ctx, bson.M{"_id": ID.String()})
+}
+
+func (r *MongoRepo) GetByShareToken(ctx context.Context, shareToken string) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by share token")
+
+	return r.getOne(ctx, bson.M{"share_token": shareToken})
+}
+
+func (r *MongoRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading lists by reader ID")
+
+	cursor, err := r.collection.Find(ctx, bson.M{"reader_id": readerID.String()},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		logger.Errorf("error selecting reading lists: %v", err)
+		return nil, err
+	}
+
+	var documents []*readingListDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		logger.Errorf("error decoding reading lists: %v", err)
+		return nil, err
+	}
+
+	if len(documents) == 0 {
+		logger.Warn("reading lists not found")
+		return nil, ErrReadingListDoesNotExists
+	}
+
+	readingLists := make([]*ReadingListModel, len(documents))
+	for i, document := range documents {
+		if readingLists[i], err = fromDocument(document); err != nil {
+			logger.Errorf("error converting reading list: %v", err)
+			return nil, err
+		}
+	}
+
+	logger.Infof("found %d reading lists", len(readingLists))
+
+	return readingLists, nil
+}
+
+func (r *MongoRepo) Update(ctx context.Context, readingList *ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reading list")
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": readingList.ID.String()}, toDocument(readingList))
+	if err != nil {
+		logger.Errorf("error updating reading list: %v", err)
+		return err
+	}
+
+	logger.Info("successfully updated reading list")
+
+	return nil
+}
+
+func (r *MongoRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reading list")
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": ID.String()}); err != nil {
+		logger.Errorf("error deleting reading list: %v", err)
+		return err
+	}
+
+	logger.Info("successfully deleted reading list")
+
+	return nil
+}
+
+func (r *MongoRepo) getOne(ctx context.Context, filter bson.M) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	var document readingListDocument
+
+	err := r.collection.FindOne(ctx, filter).Decode(&document)
+	if err != nil && errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Warn("reading list not found")
+		return nil, ErrReadingListDoesNotExists
+	}
+	if err != nil {
+		logger.Errorf("error selecting reading list: %v", err)
+		return nil, err
+	}
+
+	return fromDocument(&document)
+}
+
+func toDocument(readingList *ReadingListModel) *readingListDocument {
+	bookIDs := make([]string, len(readingList.BookIDs))
+	for i, bookID := range readingList.BookIDs {
+		bookIDs[i] = bookID.String()
+	}
+
+	return &readingListDocument{
+		ID:         readingList.ID.String(),
+		ReaderID:   readingList.ReaderID.String(),
+		Name:       readingList.Name,
+		ShareToken: readingList.ShareToken,
+		BookIDs:    bookIDs,
+		CreatedAt:  readingList.CreatedAt,
+	}
+}
+
+func fromDocument(document *readingListDocument) (*ReadingListModel, error) {
+	ID, err := uuid.Parse(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	readerID, err := uuid.Parse(document.ReaderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookIDs := make([]uuid.UUID, len(document.BookIDs))
+	for i, bookID := range document.BookIDs {
+		if bookIDs[i], err = uuid.Parse(bookID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReadingListModel{
+		ID:         ID,
+		ReaderID:   readerID,
+		Name:       document.Name,
+		ShareToken: document.ShareToken,
+		BookIDs:    bookIDs,
+		CreatedAt:  document.CreatedAt,
+	}, nil
+}
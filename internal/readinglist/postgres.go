@@ -0,0 +1,216 @@
+package readinglist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) IReadingListRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+type readingListRow struct {
+	ID         uuid.UUID      `db:"id"`
+	ReaderID   uuid.UUID      `db:"reader_id"`
+	Name       string         `db:"name"`
+	ShareToken sql.NullString `db:"share_token"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (r *PostgresRepo) Create(ctx context.Context, readingList *ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("inserting reading list into db")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `insert into bs.reading_list values ($1, $2, $3, $4, $5)`
+
+	_, err := tr.ExecContext(ctx, query, readingList.ID, readingList.ReaderID, readingList.Name,
+		toNullString(readingList.ShareToken), readingList.CreatedAt)
+	if err != nil {
+		logger.Errorf("error inserting reading list: %v", err)
+		return err
+	}
+
+	if err = r.insertBooks(ctx, tr, readingList); err != nil {
+		return err
+	}
+
+	logger.Info("successfully inserted reading list")
+
+	return nil
+}
+
+func (r *PostgresRepo) GetByID(ctx context.Context, ID uuid.UUID) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by ID")
+
+	query := `select id, reader_id, name, share_token, created_at from bs.reading_list where id = $1`
+
+	return r.getOne(ctx, query, ID)
+}
+
+func (r *PostgresRepo) GetByShareToken(ctx context.Context, shareToken string) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading list by share token")
+
+	query := `select id, reader_id, name, share_token, created_at from bs.reading_list where share_token = $1`
+
+	return r.getOne(ctx, query, shareToken)
+}
+
+func (r *PostgresRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reading lists by reader ID")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select id, reader_id, name, share_token, created_at 
+			  from bs.reading_list 
+			  where reader_id = $1
+			  order by created_at`
+
+	var rows []*readingListRow
+	if err := tr.SelectContext(ctx, &rows, query, readerID); err != nil {
+		logger.Errorf("error selecting reading lists: %v", err)
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		logger.Warn("reading lists not found")
+		return nil, ErrReadingListDoesNotExists
+	}
+
+	readingLists := make([]*ReadingListModel, len(rows))
+	for i, row := range rows {
+		readingList, err := r.withBooks(ctx, tr, row)
+		if err != nil {
+			return nil, err
+		}
+		readingLists[i] = readingList
+	}
+
+	logger.Infof("found %d reading lists", len(readingLists))
+
+	return readingLists, nil
+}
+
+func (r *PostgresRepo) Update(ctx context.Context, readingList *ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reading list")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reading_list set name = $1, share_token = $2 where id = $3`
+
+	_, err := tr.ExecContext(ctx, query, readingList.Name, toNullString(readingList.ShareToken), readingList.ID)
+	if err != nil {
+		logger.Errorf("error updating reading list: %v", err)
+		return err
+	}
+
+	query = `delete from bs.reading_list_book where reading_list_id = $1`
+
+	if _, err = tr.ExecContext(ctx, query, readingList.ID); err != nil {
+		logger.Errorf("error deleting reading list books: %v", err)
+		return err
+	}
+
+	if err = r.insertBooks(ctx, tr, readingList); err != nil {
+		return err
+	}
+
+	logger.Info("successfully updated reading list")
+
+	return nil
+}
+
+func (r *PostgresRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reading list")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `delete from bs.reading_list where id = $1`
+
+	if _, err := tr.ExecContext(ctx, query, ID); err != nil {
+		logger.Errorf("error deleting reading list: %v", err)
+		return err
+	}
+
+	logger.Info("successfully deleted reading list")
+
+	return nil
+}
+
+func (r *PostgresRepo) getOne(ctx context.Context, query string, arg any) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	var row readingListRow
+	err := tr.GetContext(ctx, &row, query, arg)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		logger.Warn("reading list not found")
+		return nil, ErrReadingListDoesNotExists
+	}
+	if err != nil {
+		logger.Errorf("error selecting reading list: %v", err)
+		return nil, err
+	}
+
+	return r.withBooks(ctx, tr, &row)
+}
+
+func (r *PostgresRepo) withBooks(ctx context.Context, tr trmsqlx.Tr, row *readingListRow) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	query := `select book_id from bs.reading_list_book where reading_list_id = $1 order by position`
+
+	var bookIDs []uuid.UUID
+	if err := tr.SelectContext(ctx, &bookIDs, query, row.ID); err != nil {
+		logger.Errorf("error selecting reading list books: %v", err)
+		return nil, err
+	}
+
+	return &ReadingListModel{
+		ID:         row.ID,
+		ReaderID:   row.ReaderID,
+		Name:       row.Name,
+		ShareToken: row.ShareToken.String,
+		BookIDs:    bookIDs,
+		CreatedAt:  row.CreatedAt,
+	}, nil
+}
+
+func (r *PostgresRepo) insertBooks(ctx context.Context, tr trmsqlx.Tr, readingList *ReadingListModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+
+	query := `insert into bs.reading_list_book values ($1, $2, $3)`
+
+	for position, bookID := range readingList.BookIDs {
+		if _, err := tr.ExecContext(ctx, query, readingList.ID, bookID, position); err != nil {
+			logger.Errorf("error inserting reading list book: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
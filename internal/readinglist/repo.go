@@ -0,0 +1,15 @@
+package readinglist
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+type IReadingListRepo interface {
+	Create(ctx context.Context, readingList *ReadingListModel) error
+	GetByID(ctx context.Context, ID uuid.UUID) (*ReadingListModel, error)
+	GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*ReadingListModel, error)
+	GetByShareToken(ctx context.Context, shareToken string) (*ReadingListModel, error)
+	Update(ctx context.Context, readingList *ReadingListModel) error
+	Delete(ctx context.Context, ID uuid.UUID) error
+}
@@ -0,0 +1,360 @@
+package readinglist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const shareTokenBytes = 24
+
+type IReadingListService interface {
+	Create(ctx context.Context, readerID uuid.UUID, name string) (*ReadingListModel, error)
+	GetByID(ctx context.Context, readerID, ID uuid.UUID) (*ReadingListModel, error)
+	GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*ReadingListModel, error)
+	GetByShareToken(ctx context.Context, shareToken string) (*ReadingListModel, error)
+	Rename(ctx context.Context, readerID, ID uuid.UUID, name string) error
+	AddBook(ctx context.Context, readerID, ID, bookID uuid.UUID) error
+	RemoveBook(ctx context.Context, readerID, ID, bookID uuid.UUID) error
+	Reorder(ctx context.Context, readerID, ID uuid.UUID, bookIDs []uuid.UUID) error
+	Share(ctx context.Context, readerID, ID uuid.UUID) (string, error)
+	Unshare(ctx context.Context, readerID, ID uuid.UUID) error
+	Delete(ctx context.Context, readerID, ID uuid.UUID) error
+}
+
+type ReadingListService struct {
+	readingListRepo    IReadingListRepo
+	bookRepo           intfRepo.IBookRepo
+	transactionManager transact.ITransactionManager
+	logger             *logrus.Entry
+}
+
+func NewReadingListService(
+	readingListRepo IReadingListRepo,
+	bookRepo intfRepo.IBookRepo,
+	transactionManager transact.ITransactionManager,
+	logger *logrus.Entry,
+) IReadingListService {
+	return &ReadingListService{
+		readingListRepo:    readingListRepo,
+		bookRepo:           bookRepo,
+		transactionManager: transactionManager,
+		logger:             logger,
+	}
+}
+
+func (rls *ReadingListService) Create(ctx context.Context, readerID uuid.UUID, name string) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to create reading list")
+
+	name = strings.TrimSpace(name)
+	if err := rls.checkName(name); err != nil {
+		return nil, err
+	}
+
+	readingList := &ReadingListModel{
+		ID:        uuid.New(),
+		ReaderID:  readerID,
+		Name:      name,
+		BookIDs:   []uuid.UUID{},
+		CreatedAt: time.Now(),
+	}
+
+	err := rls.transactionManager.Do(ctx, func(ctx context.Context) error {
+		existing, err := rls.readingListRepo.GetByReaderID(ctx, readerID)
+		if err != nil && !errors.Is(err, ErrReadingListDoesNotExists) {
+			return err
+		}
+
+		if len(existing) >= MaxReadingListsPerReader {
+			logger.Warn("reading lists limit exceeded")
+			return ErrReadingListsLimitExceeded
+		}
+
+		for _, other := range existing {
+			if strings.EqualFold(other.Name, name) {
+				logger.Warn("reading list with this name already exists")
+				return ErrReadingListAlreadyExist
+			}
+		}
+
+		return rls.readingListRepo.Create(ctx, readingList)
+	})
+	if err != nil {
+		logger.Errorf("error creating reading list: %v", err)
+		return nil, err
+	}
+
+	logger.Info("reading list successfully created")
+
+	return readingList, nil
+}
+
+func (rls *ReadingListService) GetByID(ctx context.Context, readerID, ID uuid.UUID) (*ReadingListModel, error) {
+	return rls.getOwned(ctx, readerID, ID)
+}
+
+func (rls *ReadingListService) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to get reading lists by reader ID")
+
+	readingLists, err := rls.readingListRepo.GetByReaderID(ctx, readerID)
+	if err != nil {
+		logger.Errorf("error getting reading lists: %v", err)
+		return nil, err
+	}
+
+	return readingLists, nil
+}
+
+func (rls *ReadingListService) GetByShareToken(ctx context.Context, shareToken string) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to get shared reading list")
+
+	if shareToken == "" {
+		return nil, ErrReadingListDoesNotExists
+	}
+
+	readingList, err := rls.readingListRepo.GetByShareToken(ctx, shareToken)
+	if err != nil {
+		logger.Errorf("error getting shared reading list: %v", err)
+		return nil, err
+	}
+
+	return readingList, nil
+}
+
+func (rls *ReadingListService) Rename(ctx context.Context, readerID, ID uuid.UUID, name string) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to rename reading list")
+
+	name = strings.TrimSpace(name)
+	if err := rls.checkName(name); err != nil {
+		return err
+	}
+
+	return rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		readingLists, err := rls.readingListRepo.GetByReaderID(ctx, readerID)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range readingLists {
+			if other.ID != ID && strings.EqualFold(other.Name, name) {
+				return ErrReadingListAlreadyExist
+			}
+		}
+
+		readingList.Name = name
+
+		return nil
+	})
+}
+
+func (rls *ReadingListService) AddBook(ctx context.Context, readerID, ID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to add book to reading list")
+
+	return rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		for _, existing := range readingList.BookIDs {
+			if existing == bookID {
+				return ErrDuplicateBookInReadingList
+			}
+		}
+
+		if len(readingList.BookIDs) >= MaxBooksPerReadingList {
+			return ErrReadingListBooksLimitExceed
+		}
+
+		if _, err := rls.bookRepo.GetByID(ctx, bookID); err != nil {
+			return err
+		}
+
+		readingList.BookIDs = append(readingList.BookIDs, bookID)
+
+		return nil
+	})
+}
+
+func (rls *ReadingListService) RemoveBook(ctx context.Context, readerID, ID, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to remove book from reading list")
+
+	return rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		bookIDs := make([]uuid.UUID, 0, len(readingList.BookIDs))
+		for _, existing := range readingList.BookIDs {
+			if existing != bookID {
+				bookIDs = append(bookIDs, existing)
+			}
+		}
+
+		readingList.BookIDs = bookIDs
+
+		return nil
+	})
+}
+
+// Reorder задает новый порядок книг в списке. Новый порядок должен содержать
+// ровно те же книги, что уже есть в списке
+func (rls *ReadingListService) Reorder(ctx context.Context, readerID, ID uuid.UUID, bookIDs []uuid.UUID) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to reorder reading list")
+
+	return rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		if len(bookIDs) != len(readingList.BookIDs) {
+			return ErrInvalidReadingListOrder
+		}
+
+		current := make(map[uuid.UUID]bool, len(readingList.BookIDs))
+		for _, bookID := range readingList.BookIDs {
+			current[bookID] = true
+		}
+
+		for _, bookID := range bookIDs {
+			if !current[bookID] {
+				return ErrInvalidReadingListOrder
+			}
+			delete(current, bookID)
+		}
+
+		readingList.BookIDs = bookIDs
+
+		return nil
+	})
+}
+
+func (rls *ReadingListService) Share(ctx context.Context, readerID, ID uuid.UUID) (string, error) {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to share reading list")
+
+	var shareToken string
+	err := rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		if readingList.ShareToken == "" {
+			token, err := newShareToken()
+			if err != nil {
+				return err
+			}
+			readingList.ShareToken = token
+		}
+
+		shareToken = readingList.ShareToken
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return shareToken, nil
+}
+
+func (rls *ReadingListService) Unshare(ctx context.Context, readerID, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to unshare reading list")
+
+	return rls.update(ctx, readerID, ID, func(readingList *ReadingListModel) error {
+		readingList.ShareToken = ""
+
+		return nil
+	})
+}
+
+func (rls *ReadingListService) Delete(ctx context.Context, readerID, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, rls.logger)
+	logger.Info("attempting to delete reading list")
+
+	err := rls.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := rls.getOwned(ctx, readerID, ID); err != nil {
+			return err
+		}
+
+		return rls.readingListRepo.Delete(ctx, ID)
+	})
+	if err != nil {
+		logger.Errorf("error deleting reading list: %v", err)
+		return err
+	}
+
+	logger.Info("reading list successfully deleted")
+
+	return nil
+}
+
+func (rls *ReadingListService) update(
+	ctx context.Context,
+	readerID, ID uuid.UUID,
+	apply func(readingList *ReadingListModel) error,
+) error {
+	logger := logging.FromContext(ctx, rls.logger)
+
+	err := rls.transactionManager.Do(ctx, func(ctx context.Context) error {
+		readingList, err := rls.getOwned(ctx, readerID, ID)
+		if err != nil {
+			return err
+		}
+
+		if err = apply(readingList); err != nil {
+			return err
+		}
+
+		return rls.readingListRepo.Update(ctx, readingList)
+	})
+	if err != nil {
+		logger.Errorf("error updating reading list: %v", err)
+		return err
+	}
+
+	logger.Info("reading list successfully updated")
+
+	return nil
+}
+
+// getOwned возвращает список только его владельцу: для чужого списка
+// возвращается та же ошибка, что и для несуществующего
+func (rls *ReadingListService) getOwned(ctx context.Context, readerID, ID uuid.UUID) (*ReadingListModel, error) {
+	logger := logging.FromContext(ctx, rls.logger)
+
+	readingList, err := rls.readingListRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if readingList.ReaderID != readerID {
+		logger.Warn("reading list belongs to another reader")
+		return nil, ErrReadingListDoesNotExists
+	}
+
+	return readingList, nil
+}
+
+func (rls *ReadingListService) checkName(name string) error {
+	if name == "" {
+		rls.logger.Warn("empty reading list name")
+		return ErrEmptyReadingListName
+	}
+
+	if utf8.RuneCountInString(name) > MaxReadingListNameLen {
+		rls.logger.Warn("invalid reading list name length")
+		return ErrInvalidReadingListNameLen
+	}
+
+	return nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,29 @@
+package replication
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"time"
+)
+
+// Middleware обеспечивает чтение своих записей. Запрос, в котором была
+// запись, дочитывает с мастера, а клиент после записи закрепляется за
+// мастером на stickiness, чтобы следующий запрос, попавший на другой
+// экземпляр, не прочитал устаревшие данные с отстающей реплики. Клиент - это
+// читатель из access-токена или IP. При недоступности Redis чтение идет с мастера
+func Middleware(store IPinStore, stickiness time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		client, _ := session.ClientIdentity(c.Request)
+
+		isPinned, err := store.IsPinned(ctx, client)
+		ctx = WithPin(ctx, isPinned || err != nil)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if Written(ctx) {
+			_ = store.Pin(ctx, client, stickiness)
+		}
+	}
+}
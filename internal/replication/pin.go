@@ -0,0 +1,83 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+	"time"
+)
+
+const pinKeyPrefix = "replication:pin:"
+
+type pinKey struct{}
+
+// pin отмечает запрос, в котором уже была запись: его чтения идут на мастер
+type pin struct {
+	primary atomic.Bool
+	written atomic.Bool
+}
+
+// WithPin добавляет в контекст отметку о записи. Если primary, все чтения
+// сразу идут на мастер
+func WithPin(ctx context.Context, primary bool) context.Context {
+	p := &pin{}
+	p.primary.Store(primary)
+
+	return context.WithValue(ctx, pinKey{}, p)
+}
+
+// Written сообщает, была ли в контексте запись
+func Written(ctx context.Context) bool {
+	p, ok := ctx.Value(pinKey{}).(*pin)
+	return ok && p.written.Load()
+}
+
+func pinned(ctx context.Context) bool {
+	p, ok := ctx.Value(pinKey{}).(*pin)
+	return ok && (p.primary.Load() || p.written.Load())
+}
+
+// IPinStore хранит клиентов, недавно писавших в БД, чтобы их следующие
+// запросы на любом экземпляре приложения читали свои записи с мастера
+type IPinStore interface {
+	Pin(ctx context.Context, client string, ttl time.Duration) error
+	IsPinned(ctx context.Context, client string) (bool, error)
+}
+
+type RedisPinStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisPinStore(client *redis.Client, logger *logrus.Entry) IPinStore {
+	return &RedisPinStore{client: client, logger: logger}
+}
+
+func (s *RedisPinStore) Pin(ctx context.Context, client string, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Set(ctx, pinKeyPrefix+client, 1, ttl).Err(); err != nil {
+		logger.Errorf("error pinning client to primary: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisPinStore) IsPinned(ctx context.Context, client string) (bool, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	err := s.client.Get(ctx, pinKeyPrefix+client).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Errorf("error checking client pin: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,199 @@
+package replication
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/similar"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	"time"
+)
+
+// Декораторы репозиториев направляют чтение на реплику, выбранную Router, а
+// запись - на мастер. Репозитории реплик создаются над пулами в том же порядке,
+// что и в NewRouter. Читатели и данные входа всегда читаются с мастера
+
+type routed[T any] struct {
+	router   *Router
+	primary  T
+	replicas []T
+}
+
+func (r *routed[T]) read(ctx context.Context) T {
+	if index, ok := r.router.replicaIndex(ctx); ok {
+		return r.replicas[index]
+	}
+
+	return r.primary
+}
+
+func (r *routed[T]) write(ctx context.Context) T {
+	r.router.written(ctx)
+	return r.primary
+}
+
+type BookRepo struct {
+	routed[intfRepo.IBookRepo]
+}
+
+func NewBookRepo(router *Router, primary intfRepo.IBookRepo, replicas ...intfRepo.IBookRepo) intfRepo.IBookRepo {
+	return &BookRepo{routed[intfRepo.IBookRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *BookRepo) Create(ctx context.Context, book *models.BookModel) error {
+	return r.write(ctx).Create(ctx, book)
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	return r.read(ctx).GetByID(ctx, ID)
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (*models.BookModel, error) {
+	return r.read(ctx).GetByTitle(ctx, title)
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	return r.write(ctx).Delete(ctx, ID)
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) error {
+	return r.write(ctx).Update(ctx, book)
+}
+
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	return r.read(ctx).GetByParams(ctx, params)
+}
+
+type LibCardRepo struct {
+	routed[intfRepo.ILibCardRepo]
+}
+
+func NewLibCardRepo(router *Router, primary intfRepo.ILibCardRepo, replicas ...intfRepo.ILibCardRepo) intfRepo.ILibCardRepo {
+	return &LibCardRepo{routed[intfRepo.ILibCardRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *LibCardRepo) Create(ctx context.Context, libCard *models.LibCardModel) error {
+	return r.write(ctx).Create(ctx, libCard)
+}
+
+func (r *LibCardRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*models.LibCardModel, error) {
+	return r.read(ctx).GetByReaderID(ctx, readerID)
+}
+
+func (r *LibCardRepo) GetByNum(ctx context.Context, libCardNum string) (*models.LibCardModel, error) {
+	return r.read(ctx).GetByNum(ctx, libCardNum)
+}
+
+func (r *LibCardRepo) Update(ctx context.Context, libCard *models.LibCardModel) error {
+	return r.write(ctx).Update(ctx, libCard)
+}
+
+type RatingRepo struct {
+	routed[intfRepo.IRatingRepo]
+}
+
+func NewRatingRepo(router *Router, primary intfRepo.IRatingRepo, replicas ...intfRepo.IRatingRepo) intfRepo.IRatingRepo {
+	return &RatingRepo{routed[intfRepo.IRatingRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, rating *models.RatingModel) error {
+	return r.write(ctx).Create(ctx, rating)
+}
+
+func (r *RatingRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (*models.RatingModel, error) {
+	return r.read(ctx).GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *RatingRepo) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]*models.RatingModel, error) {
+	return r.read(ctx).GetByBookID(ctx, bookID, limit, offset)
+}
+
+type ReservationRepo struct {
+	routed[intfRepo.IReservationRepo]
+}
+
+func NewReservationRepo(router *Router, primary intfRepo.IReservationRepo, replicas ...intfRepo.IReservationRepo) intfRepo.IReservationRepo {
+	return &ReservationRepo{routed[intfRepo.IReservationRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, reservation *models.ReservationModel) error {
+	return r.write(ctx).Create(ctx, reservation)
+}
+
+func (r *ReservationRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) ([]*models.ReservationModel, error) {
+	return r.read(ctx).GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *ReservationRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.ReservationModel, error) {
+	return r.read(ctx).GetByID(ctx, ID)
+}
+
+func (r *ReservationRepo) GetByBookID(ctx context.Context, bookID uuid.UUID) ([]*models.ReservationModel, error) {
+	return r.read(ctx).GetByBookID(ctx, bookID)
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, reservation *models.ReservationModel) error {
+	return r.write(ctx).Update(ctx, reservation)
+}
+
+func (r *ReservationRepo) GetExpiredByReaderID(ctx context.Context, readerID uuid.UUID) ([]*models.ReservationModel, error) {
+	return r.read(ctx).GetExpiredByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetActiveByReaderID(ctx context.Context, readerID uuid.UUID) ([]*models.ReservationModel, error) {
+	return r.read(ctx).GetActiveByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) ([]*models.ReservationModel, error) {
+	return r.read(ctx).GetByReaderID(ctx, readerID, limit, offset)
+}
+
+type SimilarBookRepo struct {
+	routed[similar.ISimilarBookRepo]
+}
+
+func NewSimilarBookRepo(router *Router, primary similar.ISimilarBookRepo, replicas ...similar.ISimilarBookRepo) similar.ISimilarBookRepo {
+	return &SimilarBookRepo{routed[similar.ISimilarBookRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *SimilarBookRepo) Refresh(ctx context.Context) error {
+	return r.write(ctx).Refresh(ctx)
+}
+
+func (r *SimilarBookRepo) GetByBook(ctx context.Context, book *models.BookModel, limit, offset int) ([]*similar.ScoredBook, error) {
+	return r.read(ctx).GetByBook(ctx, book, limit, offset)
+}
+
+type DeletedBookRepo struct {
+	routed[softdelete.IDeletedBookRepo]
+}
+
+func NewDeletedBookRepo(router *Router, primary softdelete.IDeletedBookRepo, replicas ...softdelete.IDeletedBookRepo) softdelete.IDeletedBookRepo {
+	return &DeletedBookRepo{routed[softdelete.IDeletedBookRepo]{router: router, primary: primary, replicas: replicas}}
+}
+
+func (r *DeletedBookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	return r.write(ctx).Delete(ctx, ID)
+}
+
+func (r *DeletedBookRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	return r.write(ctx).Restore(ctx, ID)
+}
+
+func (r *DeletedBookRepo) GetDeleted(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return r.read(ctx).GetDeleted(ctx, IDs)
+}
+
+func (r *DeletedBookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	return r.read(ctx).GetByParams(ctx, params)
+}
+
+func (r *DeletedBookRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return r.read(ctx).GetPurgeable(ctx, before)
+}
+
+func (r *DeletedBookRepo) Purge(ctx context.Context, ID uuid.UUID, before time.Time) error {
+	return r.write(ctx).Purge(ctx, ID, before)
+}
@@ -0,0 +1,114 @@
+package replication
+
+import (
+	"context"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+	"time"
+)
+
+// lagQuery возвращает отставание реплики. Если реплика применила все
+// полученные WAL-записи, отставание нулевое, даже когда на мастере давно не
+// было записей и pg_last_xact_replay_timestamp() устарел
+const lagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+type replica struct {
+	db *sqlx.DB
+	// available - реплика отвечает и отстает не больше maxLag
+	available atomic.Bool
+}
+
+// Router решает, можно ли выполнить чтение на реплике. Чтение уходит на
+// мастер, если оно выполняется в транзакции, если запрос закреплен за
+// мастером после записи (см. Pin) или если все реплики недоступны либо
+// отстают больше maxLag. До первой проверки отставания реплики не используются
+type Router struct {
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+	logger   *logrus.Entry
+}
+
+func NewRouter(replicas []*sqlx.DB, maxLag time.Duration, logger *logrus.Entry) *Router {
+	router := &Router{
+		replicas: make([]*replica, len(replicas)),
+		maxLag:   maxLag,
+		logger:   logger,
+	}
+	for i, db := range replicas {
+		router.replicas[i] = &replica{db: db}
+	}
+
+	return router
+}
+
+// Run проверяет отставание реплик с заданным интервалом до отмены контекста
+func (r *Router) Run(ctx context.Context, interval time.Duration) {
+	r.CheckLag(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckLag(ctx)
+		}
+	}
+}
+
+// CheckLag измеряет отставание каждой реплики и исключает из чтения те,
+// что недоступны или отстают больше maxLag
+func (r *Router) CheckLag(ctx context.Context) {
+	for i, replica := range r.replicas {
+		var lagSeconds float64
+		err := replica.db.GetContext(ctx, &lagSeconds, lagQuery)
+		lag := time.Duration(lagSeconds * float64(time.Second))
+
+		available := err == nil && lag <= r.maxLag
+		if replica.available.Swap(available) != available {
+			logger := r.logger.WithFields(logrus.Fields{"replica": i, "lag": lag.String()})
+			switch {
+			case err != nil:
+				logger.Warnf("postgres replica is unavailable: %v", err)
+			case !available:
+				logger.Warn("postgres replica lags behind, reading from primary")
+			default:
+				logger.Info("postgres replica is available")
+			}
+		}
+	}
+}
+
+// replicaIndex возвращает номер реплики для чтения или false, если читать
+// нужно с мастера. Доступные реплики выбираются по кругу
+func (r *Router) replicaIndex(ctx context.Context) (int, bool) {
+	if len(r.replicas) == 0 || trmcontext.DefaultManager.Default(ctx) != nil || pinned(ctx) {
+		return 0, false
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		index := int((start + uint64(i)) % uint64(len(r.replicas)))
+		if r.replicas[index].available.Load() {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+// written закрепляет дальнейшие чтения запроса за мастером
+func (r *Router) written(ctx context.Context) {
+	if p, ok := ctx.Value(pinKey{}).(*pin); ok {
+		p.written.Store(true)
+	}
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type requestInfoCtxKey struct{}
+
+type clientIPCtxKey struct{}
+
+// RequestInfo - сведения об устройстве, с которого пришел запрос. SessionID
+// заполняется при чтении refresh-токена и позволяет продолжить ту же сессию
+// при сохранении нового токена в рамках одного запроса
+type RequestInfo struct {
+	UserAgent string
+	IP        string
+	SessionID uuid.UUID
+}
+
+// WithRequestInfo кладет в контекст каждого запроса сведения об устройстве.
+// Обертка ставится вокруг всего роутера, так как вход и обновление токенов
+// обрабатываются внешними обработчиками
+func WithRequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := &RequestInfo{
+			UserAgent: r.UserAgent(),
+			IP:        ClientIP(r),
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), info)))
+	})
+}
+
+func NewContext(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoCtxKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) *RequestInfo {
+	if info, ok := ctx.Value(requestInfoCtxKey{}).(*RequestInfo); ok {
+		return info
+	}
+
+	return nil
+}
+
+// ParseTrustedProxies разбирает сети прокси из параметра server.trustedProxies
+func ParseTrustedProxies(cidrs []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+
+	return proxies, nil
+}
+
+// ClientIPMiddleware определяет адрес клиента один раз на запрос и кладет его в
+// контекст, откуда его берет ClientIP. Middleware ставится первым, так как адрес
+// нужен трассировке и ограничению частоты запросов
+func ClientIPMiddleware(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := resolveClientIP(c.Request, trustedProxies)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), clientIPCtxKey{}, ip))
+
+		c.Next()
+	}
+}
+
+// ClientIP возвращает адрес клиента, определенный ClientIPMiddleware. Без
+// middleware заголовкам не доверяем и возвращаем адрес соединения
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPCtxKey{}).(string); ok {
+		return ip
+	}
+
+	return remoteIP(r)
+}
+
+// ClientIdentity возвращает ключ клиента для счетчиков и блокировок в Redis:
+// читатель из middleware.IdentifyClient, а для анонимных запросов - адрес
+// клиента. Вторым значением возвращается роль читателя
+func ClientIdentity(r *http.Request) (string, string) {
+	if client := middleware.ClientFromContext(r.Context()); client.ReaderID != "" {
+		return "reader:" + client.ReaderID, client.Role
+	}
+
+	return "ip:" + ClientIP(r), ""
+}
+
+// resolveClientIP доверяет X-Forwarded-For и X-Real-IP, только если соединение
+// пришло от прокси из trustedProxies. В X-Forwarded-For каждый прокси дописывает
+// адрес справа, а левые значения может подставить сам клиент, поэтому берется
+// самый правый адрес, не принадлежащий доверенным прокси
+func resolveClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote := remoteIP(r)
+	if !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+			if !isTrustedProxy(hop, trustedProxies) || i == 0 {
+				return hop
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+
+	return remote
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
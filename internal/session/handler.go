@@ -0,0 +1,136 @@
+package session
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	sessionService ISessionService
+	tokenManager   auth.ITokenManager
+}
+
+func NewHandler(sessionService ISessionService, tokenManager auth.ITokenManager) *Handler {
+	return &Handler{sessionService: sessionService, tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		auth := api.Group("/auth", middleware.Authenticate(h.tokenManager))
+		{
+			auth.POST("/logout", h.logout)
+			auth.GET("/sessions", h.getSessions)
+			auth.DELETE("/sessions/:id", h.revokeSession)
+		}
+	}
+}
+
+// @Summary Метод выхода из аккаунта на текущем устройстве
+// @Security ApiKeyAuth
+// @Tags auth
+// @ID logout
+// @Accept json
+// @Produce json
+// @Param input body dto.LogoutInputDTO true "Refresh-токен текущей сессии"
+// @Success 204 "Успешный выход"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 404 {object} dto.ErrorResponse "Сессия не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/logout [post]
+func (h *Handler) logout(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.LogoutInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.sessionService.Logout(c.Request.Context(), readerID, middleware.GetAccessToken(c), input.RefreshToken); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Метод получения активных сессий читателя
+// @Security ApiKeyAuth
+// @Tags auth
+// @ID getSessions
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.SessionOutputDTO "Успешное получение сессий"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 404 {object} dto.ErrorResponse "Сессии не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/sessions [get]
+func (h *Handler) getSessions(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	sessions, err := h.sessionService.GetByReaderID(c.Request.Context(), readerID)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	sessionsDTO := make([]*dto.SessionOutputDTO, len(sessions))
+	for i, session := range sessions {
+		sessionsDTO[i] = &dto.SessionOutputDTO{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, sessionsDTO)
+}
+
+// @Summary Метод завершения сессии на другом устройстве
+// @Security ApiKeyAuth
+// @Tags auth
+// @ID revokeSession
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор сессии"
+// @Success 204 "Успешное завершение сессии"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 404 {object} dto.ErrorResponse "Сессия не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (h *Handler) revokeSession(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err = h.sessionService.Revoke(c.Request.Context(), readerID, sessionID); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	if errors.Is(err, ErrSessionDoesNotExists) {
+		status = http.StatusNotFound
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrSessionDoesNotExists = errors.New("session does not exists")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
+)
+
+// SessionModel - сессия читателя на одном устройстве. Сессия живет, пока
+// ее refresh-токен ротируется; все токены одной сессии образуют семейство.
+// AccessTokenID - идентификатор последнего выданного сессии access-токена,
+// он запрещается при завершении сессии
+type SessionModel struct {
+	ID                   uuid.UUID
+	ReaderID             uuid.UUID
+	UserAgent            string
+	IP                   string
+	CreatedAt            time.Time
+	LastUsedAt           time.Time
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+}
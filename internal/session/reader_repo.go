@@ -0,0 +1,95 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// ReaderRepo подменяет хранение refresh-токенов в репозитории читателей:
+// вместо одного токена на читателя у каждого читателя может быть несколько
+// сессий, а повторное использование погашенного токена отзывает всю сессию
+type ReaderRepo struct {
+	intfRepo.IReaderRepo
+	sessionStore    ISessionStore
+	refreshTokenTTL time.Duration
+	logger          *logrus.Entry
+}
+
+func NewReaderRepo(
+	readerRepo intfRepo.IReaderRepo,
+	sessionStore ISessionStore,
+	refreshTokenTTL time.Duration,
+	logger *logrus.Entry,
+) intfRepo.IReaderRepo {
+	return &ReaderRepo{
+		IReaderRepo:     readerRepo,
+		sessionStore:    sessionStore,
+		refreshTokenTTL: refreshTokenTTL,
+		logger:          logger,
+	}
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, ID uuid.UUID, token string, ttl time.Duration) error {
+	info := requestInfoFromContext(ctx)
+	if info != nil && info.SessionID != uuid.Nil {
+		return r.sessionStore.Rotate(ctx, info.SessionID, token, ttl)
+	}
+
+	now := time.Now()
+	session := &SessionModel{
+		ID:         uuid.New(),
+		ReaderID:   ID,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	if info != nil {
+		session.UserAgent = info.UserAgent
+		session.IP = info.IP
+	}
+
+	return r.sessionStore.Create(ctx, session, token, ttl)
+}
+
+func (r *ReaderRepo) GetByRefreshToken(ctx context.Context, token string) (*models.ReaderModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	session, err := r.sessionStore.Consume(ctx, token, r.refreshTokenTTL)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		logger.Warn("refresh token reuse detected, session revoked")
+		return nil, errs.ErrReaderDoesNotExists
+	}
+	if errors.Is(err, ErrSessionDoesNotExists) {
+		return r.getByLegacyRefreshToken(ctx, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.SessionID = session.ID
+	}
+
+	return r.IReaderRepo.GetByID(ctx, session.ReaderID)
+}
+
+// getByLegacyRefreshToken принимает токены, выданные до появления сессий.
+// Такой токен гасится, а вместо него создается новая сессия
+func (r *ReaderRepo) getByLegacyRefreshToken(ctx context.Context, token string) (*models.ReaderModel, error) {
+	reader, err := r.IReaderRepo.GetByRefreshToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.sessionStore.DeleteLegacyToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type IAccessTokenRevoker interface {
+	Revoke(ctx context.Context, accessToken string) error
+	// TokenID возвращает идентификатор access-токена и срок его действия
+	TokenID(accessToken string) (string, time.Time, error)
+	// RevokeID запрещает access-токен по идентификатору из TokenID
+	RevokeID(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeReader(ctx context.Context, readerID uuid.UUID) error
+}
+
+// Revoker завершает все сессии читателя и запрещает уже выданные ему
+// access-токены. Используется при смене или сбросе пароля и удалении аккаунта
+type Revoker struct {
+	sessionStore       ISessionStore
+	accessTokenRevoker IAccessTokenRevoker
+}
+
+func NewRevoker(sessionStore ISessionStore, accessTokenRevoker IAccessTokenRevoker) *Revoker {
+	return &Revoker{sessionStore: sessionStore, accessTokenRevoker: accessTokenRevoker}
+}
+
+func (r *Revoker) DeleteByReaderID(ctx context.Context, readerID uuid.UUID) error {
+	if err := r.sessionStore.DeleteByReaderID(ctx, readerID); err != nil {
+		return err
+	}
+
+	return r.accessTokenRevoker.RevokeReader(ctx, readerID)
+}
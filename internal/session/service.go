@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type ISessionService interface {
+	GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*SessionModel, error)
+	Logout(ctx context.Context, readerID uuid.UUID, accessToken, refreshToken string) error
+	Revoke(ctx context.Context, readerID, ID uuid.UUID) error
+}
+
+type SessionService struct {
+	sessionStore       ISessionStore
+	accessTokenRevoker IAccessTokenRevoker
+	logger             *logrus.Entry
+}
+
+func NewSessionService(
+	sessionStore ISessionStore,
+	accessTokenRevoker IAccessTokenRevoker,
+	logger *logrus.Entry,
+) ISessionService {
+	return &SessionService{sessionStore: sessionStore, accessTokenRevoker: accessTokenRevoker, logger: logger}
+}
+
+func (ss *SessionService) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*SessionModel, error) {
+	logger := logging.FromContext(ctx, ss.logger)
+	logger.Info("attempting to get reader sessions")
+
+	sessions, err := ss.sessionStore.GetByReaderID(ctx, readerID)
+	if err != nil {
+		logger.Errorf("error getting reader sessions: %v", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Logout завершает сессию, которой принадлежит refresh-токен, и запрещает
+// access-токен, с которым пришел запрос
+func (ss *SessionService) Logout(ctx context.Context, readerID uuid.UUID, accessToken, refreshToken string) error {
+	logger := logging.FromContext(ctx, ss.logger)
+	logger.Info("attempting to logout")
+
+	session, err := ss.sessionStore.GetByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		logger.Errorf("error getting session: %v", err)
+		return err
+	}
+
+	if session.ReaderID != readerID {
+		logger.Warn("session belongs to another reader")
+		return ErrSessionDoesNotExists
+	}
+
+	if err = ss.sessionStore.Delete(ctx, session.ID); err != nil {
+		logger.Errorf("error deleting session: %v", err)
+		return err
+	}
+
+	if err = ss.accessTokenRevoker.Revoke(ctx, accessToken); err != nil {
+		logger.Errorf("error revoking access token: %v", err)
+		return err
+	}
+
+	logger.Info("successfully logged out")
+
+	return nil
+}
+
+// Revoke завершает сессию читателя на другом устройстве и запрещает последний
+// выданный ей access-токен
+func (ss *SessionService) Revoke(ctx context.Context, readerID, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, ss.logger)
+	logger.Info("attempting to revoke session")
+
+	session, err := ss.sessionStore.GetByID(ctx, ID)
+	if err != nil {
+		logger.Errorf("error getting session: %v", err)
+		return err
+	}
+
+	if session.ReaderID != readerID {
+		logger.Warn("session belongs to another reader")
+		return ErrSessionDoesNotExists
+	}
+
+	if err = ss.sessionStore.Delete(ctx, ID); err != nil {
+		logger.Errorf("error deleting session: %v", err)
+		return err
+	}
+
+	if session.AccessTokenID != "" {
+		if err = ss.accessTokenRevoker.RevokeID(ctx, session.AccessTokenID, session.AccessTokenExpiresAt); err != nil {
+			logger.Errorf("error revoking access token: %v", err)
+			return err
+		}
+	}
+
+	logger.Info("session successfully revoked")
+
+	return nil
+}
@@ -0,0 +1,372 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	sessionKeyPrefix        = "session:"
+	tokenKeyPrefix          = "session:token:"
+	usedTokenKeyPrefix      = "session:used_token:"
+	readerSessionsKeyPrefix = "session:reader:"
+
+	readerIDField   = "reader_id"
+	userAgentField  = "user_agent"
+	ipField         = "ip"
+	createdAtField  = "created_at"
+	lastUsedAtField = "last_used_at"
+	tokenHashField  = "token_hash"
+
+	accessTokenIDField        = "access_token_id"
+	accessTokenExpiresAtField = "access_token_expires_at"
+)
+
+// setAccessTokenScript не пишет в уже удаленную сессию, иначе ее ключ
+// остался бы в Redis без TTL
+var setAccessTokenScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+
+return redis.call('HSET', KEYS[1], ARGV[1], ARGV[2], ARGV[3], ARGV[4])
+`)
+
+type ISessionStore interface {
+	Create(ctx context.Context, session *SessionModel, refreshToken string, ttl time.Duration) error
+	// Rotate привязывает к сессии новый refresh-токен
+	Rotate(ctx context.Context, ID uuid.UUID, refreshToken string, ttl time.Duration) error
+	// Consume погашает refresh-токен и возвращает его сессию. Для уже
+	// погашенного токена сессия удаляется, ее последний access-токен
+	// запрещается, и возвращается ErrRefreshTokenReused
+	Consume(ctx context.Context, refreshToken string, ttl time.Duration) (*SessionModel, error)
+	GetByRefreshToken(ctx context.Context, refreshToken string) (*SessionModel, error)
+	// SetAccessToken запоминает последний access-токен сессии, которой
+	// принадлежит refresh-токен
+	SetAccessToken(ctx context.Context, refreshToken, tokenID string, expiresAt time.Time) error
+	GetByID(ctx context.Context, ID uuid.UUID) (*SessionModel, error)
+	GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*SessionModel, error)
+	Delete(ctx context.Context, ID uuid.UUID) error
+	DeleteByReaderID(ctx context.Context, readerID uuid.UUID) error
+	// DeleteLegacyToken удаляет refresh-токен в старом формате "токен - идентификатор читателя"
+	DeleteLegacyToken(ctx context.Context, refreshToken string) error
+}
+
+type RedisStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisStore(client *redis.Client, logger *logrus.Entry) *RedisStore {
+	return &RedisStore{client: client, logger: logger}
+}
+
+func (s *RedisStore) Create(ctx context.Context, session *SessionModel, refreshToken string, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("creating session")
+
+	tokenHash := hashToken(refreshToken)
+	sessionKey := sessionKeyPrefix + session.ID.String()
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey,
+			readerIDField, session.ReaderID.String(),
+			userAgentField, session.UserAgent,
+			ipField, session.IP,
+			createdAtField, session.CreatedAt.Unix(),
+			lastUsedAtField, session.LastUsedAt.Unix(),
+			tokenHashField, tokenHash,
+		)
+		pipe.Expire(ctx, sessionKey, ttl)
+		pipe.Set(ctx, tokenKeyPrefix+tokenHash, session.ID.String(), ttl)
+		// индекс живет не дольше самой свежей сессии читателя
+		pipe.SAdd(ctx, readerSessionsKeyPrefix+session.ReaderID.String(), session.ID.String())
+		pipe.Expire(ctx, readerSessionsKeyPrefix+session.ReaderID.String(), ttl)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error creating session: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisStore) Rotate(ctx context.Context, ID uuid.UUID, refreshToken string, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("rotating session refresh token")
+
+	session, err := s.GetByID(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	tokenHash := hashToken(refreshToken)
+	sessionKey := sessionKeyPrefix + session.ID.String()
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey, lastUsedAtField, time.Now().Unix(), tokenHashField, tokenHash)
+		pipe.Expire(ctx, sessionKey, ttl)
+		pipe.Set(ctx, tokenKeyPrefix+tokenHash, session.ID.String(), ttl)
+		pipe.Expire(ctx, readerSessionsKeyPrefix+session.ReaderID.String(), ttl)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error rotating session refresh token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisStore) Consume(ctx context.Context, refreshToken string, ttl time.Duration) (*SessionModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	tokenHash := hashToken(refreshToken)
+
+	sessionID, err := s.client.GetDel(ctx, tokenKeyPrefix+tokenHash).Result()
+	if err == nil {
+		if err = s.client.Set(ctx, usedTokenKeyPrefix+tokenHash, sessionID, ttl).Err(); err != nil {
+			logger.Errorf("error marking refresh token as used: %v", err)
+			return nil, err
+		}
+
+		return s.getByRawID(ctx, sessionID)
+	}
+	if !errors.Is(err, redis.Nil) {
+		logger.Errorf("error consuming refresh token: %v", err)
+		return nil, err
+	}
+
+	sessionID, err = s.client.Get(ctx, usedTokenKeyPrefix+tokenHash).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionDoesNotExists
+	}
+	if err != nil {
+		logger.Errorf("error checking used refresh token: %v", err)
+		return nil, err
+	}
+
+	// токен украден или повторно отправлен клиентом: сессия завершается, ее
+	// последний access-токен запрещается вместе с удалением сессии
+	ID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.delete(ctx, ID, true); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrRefreshTokenReused
+}
+
+func (s *RedisStore) GetByRefreshToken(ctx context.Context, refreshToken string) (*SessionModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	sessionID, err := s.client.Get(ctx, tokenKeyPrefix+hashToken(refreshToken)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionDoesNotExists
+	}
+	if err != nil {
+		logger.Errorf("error getting session by refresh token: %v", err)
+		return nil, err
+	}
+
+	return s.getByRawID(ctx, sessionID)
+}
+
+func (s *RedisStore) SetAccessToken(ctx context.Context, refreshToken, tokenID string, expiresAt time.Time) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	sessionID, err := s.client.Get(ctx, tokenKeyPrefix+hashToken(refreshToken)).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionDoesNotExists
+	}
+	if err != nil {
+		logger.Errorf("error getting session by refresh token: %v", err)
+		return err
+	}
+
+	err = setAccessTokenScript.Run(ctx, s.client, []string{sessionKeyPrefix + sessionID},
+		accessTokenIDField, tokenID, accessTokenExpiresAtField, expiresAt.Unix()).Err()
+	if err != nil {
+		logger.Errorf("error saving session access token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisStore) GetByID(ctx context.Context, ID uuid.UUID) (*SessionModel, error) {
+	return s.getByRawID(ctx, ID.String())
+}
+
+func (s *RedisStore) GetByReaderID(ctx context.Context, readerID uuid.UUID) ([]*SessionModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	readerKey := readerSessionsKeyPrefix + readerID.String()
+
+	IDs, err := s.client.SMembers(ctx, readerKey).Result()
+	if err != nil {
+		logger.Errorf("error getting reader sessions: %v", err)
+		return nil, err
+	}
+
+	sessions := make([]*SessionModel, 0, len(IDs))
+	for _, ID := range IDs {
+		session, err := s.getByRawID(ctx, ID)
+		if errors.Is(err, ErrSessionDoesNotExists) {
+			s.client.SRem(ctx, readerKey, ID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(sessions) == 0 {
+		return nil, ErrSessionDoesNotExists
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, ID uuid.UUID) error {
+	return s.delete(ctx, ID, false)
+}
+
+// delete удаляет сессию; если denyAccessToken, последний access-токен сессии
+// запрещается в той же транзакции
+func (s *RedisStore) delete(ctx context.Context, ID uuid.UUID, denyAccessToken bool) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("deleting session")
+
+	sessionKey := sessionKeyPrefix + ID.String()
+
+	values, err := s.client.HMGet(ctx, sessionKey,
+		readerIDField, tokenHashField, accessTokenIDField, accessTokenExpiresAtField).Result()
+	if err != nil {
+		logger.Errorf("error getting session: %v", err)
+		return err
+	}
+
+	readerID, _ := values[0].(string)
+	tokenHash, _ := values[1].(string)
+	accessTokenID, _ := values[2].(string)
+	accessTokenExpiresAt, _ := values[3].(string)
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey)
+		if tokenHash != "" {
+			pipe.Del(ctx, tokenKeyPrefix+tokenHash)
+		}
+		if readerID != "" {
+			pipe.SRem(ctx, readerSessionsKeyPrefix+readerID, ID.String())
+		}
+		if denyAccessToken && accessTokenID != "" {
+			token.AddPipelined(ctx, pipe, accessTokenID, parseUnix(accessTokenExpiresAt))
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error deleting session: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisStore) DeleteByReaderID(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("deleting reader sessions")
+
+	IDs, err := s.client.SMembers(ctx, readerSessionsKeyPrefix+readerID.String()).Result()
+	if err != nil {
+		logger.Errorf("error getting reader sessions: %v", err)
+		return err
+	}
+
+	for _, ID := range IDs {
+		sessionID, err := uuid.Parse(ID)
+		if err != nil {
+			continue
+		}
+		if err = s.Delete(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+
+	return s.client.Del(ctx, readerSessionsKeyPrefix+readerID.String()).Err()
+}
+
+func (s *RedisStore) DeleteLegacyToken(ctx context.Context, refreshToken string) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, refreshToken).Err(); err != nil {
+		logger.Errorf("error deleting legacy refresh token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisStore) getByRawID(ctx context.Context, ID string) (*SessionModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	values, err := s.client.HGetAll(ctx, sessionKeyPrefix+ID).Result()
+	if err != nil {
+		logger.Errorf("error getting session: %v", err)
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, ErrSessionDoesNotExists
+	}
+
+	sessionID, err := uuid.Parse(ID)
+	if err != nil {
+		return nil, err
+	}
+	readerID, err := uuid.Parse(values[readerIDField])
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionModel{
+		ID:                   sessionID,
+		ReaderID:             readerID,
+		UserAgent:            values[userAgentField],
+		IP:                   values[ipField],
+		CreatedAt:            parseUnix(values[createdAtField]),
+		LastUsedAt:           parseUnix(values[lastUsedAtField]),
+		AccessTokenID:        values[accessTokenIDField],
+		AccessTokenExpiresAt: parseUnix(values[accessTokenExpiresAtField]),
+	}, nil
+}
+
+func parseUnix(value string) time.Time {
+	seconds, _ := strconv.ParseInt(value, 10, 64)
+
+	return time.Unix(seconds, 0)
+}
+
+func hashToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+const authPathPrefix = "/api/v1/auth/"
+
+type tokensOutput struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// teeWriter отдает ответ клиенту и сохраняет его копию
+type teeWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *teeWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// AccessTokenTracker запоминает в сессии идентификатор выданного ей
+// access-токена, чтобы завершение сессии с другого устройства запрещало и его
+type AccessTokenTracker struct {
+	sessionStore       ISessionStore
+	accessTokenRevoker IAccessTokenRevoker
+	logger             *logrus.Entry
+}
+
+func NewAccessTokenTracker(
+	sessionStore ISessionStore,
+	accessTokenRevoker IAccessTokenRevoker,
+	logger *logrus.Entry,
+) *AccessTokenTracker {
+	return &AccessTokenTracker{sessionStore: sessionStore, accessTokenRevoker: accessTokenRevoker, logger: logger}
+}
+
+// Wrap читает пару токенов из ответов на вход, обновление токенов и проверку
+// второго фактора. Эти обработчики находятся во внешнем модуле или отдают
+// отложенный ответ входа, поэтому обертка ставится вокруг всего роутера
+func (t *AccessTokenTracker) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, authPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tee := &teeWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(tee, r)
+
+		if tee.status != http.StatusOK {
+			return
+		}
+
+		var output tokensOutput
+		if err := json.Unmarshal(tee.body.Bytes(), &output); err != nil || output.AccessToken == "" || output.RefreshToken == "" {
+			return
+		}
+
+		t.track(r, &output)
+	})
+}
+
+func (t *AccessTokenTracker) track(r *http.Request, output *tokensOutput) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx, t.logger)
+
+	tokenID, expiresAt, err := t.accessTokenRevoker.TokenID(output.AccessToken)
+	if err != nil {
+		logger.Errorf("error getting access token id: %v", err)
+		return
+	}
+
+	if err = t.sessionStore.SetAccessToken(ctx, output.RefreshToken, tokenID, expiresAt); err != nil {
+		logger.Errorf("error tracking session access token: %v", err)
+	}
+}
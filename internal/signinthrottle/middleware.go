@@ -0,0 +1,97 @@
+package signinthrottle
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	signInPath       = "/api/v1/auth/sign-in"
+	maxSignInBodyLen = 1 << 20
+)
+
+type signInInput struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+// statusRecorder запоминает код ответа обработчика входа
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Wrap ограничивает запросы на вход. Обработчик входа находится во внешнем
+// модуле, поэтому обертка ставится вокруг всего роутера и по коду ответа
+// определяет, была ли попытка неудачной
+func (t *Throttle) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != signInPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSignInBodyLen))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r = r.WithContext(twofactor.WithChallengeFlag(r.Context()))
+
+		var input signInInput
+		_ = json.Unmarshal(body, &input)
+		phoneNumber := strings.TrimSpace(input.PhoneNumber)
+		ip := session.ClientIP(r)
+
+		ctx := r.Context()
+		retryAfter, err := t.Check(ctx, phoneNumber, ip)
+		if err != nil {
+			// при недоступности Redis вход не блокируется
+			logging.FromContext(ctx, t.logger).Errorf("error checking sign-in throttle: %v", err)
+		}
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "too many sign-in attempts, try again later")
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		switch {
+		// при выданном challenge вход завершится только после проверки кода,
+		// тогда счетчики и сбросит сервис двухфакторной аутентификации
+		case recorder.status == http.StatusOK && !twofactor.ChallengeIssued(ctx):
+			err = t.Success(ctx, phoneNumber)
+		case recorder.status == http.StatusConflict:
+			err = t.Failure(ctx, phoneNumber, ip)
+		// неизвестный номер засчитывается только по IP, иначе перебором
+		// несуществующих аккаунтов можно было бы заблокировать любой номер
+		case recorder.status == http.StatusNotFound:
+			err = t.Failure(ctx, "", ip)
+		}
+		if err != nil {
+			logging.FromContext(ctx, t.logger).Errorf("error updating sign-in throttle: %v", err)
+		}
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(dto.ErrorResponse{ErrorMsg: msg})
+}
@@ -0,0 +1,138 @@
+package signinthrottle
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	failuresKeyPrefix = "signin_throttle:failures:"
+	blockedKeyPrefix  = "signin_throttle:blocked:"
+
+	// AuditStreamKey - поток Redis с записями аудита о блокировках аккаунтов,
+	// читается командой XRANGE
+	AuditStreamKey    = "signin_throttle:audit"
+	auditStreamMaxLen = 10000
+)
+
+// failureScript засчитывает неудачную попытку и блокирует ключ в одной
+// операции: задержка считается по новому значению счетчика и только продлевает
+// текущую блокировку, поэтому параллельные неудачи не сокращают ее. Запись
+// аудита добавляется той же операцией ровно при достижении MaxFailures
+var failureScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+
+local freeAttempts, maxFailures = tonumber(ARGV[2]), tonumber(ARGV[3])
+local block = 0
+if failures >= maxFailures then
+	block = tonumber(ARGV[6])
+elseif failures > freeAttempts then
+	block = math.floor(math.min(tonumber(ARGV[4]) * 2 ^ (failures - freeAttempts - 1), tonumber(ARGV[5])))
+end
+if block > 0 and block > redis.call('PTTL', KEYS[2]) then
+	redis.call('SET', KEYS[2], 1, 'PX', block)
+end
+
+if failures == maxFailures and #ARGV > 6 then
+	redis.call('XADD', KEYS[3], 'MAXLEN', '~', ARGV[7], '*', unpack(ARGV, 8))
+end
+
+return failures
+`)
+
+// AuditRecord - запись аудита о блокировке входа в аккаунт
+type AuditRecord struct {
+	PhoneNumber string
+	IP          string
+}
+
+type IAttemptStore interface {
+	// BlockedFor возвращает, сколько еще ключ заблокирован, или 0
+	BlockedFor(ctx context.Context, key string) (time.Duration, error)
+	// RegisterFailure засчитывает неудачную попытку в пределах окна
+	// policy.FailureWindow, блокирует ключ по limits и возвращает число неудач.
+	// Если audit не nil, при блокировке ключа сохраняется запись аудита
+	RegisterFailure(ctx context.Context, key string, policy Policy, limits Limits, audit *AuditRecord) (int, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type RedisAttemptStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisAttemptStore(client *redis.Client, logger *logrus.Entry) IAttemptStore {
+	return &RedisAttemptStore{client: client, logger: logger}
+}
+
+func (s *RedisAttemptStore) BlockedFor(ctx context.Context, key string) (time.Duration, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	ttl, err := s.client.PTTL(ctx, blockedKeyPrefix+key).Result()
+	if err != nil {
+		logger.Errorf("error getting sign-in block: %v", err)
+		return 0, err
+	}
+
+	// отрицательный TTL означает, что блокировки нет
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (s *RedisAttemptStore) RegisterFailure(
+	ctx context.Context,
+	key string,
+	policy Policy,
+	limits Limits,
+	audit *AuditRecord,
+) (int, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	args := []any{
+		policy.FailureWindow.Milliseconds(),
+		limits.FreeAttempts,
+		limits.MaxFailures,
+		policy.BaseDelay.Milliseconds(),
+		policy.MaxDelay.Milliseconds(),
+		policy.LockoutDuration.Milliseconds(),
+	}
+	if audit != nil {
+		args = append(args, auditStreamMaxLen,
+			"event", "account_locked",
+			"phone_number", audit.PhoneNumber,
+			"ip", audit.IP,
+			"failures", limits.MaxFailures,
+			"locked_for", policy.LockoutDuration.String(),
+			"locked_at", time.Now().UTC().Format(time.RFC3339),
+		)
+	}
+
+	failures, err := failureScript.Run(ctx, s.client,
+		[]string{failuresKeyPrefix + key, blockedKeyPrefix + key, AuditStreamKey}, args...).Int()
+	if err != nil {
+		logger.Errorf("error registering sign-in failure: %v", err)
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+func (s *RedisAttemptStore) Reset(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, failuresKeyPrefix+key, blockedKeyPrefix+key).Err(); err != nil {
+		logger.Errorf("error resetting sign-in failures: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package signinthrottle
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	phoneKeyPrefix = "phone:"
+	ipKeyPrefix    = "ip:"
+)
+
+// Limits - пороги для одного ключа: первые FreeAttempts ошибок проходят без
+// задержки, дальше задержка удваивается, а после MaxFailures ключ блокируется
+type Limits struct {
+	FreeAttempts int
+	MaxFailures  int
+}
+
+type Policy struct {
+	FailureWindow   time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Phone           Limits
+	IP              Limits
+}
+
+// Throttle ограничивает попытки входа одновременно по номеру телефона и по
+// IP-адресу клиента. Счетчики хранятся в Redis, поэтому ограничения общие
+// для всех экземпляров приложения за балансировщиком
+type Throttle struct {
+	store  IAttemptStore
+	policy Policy
+	logger *logrus.Entry
+}
+
+func NewThrottle(store IAttemptStore, policy Policy, logger *logrus.Entry) *Throttle {
+	return &Throttle{store: store, policy: policy, logger: logger}
+}
+
+// Check возвращает время, через которое можно повторить попытку входа, или 0
+func (t *Throttle) Check(ctx context.Context, phoneNumber, ip string) (time.Duration, error) {
+	var retryAfter time.Duration
+
+	for _, key := range keys(phoneNumber, ip) {
+		blockedFor, err := t.store.BlockedFor(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		retryAfter = max(retryAfter, blockedFor)
+	}
+
+	return retryAfter, nil
+}
+
+// Failure засчитывает неудачную попытку входа. Пустой номер телефона
+// засчитывается только по IP
+func (t *Throttle) Failure(ctx context.Context, phoneNumber, ip string) error {
+	logger := logging.FromContext(ctx, t.logger)
+
+	if phoneNumber != "" {
+		audit := &AuditRecord{PhoneNumber: phoneNumber, IP: ip}
+		failures, err := t.store.RegisterFailure(ctx, phoneKeyPrefix+phoneNumber, t.policy, t.policy.Phone, audit)
+		if err != nil {
+			return err
+		}
+		if failures == t.policy.Phone.MaxFailures {
+			logger.WithFields(logrus.Fields{
+				"phone_number": phoneNumber,
+				"ip":           ip,
+				"failures":     failures,
+				"locked_for":   t.policy.LockoutDuration.String(),
+			}).Warn("sign-in locked for account after repeated failures")
+		}
+	}
+
+	failures, err := t.store.RegisterFailure(ctx, ipKeyPrefix+ip, t.policy, t.policy.IP, nil)
+	if err != nil {
+		return err
+	}
+	if failures == t.policy.IP.MaxFailures {
+		logger.WithFields(logrus.Fields{
+			"ip":         ip,
+			"failures":   failures,
+			"locked_for": t.policy.LockoutDuration.String(),
+		}).Warn("sign-in locked for ip after repeated failures")
+	}
+
+	return nil
+}
+
+// Success сбрасывает счетчик номера телефона. Счетчик IP не сбрасывается,
+// чтобы успешный вход в свой аккаунт не обнулял перебор чужих
+func (t *Throttle) Success(ctx context.Context, phoneNumber string) error {
+	if phoneNumber == "" {
+		return nil
+	}
+
+	return t.store.Reset(ctx, phoneKeyPrefix+phoneNumber)
+}
+
+func keys(phoneNumber, ip string) []string {
+	if phoneNumber == "" {
+		return []string{ipKeyPrefix + ip}
+	}
+
+	return []string{phoneKeyPrefix + phoneNumber, ipKeyPrefix + ip}
+}
@@ -0,0 +1,82 @@
+package similar
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"net/http"
+	"strconv"
+)
+
+type Handler struct {
+	similarBookService ISimilarBookService
+}
+
+func NewHandler(similarBookService ISimilarBookService) *Handler {
+	return &Handler{similarBookService: similarBookService}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.GET("/books/:id/similar", h.getSimilarBooks)
+	}
+}
+
+// @Summary Метод получения похожих книг
+// @Tags book
+// @ID getSimilarBooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор книги"
+// @Param page_number query string true "Номер страницы"
+// @Success 200 {array} dto.SimilarBookOutputDTO "Успешное получение похожих книг"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Похожие книги не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/books/{id}/similar [get]
+func (h *Handler) getSimilarBooks(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	pageNumber, err := strconv.Atoi(c.Query("page_number"))
+	if err != nil || pageNumber < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: "invalid page number"})
+		return
+	}
+
+	similarBooks, err := h.similarBookService.GetByBookID(c.Request.Context(), bookID,
+		SimilarBooksPageLimit, (pageNumber-1)*SimilarBooksPageLimit)
+	if err != nil && (errors.Is(err, errs.ErrBookDoesNotExists) || errors.Is(err, ErrSimilarBooksDoesNotExists)) {
+		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	similarBooksDTO := make([]*dto.SimilarBookOutputDTO, len(similarBooks))
+	for i, similarBook := range similarBooks {
+		similarBooksDTO[i] = &dto.SimilarBookOutputDTO{
+			ID:             similarBook.Book.ID,
+			Title:          similarBook.Book.Title,
+			Author:         similarBook.Book.Author,
+			Publisher:      similarBook.Book.Publisher,
+			CopiesNumber:   similarBook.Book.CopiesNumber,
+			Rarity:         similarBook.Book.Rarity,
+			Genre:          similarBook.Book.Genre,
+			PublishingYear: similarBook.Book.PublishingYear,
+			Language:       similarBook.Book.Language,
+			AgeLimit:       similarBook.Book.AgeLimit,
+			Score:          similarBook.Score,
+		}
+	}
+
+	c.JSON(http.StatusOK, similarBooksDTO)
+}
@@ -0,0 +1,215 @@
+package similar
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"strings"
+)
+
+const (
+	bookCollection          = "book"
+	reservationCollection   = "reservation"
+	coReservationCollection = "book_co_reservation"
+)
+
+type MongoRepo struct {
+	db     *mongo.Database
+	logger *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) ISimilarBookRepo {
+	return &MongoRepo{db: db, logger: logger}
+}
+
+// Refresh записывает таблицу стадией $out: коллекция заменяется атомарно,
+// индексы прежней коллекции сохраняются
+func (r *MongoRepo) Refresh(ctx context.Context) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("refreshing book co-reservations")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"reader_id": "$reader_id", "book_id": "$book_id"}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$_id.reader_id", "books": bson.M{"$push": "$_id.book_id"}}}},
+		{{Key: "$project", Value: bson.M{"book_id": "$books", "co_book_id": "$books"}}},
+		{{Key: "$unwind", Value: "$book_id"}},
+		{{Key: "$unwind", Value: "$co_book_id"}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$ne": bson.A{"$book_id", "$co_book_id"}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           bson.M{"book_id": "$book_id", "co_book_id": "$co_book_id"},
+			"readers_count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$_id.book_id",
+			"co_books": bson.M{"$topN": bson.M{
+				"n":      MaxCoReservedPerBook,
+				"sortBy": bson.D{{Key: "readers_count", Value: -1}, {Key: "_id.co_book_id", Value: 1}},
+				"output": bson.M{"co_book_id": "$_id.co_book_id", "readers_count": "$readers_count"},
+			}},
+		}}},
+		{{Key: "$unwind", Value: "$co_books"}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           0,
+			"book_id":       "$_id",
+			"co_book_id":    "$co_books.co_book_id",
+			"readers_count": "$co_books.readers_count",
+		}}},
+		{{Key: "$out", Value: coReservationCollection}},
+	}
+
+	cursor, err := r.db.Collection(reservationCollection).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		logger.Errorf("error refreshing book co-reservations: %v", err)
+		return err
+	}
+	if err = cursor.Close(ctx); err != nil {
+		logger.Errorf("error refreshing book co-reservations: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoRepo) GetByBook(ctx context.Context, book *models.BookModel, limit, offset int) ([]*ScoredBook, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting similar books")
+
+	bookID := toBinary(book.ID)
+	genres := Genres(book.Genre)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"book_id": bookID}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":   "$co_book_id",
+			"score": bson.M{"$multiply": bson.A{CoReservationWeight, "$readers_count"}},
+		}}},
+	}
+	if author := Normalize(book.Author); author != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll": bookCollection,
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"author": primitive.Regex{
+					Pattern: `^\s*` + regexp.QuoteMeta(author) + `\s*$`,
+					Options: "i",
+				}}},
+				bson.M{"$project": bson.M{"_id": 1, "score": bson.M{"$literal": SameAuthorWeight}}},
+			},
+		}}})
+	}
+	if len(genres) > 0 {
+		quoted := make([]string, len(genres))
+		for i, genre := range genres {
+			quoted[i] = regexp.QuoteMeta(genre)
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll": bookCollection,
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"genre": primitive.Regex{
+					Pattern: `(^|,)\s*(` + strings.Join(quoted, "|") + `)\s*(,|$)`,
+					Options: "i",
+				}}},
+				bson.M{"$project": bson.M{"_id": 1, "score": bson.M{"$multiply": bson.A{
+					SharedGenreWeight,
+					bson.M{"$size": bson.M{"$setIntersection": bson.A{
+						bson.M{"$map": bson.M{
+							"input": bson.M{"$split": bson.A{"$genre", ","}},
+							"in":    bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$$this"}}},
+						}},
+						genres,
+					}}},
+				}}}},
+			},
+		}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$_id", "score": bson.M{"$sum": "$score"}}}},
+		bson.D{{Key: "$match", Value: bson.M{"_id": bson.M{"$ne": bookID}}}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         bookCollection,
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "book",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$book"}},
+		bson.D{{Key: "$match", Value: bson.M{"book.deleted_at": nil}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$skip", Value: max(offset, 0)}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+
+	cursor, err := r.db.Collection(coReservationCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Errorf("error selecting similar books: %v", err)
+		return nil, err
+	}
+
+	var documents []scoredBookDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		logger.Errorf("error decoding similar books: %v", err)
+		return nil, err
+	}
+
+	similarBooks := make([]*ScoredBook, len(documents))
+	for i := range documents {
+		if similarBooks[i], err = documents[i].model(); err != nil {
+			logger.Errorf("error decoding book id: %v", err)
+			return nil, err
+		}
+	}
+
+	logger.Infof("found %d similar books", len(similarBooks))
+
+	return similarBooks, nil
+}
+
+// scoredBookDocument - документ коллекции book с оценкой похожести
+type scoredBookDocument struct {
+	Score float64 `bson:"score"`
+	Book  struct {
+		ID             primitive.Binary `bson:"_id"`
+		Title          string           `bson:"title"`
+		Author         string           `bson:"author"`
+		Publisher      string           `bson:"publisher"`
+		CopiesNumber   uint             `bson:"copies_number"`
+		Rarity         string           `bson:"rarity"`
+		Genre          string           `bson:"genre"`
+		PublishingYear uint             `bson:"publishing_year"`
+		Language       string           `bson:"language"`
+		AgeLimit       uint             `bson:"age_limit"`
+	} `bson:"book"`
+}
+
+func (d *scoredBookDocument) model() (*ScoredBook, error) {
+	ID, err := uuid.FromBytes(d.Book.ID.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScoredBook{
+		Book: &models.BookModel{
+			ID:             ID,
+			Title:          d.Book.Title,
+			Author:         d.Book.Author,
+			Publisher:      d.Book.Publisher,
+			CopiesNumber:   d.Book.CopiesNumber,
+			Rarity:         d.Book.Rarity,
+			Genre:          d.Book.Genre,
+			PublishingYear: d.Book.PublishingYear,
+			Language:       d.Book.Language,
+			AgeLimit:       d.Book.AgeLimit,
+		},
+		Score: d.Score,
+	}, nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
@@ -0,0 +1,173 @@
+package similar
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// refreshLockID - ключ pg_try_advisory_xact_lock пересчета таблицы: пока один
+// экземпляр ее пересчитывает, остальные пропускают свой пересчет
+const refreshLockID = 202601
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) ISimilarBookRepo {
+	return &PostgresRepo{db: db, logger: logger}
+}
+
+// Refresh заменяет таблицу в одной транзакции, поэтому чтения до фиксации
+// видят прежнюю таблицу целиком
+func (r *PostgresRepo) Refresh(ctx context.Context) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("refreshing book co-reservations")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.Errorf("error beginning transaction: %v", err)
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked bool
+	if err = tx.GetContext(ctx, &locked, `select pg_try_advisory_xact_lock($1)`, refreshLockID); err != nil {
+		logger.Errorf("error locking book co-reservations: %v", err)
+		return err
+	}
+	if !locked {
+		logger.Info("book co-reservations are being refreshed by another instance, skipping")
+		return nil
+	}
+
+	if _, err = tx.ExecContext(ctx, `delete from bs.book_co_reservation`); err != nil {
+		logger.Errorf("error clearing book co-reservations: %v", err)
+		return err
+	}
+
+	query := `with reserved as (
+			      select distinct reader_id, book_id from bs.reservation
+			  ), co_reserved as (
+			      select r1.book_id, r2.book_id as co_book_id, count(*) as readers_count,
+			             row_number() over (partition by r1.book_id order by count(*) desc, r2.book_id) as position
+			      from reserved r1
+			               join reserved r2 on r2.reader_id = r1.reader_id and r2.book_id <> r1.book_id
+			      group by r1.book_id, r2.book_id
+			  )
+			  insert into bs.book_co_reservation (book_id, co_book_id, readers_count)
+			  select book_id, co_book_id, readers_count
+			  from co_reserved
+			  where position <= $1`
+
+	result, err := tx.ExecContext(ctx, query, MaxCoReservedPerBook)
+	if err != nil {
+		logger.Errorf("error inserting book co-reservations: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.Errorf("error committing book co-reservations: %v", err)
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	logger.Infof("stored %d book co-reservations", rows)
+
+	return nil
+}
+
+func (r *PostgresRepo) GetByBook(ctx context.Context, book *models.BookModel, limit, offset int) ([]*ScoredBook, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting similar books")
+
+	// выражения автора и жанров совпадают с индексами book_author_idx и
+	// book_genres_idx из миграции 000006
+	query := `with candidate as (
+			      select co_book_id as id, $2::float8 * readers_count as score
+			      from bs.book_co_reservation
+			      where book_id = $1
+			      union all
+			      select id, $3::float8
+			      from bs.book
+			      where $4 <> '' and lower(trim(author)) = $4
+			      union all
+			      select id, $5::float8 * cardinality(array(
+			          select unnest(array_remove(regexp_split_to_array(lower(trim(genre)), '\s*,\s*'), ''))
+			          intersect
+			          select unnest($6::text[])))
+			      from bs.book
+			      where array_remove(regexp_split_to_array(lower(trim(genre)), '\s*,\s*'), '') && $6::text[]
+			  )
+			  select b.id, b.title, b.author, b.publisher, b.copies_number, b.rarity, b.genre,
+			         b.publishing_year, b.language, b.age_limit, sum(c.score) as score
+			  from candidate c
+			           join bs.book b on b.id = c.id
+			  where b.id <> $1 and b.deleted_at is null
+			  group by b.id
+			  order by score desc, b.id
+			  limit $7 offset $8`
+
+	var rows []scoredBookRow
+	err := r.db.SelectContext(ctx, &rows, query,
+		book.ID,
+		CoReservationWeight,
+		SameAuthorWeight,
+		Normalize(book.Author),
+		SharedGenreWeight,
+		pq.StringArray(Genres(book.Genre)),
+		limit,
+		max(offset, 0),
+	)
+	if err != nil {
+		logger.Errorf("error selecting similar books: %v", err)
+		return nil, err
+	}
+
+	similarBooks := make([]*ScoredBook, len(rows))
+	for i := range rows {
+		similarBooks[i] = rows[i].model()
+	}
+
+	logger.Infof("found %d similar books", len(similarBooks))
+
+	return similarBooks, nil
+}
+
+// scoredBookRow - строка bs.book с оценкой похожести
+type scoredBookRow struct {
+	ID             uuid.UUID `db:"id"`
+	Title          string    `db:"title"`
+	Author         string    `db:"author"`
+	Publisher      string    `db:"publisher"`
+	CopiesNumber   uint      `db:"copies_number"`
+	Rarity         string    `db:"rarity"`
+	Genre          string    `db:"genre"`
+	PublishingYear uint      `db:"publishing_year"`
+	Language       string    `db:"language"`
+	AgeLimit       uint      `db:"age_limit"`
+	Score          float64   `db:"score"`
+}
+
+func (r *scoredBookRow) model() *ScoredBook {
+	return &ScoredBook{
+		Book: &models.BookModel{
+			ID:             r.ID,
+			Title:          r.Title,
+			Author:         r.Author,
+			Publisher:      r.Publisher,
+			CopiesNumber:   r.CopiesNumber,
+			Rarity:         r.Rarity,
+			Genre:          r.Genre,
+			PublishingYear: r.PublishingYear,
+			Language:       r.Language,
+			AgeLimit:       r.AgeLimit,
+		},
+		Score: r.Score,
+	}
+}
@@ -0,0 +1,21 @@
+package similar
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+)
+
+// ISimilarBookRepo хранит таблицу совместных бронирований ("читатели этой
+// книги также брали") в БД и ранжирует по ней похожие книги. Таблица общая
+// для всех экземпляров приложения, поэтому они отвечают одинаково
+type ISimilarBookRepo interface {
+	// Refresh пересчитывает таблицу совместных бронирований по всем
+	// бронированиям; для каждой книги хранится не больше MaxCoReservedPerBook
+	// книг, которые брали те же читатели
+	Refresh(ctx context.Context) error
+	// GetByBook возвращает страницу книг, похожих на book, по убыванию оценки:
+	// совместные бронирования из таблицы, тот же автор и общие жанры. Удаленные
+	// книги и сама book не возвращаются; если похожих книг нет, возвращается
+	// пустой срез
+	GetByBook(ctx context.Context, book *models.BookModel, limit, offset int) ([]*ScoredBook, error)
+}
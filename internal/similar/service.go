@@ -0,0 +1,128 @@
+package similar
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	SimilarBooksPageLimit = 10
+	RefreshInterval       = time.Hour
+
+	MaxCoReservedPerBook = 100
+
+	CoReservationWeight = 3.0
+	SameAuthorWeight    = 2.0
+	SharedGenreWeight   = 0.5
+)
+
+var ErrSimilarBooksDoesNotExists = errors.New("similar books does not exists")
+
+type ScoredBook struct {
+	Book  *models.BookModel
+	Score float64
+}
+
+type ISimilarBookService interface {
+	GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]*ScoredBook, error)
+}
+
+type SimilarBookService struct {
+	bookRepo        intfRepo.IBookRepo
+	similarBookRepo ISimilarBookRepo
+	logger          *logrus.Entry
+}
+
+func NewSimilarBookService(
+	bookRepo intfRepo.IBookRepo,
+	similarBookRepo ISimilarBookRepo,
+	logger *logrus.Entry,
+) *SimilarBookService {
+	return &SimilarBookService{
+		bookRepo:        bookRepo,
+		similarBookRepo: similarBookRepo,
+		logger:          logger,
+	}
+}
+
+// Run пересчитывает таблицу совместных бронирований с заданным интервалом
+// до отмены контекста
+func (s *SimilarBookService) Run(ctx context.Context, interval time.Duration) {
+	s.refresh(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *SimilarBookService) refresh(ctx context.Context) {
+	s.logger.Info("refreshing book co-reservations")
+
+	if err := s.similarBookRepo.Refresh(ctx); err != nil {
+		s.logger.Errorf("error refreshing book co-reservations: %v", err)
+		return
+	}
+
+	s.logger.Info("book co-reservations refreshed")
+}
+
+// GetByBookID возвращает страницу книг, похожих на книгу с заданным
+// идентификатором. Совместные бронирования берутся из таблицы, поэтому
+// бронирования после ее пересчета учитываются со следующим пересчетом
+func (s *SimilarBookService) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) ([]*ScoredBook, error) {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("attempting to get similar books")
+
+	book, err := s.bookRepo.GetByID(ctx, bookID)
+	if err != nil {
+		logger.Errorf("error getting book: %v", err)
+		return nil, err
+	}
+
+	similarBooks, err := s.similarBookRepo.GetByBook(ctx, book, limit, offset)
+	if err != nil {
+		logger.Errorf("error getting similar books: %v", err)
+		return nil, err
+	}
+	if len(similarBooks) == 0 {
+		logger.Warn("similar books not found")
+		return nil, ErrSimilarBooksDoesNotExists
+	}
+
+	logger.Info("similar books successfully retrieved")
+
+	return similarBooks, nil
+}
+
+// Genres возвращает жанры книги: жанры в поле перечисляются через запятую и
+// сравниваются без учета регистра и пробелов по краям
+func Genres(genre string) []string {
+	var genres []string
+	for _, g := range strings.Split(genre, ",") {
+		if g = Normalize(g); g != "" {
+			genres = append(genres, g)
+		}
+	}
+
+	return genres
+}
+
+// Normalize приводит автора или жанр к виду, в котором они сравниваются
+func Normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
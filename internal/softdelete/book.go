@@ -0,0 +1,74 @@
+package softdelete
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+)
+
+// BookRepo заменяет удаление книги мягким: бронирования и отзывы продолжают
+// ссылаться на существующую книгу. Удаленные книги не попадают в поиск и не
+// находятся по названию, но читаются по идентификатору для истории и возврата
+// экземпляра. При бронировании через ReservationService удаленная книга не
+// находится и по идентификатору.
+// Поиск выполняет IDeletedBookRepo, исключая удаленные книги в самом запросе.
+// Удаление с If-Match проверяет и увеличивает версию книги
+type BookRepo struct {
+	intfRepo.IBookRepo
+	deleted  IDeletedBookRepo
+	versions optimistic.IVersionRepo
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, deleted IDeletedBookRepo, versions optimistic.IVersionRepo) intfRepo.IBookRepo {
+	return &BookRepo{IBookRepo: next, deleted: deleted, versions: versions}
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (*models.BookModel, error) {
+	book, err := r.IBookRepo.GetByID(ctx, ID)
+	if err != nil || book == nil || !reserving(ctx) {
+		return book, err
+	}
+
+	deleted, err := r.deleted.GetDeleted(ctx, []uuid.UUID{book.ID})
+	if err != nil {
+		return nil, err
+	}
+	if deleted[book.ID] {
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	return book, nil
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (*models.BookModel, error) {
+	book, err := r.IBookRepo.GetByTitle(ctx, title)
+	if err != nil || book == nil {
+		return book, err
+	}
+
+	deleted, err := r.deleted.GetDeleted(ctx, []uuid.UUID{book.ID})
+	if err != nil {
+		return nil, err
+	}
+	if deleted[book.ID] {
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	return book, nil
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	if err := optimistic.CheckExpected(ctx, r.versions, optimistic.EntityBook, ID); err != nil {
+		return err
+	}
+
+	return r.deleted.Delete(ctx, ID)
+}
+
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	return r.deleted.GetByParams(ctx, params)
+}
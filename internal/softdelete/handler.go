@@ -0,0 +1,115 @@
+package softdelete
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	deletedBookService IDeletedBookService
+	tokenManager       auth.ITokenManager
+	restoreRoles       []string
+}
+
+func NewHandler(deletedBookService IDeletedBookService, tokenManager auth.ITokenManager, restoreRoles []string) *Handler {
+	return &Handler{deletedBookService: deletedBookService, tokenManager: tokenManager, restoreRoles: restoreRoles}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.POST("/books/:id/restore",
+			middleware.Authenticate(h.tokenManager), middleware.RequireRole(h.restoreRoles...), h.restoreBook)
+	}
+}
+
+// @Summary Метод восстановления удаленной книги
+// @Security ApiKeyAuth
+// @Tags book
+// @ID restoreBook
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор книги"
+// @Success 204 "Успешное восстановление книги"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Удаленная книга не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/books/{id}/restore [post]
+func (h *Handler) restoreBook(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	err = h.deletedBookService.Restore(c.Request.Context(), bookID)
+	if err != nil && errors.Is(err, ErrDeletedBookDoesNotExists) {
+		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+type ReaderHandler struct {
+	deletedReaderService IDeletedReaderService
+	tokenManager         auth.ITokenManager
+	restoreRoles         []string
+}
+
+func NewReaderHandler(deletedReaderService IDeletedReaderService, tokenManager auth.ITokenManager, restoreRoles []string) *ReaderHandler {
+	return &ReaderHandler{deletedReaderService: deletedReaderService, tokenManager: tokenManager, restoreRoles: restoreRoles}
+}
+
+func (h *ReaderHandler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		api.POST("/readers/:id/restore",
+			middleware.Authenticate(h.tokenManager), middleware.RequireRole(h.restoreRoles...), h.restoreReader)
+	}
+}
+
+// @Summary Метод восстановления удаленного аккаунта читателя
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID restoreReaderAccount
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Success 204 "Успешное восстановление аккаунта"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} dto.ErrorResponse "Удаленный читатель не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/restore [post]
+func (h *ReaderHandler) restoreReader(c *gin.Context) {
+	readerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	err = h.deletedReaderService.Restore(c.Request.Context(), readerID)
+	if err != nil && errors.Is(err, ErrDeletedReaderDoesNotExists) {
+		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
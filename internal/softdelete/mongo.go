@@ -0,0 +1,383 @@
+package softdelete
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"time"
+)
+
+const (
+	bookCollection          = "book"
+	readerCollection        = "reader"
+	ratingCollection        = "rating"
+	reservationCollection   = "reservation"
+	favoriteBooksCollection = "favorite_books"
+	readingListCollection   = "reading_list"
+)
+
+type MongoRepo struct {
+	db     *mongo.Database
+	logger *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) IDeletedBookRepo {
+	return &MongoRepo{db: db, logger: logger}
+}
+
+func (r *MongoRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting book")
+
+	result, err := r.db.Collection(bookCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(ID), "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+	if err != nil {
+		logger.Errorf("error soft deleting book: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("book not found")
+		return errs.ErrBookDoesNotExists
+	}
+
+	logger.Info("successfully soft deleted book")
+
+	return nil
+}
+
+func (r *MongoRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring book")
+
+	result, err := r.db.Collection(bookCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(ID), "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		logger.Errorf("error restoring book: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("deleted book not found")
+		return ErrDeletedBookDoesNotExists
+	}
+
+	logger.Info("successfully restored book")
+
+	return nil
+}
+
+func (r *MongoRepo) GetDeleted(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	if len(IDs) == 0 {
+		return map[uuid.UUID]bool{}, nil
+	}
+
+	binaryIDs := make(bson.A, len(IDs))
+	for i, ID := range IDs {
+		binaryIDs[i] = toBinary(ID)
+	}
+
+	deletedIDs, err := findIDs(ctx, r.db.Collection(bookCollection), bson.M{"_id": bson.M{"$in": binaryIDs}, "deleted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		logger.Errorf("error selecting deleted books: %v", err)
+		return nil, err
+	}
+
+	deleted := make(map[uuid.UUID]bool, len(deletedIDs))
+	for _, ID := range deletedIDs {
+		deleted[ID] = true
+	}
+
+	return deleted, nil
+}
+
+func (r *MongoRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting available books by params")
+
+	// строковые параметры ищутся подстрокой без учета регистра, числовые -
+	// точным совпадением; пустые и нулевые параметры не учитываются
+	filter := bson.M{"deleted_at": nil}
+	for field, value := range map[string]string{
+		"title":     params.Title,
+		"author":    params.Author,
+		"publisher": params.Publisher,
+		"rarity":    params.Rarity,
+		"genre":     params.Genre,
+		"language":  params.Language,
+	} {
+		if value != "" {
+			filter[field] = primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+		}
+	}
+	for field, value := range map[string]uint{
+		"copies_number":   params.CopiesNumber,
+		"publishing_year": params.PublishingYear,
+		"age_limit":       params.AgeLimit,
+	} {
+		if value != 0 {
+			filter[field] = value
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(max(params.Offset, 0))).
+		SetLimit(int64(params.Limit))
+
+	cursor, err := r.db.Collection(bookCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Errorf("error selecting available books by params: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []bookDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		logger.Errorf("error decoding available books: %v", err)
+		return nil, err
+	}
+	if len(documents) == 0 {
+		logger.Warn("books not found")
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(documents))
+	for i := range documents {
+		if books[i], err = documents[i].model(); err != nil {
+			logger.Errorf("error decoding book id: %v", err)
+			return nil, err
+		}
+	}
+
+	return books, nil
+}
+
+func (r *MongoRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	expiredIDs, err := findIDs(ctx, r.db.Collection(bookCollection), bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		logger.Errorf("error selecting purgeable books: %v", err)
+		return nil, err
+	}
+
+	IDs := make([]uuid.UUID, 0, len(expiredIDs))
+	for _, ID := range expiredIDs {
+		count, err := r.db.Collection(reservationCollection).CountDocuments(ctx,
+			bson.M{"book_id": toBinary(ID), "state": bson.M{"$ne": impl.ReservationClosed}},
+			options.Count().SetLimit(1),
+		)
+		if err != nil {
+			logger.Errorf("error counting book reservations: %v", err)
+			return nil, err
+		}
+		if count == 0 {
+			IDs = append(IDs, ID)
+		}
+	}
+
+	return IDs, nil
+}
+
+func (r *MongoRepo) Purge(ctx context.Context, ID uuid.UUID, before time.Time) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("purging book")
+
+	result, err := r.db.Collection(bookCollection).DeleteOne(ctx,
+		bson.M{"_id": toBinary(ID), "deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		logger.Errorf("error purging book: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		logger.Info("book was restored, skipping it")
+		return nil
+	}
+
+	filter := bson.M{"book_id": toBinary(ID)}
+	for _, name := range []string{ratingCollection, reservationCollection, favoriteBooksCollection} {
+		if _, err = r.db.Collection(name).DeleteMany(ctx, filter); err != nil {
+			logger.Errorf("error purging book data: %v", err)
+			return err
+		}
+	}
+
+	_, err = r.db.Collection(readingListCollection).UpdateMany(ctx,
+		bson.M{"book_ids": ID.String()},
+		bson.M{"$pull": bson.M{"book_ids": ID.String()}},
+	)
+	if err != nil {
+		logger.Errorf("error purging book data: %v", err)
+		return err
+	}
+
+	logger.Info("successfully purged book")
+
+	return nil
+}
+
+type MongoReaderRepo struct {
+	db     *mongo.Database
+	logger *logrus.Entry
+}
+
+func NewMongoReaderRepo(db *mongo.Database, logger *logrus.Entry) IDeletedReaderRepo {
+	return &MongoReaderRepo{db: db, logger: logger}
+}
+
+func (r *MongoReaderRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting reader")
+
+	result, err := r.db.Collection(readerCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(ID), "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+	if err != nil {
+		logger.Errorf("error soft deleting reader: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("reader not found")
+		return errs.ErrReaderDoesNotExists
+	}
+
+	logger.Info("successfully soft deleted reader")
+
+	return nil
+}
+
+func (r *MongoReaderRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring reader")
+
+	result, err := r.db.Collection(readerCollection).UpdateOne(ctx,
+		bson.M{"_id": toBinary(ID), "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		logger.Errorf("error restoring reader: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		logger.Warn("deleted reader not found")
+		return ErrDeletedReaderDoesNotExists
+	}
+
+	logger.Info("successfully restored reader")
+
+	return nil
+}
+
+func (r *MongoReaderRepo) IsDeleted(ctx context.Context, ID uuid.UUID) (bool, error) {
+	return r.exists(ctx, bson.M{"_id": toBinary(ID), "deleted_at": bson.M{"$ne": nil}})
+}
+
+func (r *MongoReaderRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	IDs, err := findIDs(ctx, r.db.Collection(readerCollection), bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		logger.Errorf("error selecting purgeable readers: %v", err)
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+// IsPurgeable не блокирует документ: восстановление, пришедшее во время
+// окончательного удаления, конфликтует с транзакцией по записи, и она откатывается
+func (r *MongoReaderRepo) IsPurgeable(ctx context.Context, ID uuid.UUID, before time.Time) (bool, error) {
+	return r.exists(ctx, bson.M{"_id": toBinary(ID), "deleted_at": bson.M{"$lt": before}})
+}
+
+func (r *MongoReaderRepo) exists(ctx context.Context, filter bson.M) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	count, err := r.db.Collection(readerCollection).CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		logger.Errorf("error checking deleted reader: %v", err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func findIDs(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]uuid.UUID, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []struct {
+		ID primitive.Binary `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	IDs := make([]uuid.UUID, 0, len(documents))
+	for _, document := range documents {
+		ID, err := uuid.FromBytes(document.ID.Data)
+		if err != nil {
+			return nil, err
+		}
+		IDs = append(IDs, ID)
+	}
+
+	return IDs, nil
+}
+
+func toBinary(ID uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: ID[:]}
+}
+
+// bookDocument - документ коллекции book
+type bookDocument struct {
+	ID             primitive.Binary `bson:"_id"`
+	Title          string           `bson:"title"`
+	Author         string           `bson:"author"`
+	Publisher      string           `bson:"publisher"`
+	CopiesNumber   uint             `bson:"copies_number"`
+	Rarity         string           `bson:"rarity"`
+	Genre          string           `bson:"genre"`
+	PublishingYear uint             `bson:"publishing_year"`
+	Language       string           `bson:"language"`
+	AgeLimit       uint             `bson:"age_limit"`
+}
+
+func (d *bookDocument) model() (*models.BookModel, error) {
+	ID, err := uuid.FromBytes(d.ID.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BookModel{
+		ID:             ID,
+		Title:          d.Title,
+		Author:         d.Author,
+		Publisher:      d.Publisher,
+		CopiesNumber:   d.CopiesNumber,
+		Rarity:         d.Rarity,
+		Genre:          d.Genre,
+		PublishingYear: d.PublishingYear,
+		Language:       d.Language,
+		AgeLimit:       d.AgeLimit,
+	}, nil
+}
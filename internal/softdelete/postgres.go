@@ -0,0 +1,372 @@
+package softdelete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) IDeletedBookRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting book")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.book set deleted_at = now() where id = $1 and deleted_at is null`
+
+	if err := execOne(ctx, tr, query, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn("book not found")
+			return errs.ErrBookDoesNotExists
+		}
+		logger.Errorf("error soft deleting book: %v", err)
+		return err
+	}
+
+	logger.Info("successfully soft deleted book")
+
+	return nil
+}
+
+func (r *PostgresRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring book")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.book set deleted_at = null where id = $1 and deleted_at is not null`
+
+	if err := execOne(ctx, tr, query, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn("deleted book not found")
+			return ErrDeletedBookDoesNotExists
+		}
+		logger.Errorf("error restoring book: %v", err)
+		return err
+	}
+
+	logger.Info("successfully restored book")
+
+	return nil
+}
+
+func (r *PostgresRepo) GetDeleted(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	if len(IDs) == 0 {
+		return map[uuid.UUID]bool{}, nil
+	}
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select id from bs.book where id = any($1::uuid[]) and deleted_at is not null`
+
+	var deletedIDs []uuid.UUID
+	if err := tr.SelectContext(ctx, &deletedIDs, query, pq.StringArray(toStrings(IDs))); err != nil {
+		logger.Errorf("error selecting deleted books: %v", err)
+		return nil, err
+	}
+
+	deleted := make(map[uuid.UUID]bool, len(deletedIDs))
+	for _, ID := range deletedIDs {
+		deleted[ID] = true
+	}
+
+	return deleted, nil
+}
+
+func (r *PostgresRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting available books by params")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	// строковые параметры ищутся подстрокой без учета регистра, числовые -
+	// точным совпадением; пустые и нулевые параметры не учитываются
+	query := `select id, title, author, publisher, copies_number, rarity, genre, publishing_year, language, age_limit
+			  from bs.book
+			  where deleted_at is null
+			    and ($1 = '' or title ilike '%' || $1 || '%')
+			    and ($2 = '' or author ilike '%' || $2 || '%')
+			    and ($3 = '' or publisher ilike '%' || $3 || '%')
+			    and ($4 = 0 or copies_number = $4)
+			    and ($5 = '' or rarity ilike '%' || $5 || '%')
+			    and ($6 = '' or genre ilike '%' || $6 || '%')
+			    and ($7 = 0 or publishing_year = $7)
+			    and ($8 = '' or language ilike '%' || $8 || '%')
+			    and ($9 = 0 or age_limit = $9)
+			  order by title, id
+			  limit nullif($10, 0) offset $11`
+
+	var rows []bookRow
+	err := tr.SelectContext(ctx, &rows, query,
+		params.Title,
+		params.Author,
+		params.Publisher,
+		params.CopiesNumber,
+		params.Rarity,
+		params.Genre,
+		params.PublishingYear,
+		params.Language,
+		params.AgeLimit,
+		params.Limit,
+		max(params.Offset, 0),
+	)
+	if err != nil {
+		logger.Errorf("error selecting available books by params: %v", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		logger.Warn("books not found")
+		return nil, errs.ErrBookDoesNotExists
+	}
+
+	books := make([]*models.BookModel, len(rows))
+	for i := range rows {
+		books[i] = rows[i].model()
+	}
+
+	return books, nil
+}
+
+func (r *PostgresRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select b.id
+			  from bs.book b
+			  where b.deleted_at < $1 and not exists (
+			      select 1 from bs.reservation r where r.book_id = b.id and r.state <> $2
+			  )`
+
+	var IDs []uuid.UUID
+	if err := tr.SelectContext(ctx, &IDs, query, before, impl.ReservationClosed); err != nil {
+		logger.Errorf("error selecting purgeable books: %v", err)
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+func (r *PostgresRepo) Purge(ctx context.Context, ID uuid.UUID, before time.Time) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("purging book")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	// строка книги блокируется до конца транзакции, чтобы ее не восстановили
+	// между проверкой и удалением
+	var lockedID uuid.UUID
+	err := tr.GetContext(ctx, &lockedID,
+		`select id from bs.book where id = $1 and deleted_at < $2 for update`, ID, before)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Info("book was restored, skipping it")
+		return nil
+	}
+	if err != nil {
+		logger.Errorf("error locking book: %v", err)
+		return err
+	}
+
+	// списки чтения удаляются каскадно вместе с книгой
+	queries := []string{
+		`delete from bs.rating where book_id = $1`,
+		`delete from bs.reservation where book_id = $1`,
+		`delete from bs.favorite_books where book_id = $1`,
+		`delete from bs.book where id = $1`,
+	}
+	for _, query := range queries {
+		if _, err = tr.ExecContext(ctx, query, ID); err != nil {
+			logger.Errorf("error purging book: %v", err)
+			return err
+		}
+	}
+
+	logger.Info("successfully purged book")
+
+	return nil
+}
+
+type PostgresReaderRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresReaderRepo(db *sqlx.DB, logger *logrus.Entry) IDeletedReaderRepo {
+	return &PostgresReaderRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+func (r *PostgresReaderRepo) Delete(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("soft deleting reader")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader set deleted_at = now() where id = $1 and deleted_at is null`
+
+	if err := execOne(ctx, tr, query, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn("reader not found")
+			return errs.ErrReaderDoesNotExists
+		}
+		logger.Errorf("error soft deleting reader: %v", err)
+		return err
+	}
+
+	logger.Info("successfully soft deleted reader")
+
+	return nil
+}
+
+func (r *PostgresReaderRepo) Restore(ctx context.Context, ID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("restoring reader")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader set deleted_at = null where id = $1 and deleted_at is not null`
+
+	if err := execOne(ctx, tr, query, ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn("deleted reader not found")
+			return ErrDeletedReaderDoesNotExists
+		}
+		logger.Errorf("error restoring reader: %v", err)
+		return err
+	}
+
+	logger.Info("successfully restored reader")
+
+	return nil
+}
+
+func (r *PostgresReaderRepo) IsDeleted(ctx context.Context, ID uuid.UUID) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select exists(select 1 from bs.reader where id = $1 and deleted_at is not null)`
+
+	var deleted bool
+	if err := tr.GetContext(ctx, &deleted, query, ID); err != nil {
+		logger.Errorf("error checking deleted reader: %v", err)
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+func (r *PostgresReaderRepo) GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select id from bs.reader where deleted_at < $1`
+
+	var IDs []uuid.UUID
+	if err := tr.SelectContext(ctx, &IDs, query, before); err != nil {
+		logger.Errorf("error selecting purgeable readers: %v", err)
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+func (r *PostgresReaderRepo) IsPurgeable(ctx context.Context, ID uuid.UUID, before time.Time) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select id from bs.reader where id = $1 and deleted_at < $2 for update`
+
+	var lockedID uuid.UUID
+	err := tr.GetContext(ctx, &lockedID, query, ID, before)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Errorf("error locking reader: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// execOne выполняет изменение одной строки; если строк не нашлось, возвращает sql.ErrNoRows
+func execOne(ctx context.Context, tr trmsqlx.Tr, query string, args ...any) error {
+	result, err := tr.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func toStrings(IDs []uuid.UUID) []string {
+	strs := make([]string, len(IDs))
+	for i, ID := range IDs {
+		strs[i] = ID.String()
+	}
+
+	return strs
+}
+
+// bookRow - строка bs.book; модель сервисов не содержит тегов db
+type bookRow struct {
+	ID             uuid.UUID `db:"id"`
+	Title          string    `db:"title"`
+	Author         string    `db:"author"`
+	Publisher      string    `db:"publisher"`
+	CopiesNumber   uint      `db:"copies_number"`
+	Rarity         string    `db:"rarity"`
+	Genre          string    `db:"genre"`
+	PublishingYear uint      `db:"publishing_year"`
+	Language       string    `db:"language"`
+	AgeLimit       uint      `db:"age_limit"`
+}
+
+func (r *bookRow) model() *models.BookModel {
+	return &models.BookModel{
+		ID:             r.ID,
+		Title:          r.Title,
+		Author:         r.Author,
+		Publisher:      r.Publisher,
+		CopiesNumber:   r.CopiesNumber,
+		Rarity:         r.Rarity,
+		Genre:          r.Genre,
+		PublishingYear: r.PublishingYear,
+		Language:       r.Language,
+		AgeLimit:       r.AgeLimit,
+	}
+}
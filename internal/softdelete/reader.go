@@ -0,0 +1,73 @@
+package softdelete
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"time"
+)
+
+// AccountRepo заменяет удаление аккаунта мягким: до окончательного удаления
+// DeletedReaderService.Purge администратор может восстановить читателя вместе
+// с его билетом, бронированиями и отзывами
+type AccountRepo struct {
+	account.IAccountRepo
+	deleted IDeletedReaderRepo
+}
+
+func NewAccountRepo(next account.IAccountRepo, deleted IDeletedReaderRepo) account.IAccountRepo {
+	return &AccountRepo{IAccountRepo: next, deleted: deleted}
+}
+
+func (r *AccountRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	return r.deleted.Delete(ctx, readerID)
+}
+
+// ReaderRepo не дает удаленному читателю войти: refresh-токен для него не
+// сохраняется и не находится, поэтому вход и обновление токенов завершаются
+// errs.ErrReaderDoesNotExists. По идентификатору и номеру телефона удаленный
+// читатель находится: история остается, а номер занят до окончательного удаления
+type ReaderRepo struct {
+	intfRepo.IReaderRepo
+	deleted IDeletedReaderRepo
+}
+
+func NewReaderRepo(next intfRepo.IReaderRepo, deleted IDeletedReaderRepo) intfRepo.IReaderRepo {
+	return &ReaderRepo{IReaderRepo: next, deleted: deleted}
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, ID uuid.UUID, token string, ttl time.Duration) error {
+	if err := r.checkNotDeleted(ctx, ID); err != nil {
+		return err
+	}
+
+	return r.IReaderRepo.SaveRefreshToken(ctx, ID, token, ttl)
+}
+
+func (r *ReaderRepo) GetByRefreshToken(ctx context.Context, token string) (*models.ReaderModel, error) {
+	reader, err := r.IReaderRepo.GetByRefreshToken(ctx, token)
+	if err != nil || reader == nil {
+		return reader, err
+	}
+
+	if err = r.checkNotDeleted(ctx, reader.ID); err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (r *ReaderRepo) checkNotDeleted(ctx context.Context, ID uuid.UUID) error {
+	deleted, err := r.deleted.IsDeleted(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return errs.ErrReaderDoesNotExists
+	}
+
+	return nil
+}
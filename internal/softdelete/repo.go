@@ -0,0 +1,52 @@
+package softdelete
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"time"
+)
+
+var (
+	ErrDeletedBookDoesNotExists   = errors.New("deleted book does not exists")
+	ErrDeletedReaderDoesNotExists = errors.New("deleted reader does not exists")
+)
+
+// IDeletedBookRepo хранит отметки об удалении книг. Удаленная книга остается
+// в БД, пока ее не удалит окончательно Purge
+type IDeletedBookRepo interface {
+	// Delete отмечает книгу удаленной
+	Delete(ctx context.Context, ID uuid.UUID) error
+	// Restore снимает отметку об удалении
+	Restore(ctx context.Context, ID uuid.UUID) error
+	// GetDeleted возвращает удаленные книги среди IDs
+	GetDeleted(ctx context.Context, IDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	// GetByParams ищет книги так же, как репозиторий книг, но без удаленных:
+	// они исключаются в запросе до limit и offset
+	GetByParams(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error)
+	// GetPurgeable возвращает книги, удаленные раньше before, без незакрытых бронирований
+	GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	// Purge окончательно удаляет книгу, удаленную раньше before, вместе с ее
+	// отзывами, бронированиями и вхождениями в избранное и списки чтения.
+	// Если книгу успели восстановить, ничего не делает
+	Purge(ctx context.Context, ID uuid.UUID, before time.Time) error
+}
+
+// IDeletedReaderRepo хранит отметки об удалении читателей. Удаленный читатель
+// остается в БД, пока его данные не удалит окончательно IAccountRepo.Delete
+type IDeletedReaderRepo interface {
+	// Delete отмечает читателя удаленным
+	Delete(ctx context.Context, ID uuid.UUID) error
+	// Restore снимает отметку об удалении
+	Restore(ctx context.Context, ID uuid.UUID) error
+	// IsDeleted сообщает, удален ли читатель
+	IsDeleted(ctx context.Context, ID uuid.UUID) (bool, error)
+	// GetPurgeable возвращает читателей, удаленных раньше before
+	GetPurgeable(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	// IsPurgeable сообщает, удален ли читатель раньше before. В транзакции
+	// Postgres строка читателя блокируется до ее конца, чтобы читателя не
+	// восстановили до окончательного удаления
+	IsPurgeable(ctx context.Context, ID uuid.UUID, before time.Time) (bool, error)
+}
@@ -0,0 +1,29 @@
+package softdelete
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/intf"
+)
+
+type reservingKey struct{}
+
+func reserving(ctx context.Context) bool {
+	_, ok := ctx.Value(reservingKey{}).(struct{})
+	return ok
+}
+
+// ReservationService отмечает контекст бронирования книги: в нем BookRepo не
+// находит удаленную книгу, и бронь не создается с errs.ErrBookDoesNotExists.
+// Возврат и продление брони читают удаленную книгу как есть
+type ReservationService struct {
+	intf.IReservationService
+}
+
+func NewReservationService(next intf.IReservationService) intf.IReservationService {
+	return &ReservationService{IReservationService: next}
+}
+
+func (s *ReservationService) Create(ctx context.Context, readerID, bookID uuid.UUID) error {
+	return s.IReservationService.Create(context.WithValue(ctx, reservingKey{}, struct{}{}), readerID, bookID)
+}
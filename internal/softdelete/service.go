@@ -0,0 +1,220 @@
+package softdelete
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// OnChange вызывается после восстановления или окончательного удаления книги,
+// чтобы сбросить кэши и версии, которые обычно обновляют декораторы репозитория
+type OnChange func(ctx context.Context, bookID uuid.UUID)
+
+type IDeletedBookService interface {
+	Restore(ctx context.Context, bookID uuid.UUID) error
+	Purge(ctx context.Context) (int, error)
+}
+
+type DeletedBookService struct {
+	deletedBookRepo    IDeletedBookRepo
+	transactionManager transact.ITransactionManager
+	onChange           []OnChange
+	logger             *logrus.Entry
+	retention          time.Duration
+}
+
+func NewDeletedBookService(
+	deletedBookRepo IDeletedBookRepo,
+	transactionManager transact.ITransactionManager,
+	logger *logrus.Entry,
+	retention time.Duration,
+	onChange ...OnChange,
+) *DeletedBookService {
+	return &DeletedBookService{
+		deletedBookRepo:    deletedBookRepo,
+		transactionManager: transactionManager,
+		onChange:           onChange,
+		logger:             logger,
+		retention:          retention,
+	}
+}
+
+func (s *DeletedBookService) Restore(ctx context.Context, bookID uuid.UUID) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("attempting to restore book")
+
+	if err := s.deletedBookRepo.Restore(ctx, bookID); err != nil {
+		logger.Errorf("error restoring book: %v", err)
+		return err
+	}
+	s.changed(ctx, bookID)
+
+	logger.Info("book successfully restored")
+
+	return nil
+}
+
+// Purge окончательно удаляет книги, удаленные больше retention назад. Книга
+// с незакрытыми бронированиями ждет их закрытия
+func (s *DeletedBookService) Purge(ctx context.Context) (int, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	before := time.Now().Add(-s.retention)
+
+	bookIDs, err := s.deletedBookRepo.GetPurgeable(ctx, before)
+	if err != nil {
+		logger.Errorf("error getting purgeable books: %v", err)
+		return 0, err
+	}
+
+	purged := 0
+	for _, bookID := range bookIDs {
+		err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+			return s.deletedBookRepo.Purge(ctx, bookID, before)
+		})
+		if err != nil {
+			logger.Errorf("error purging book %s: %v", bookID, err)
+			return purged, err
+		}
+		s.changed(ctx, bookID)
+		purged++
+	}
+
+	if purged > 0 {
+		logger.Infof("purged %d deleted books", purged)
+	}
+
+	return purged, nil
+}
+
+// Run удаляет книги с истекшим сроком хранения каждые interval до отмены ctx
+func (s *DeletedBookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Purge(ctx); err != nil {
+			s.logger.Errorf("error purging deleted books: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *DeletedBookService) changed(ctx context.Context, bookID uuid.UUID) {
+	for _, onChange := range s.onChange {
+		onChange(ctx, bookID)
+	}
+}
+
+type IDeletedReaderService interface {
+	Restore(ctx context.Context, readerID uuid.UUID) error
+	Purge(ctx context.Context) (int, error)
+}
+
+type DeletedReaderService struct {
+	deletedReaderRepo  IDeletedReaderRepo
+	accountRepo        account.IAccountRepo
+	transactionManager transact.ITransactionManager
+	logger             *logrus.Entry
+	retention          time.Duration
+}
+
+// NewDeletedReaderService принимает accountRepo без AccountRepo: Purge удаляет
+// данные читателя окончательно
+func NewDeletedReaderService(
+	deletedReaderRepo IDeletedReaderRepo,
+	accountRepo account.IAccountRepo,
+	transactionManager transact.ITransactionManager,
+	logger *logrus.Entry,
+	retention time.Duration,
+) *DeletedReaderService {
+	return &DeletedReaderService{
+		deletedReaderRepo:  deletedReaderRepo,
+		accountRepo:        accountRepo,
+		transactionManager: transactionManager,
+		logger:             logger,
+		retention:          retention,
+	}
+}
+
+func (s *DeletedReaderService) Restore(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, s.logger)
+	logger.Info("attempting to restore reader")
+
+	if err := s.deletedReaderRepo.Restore(ctx, readerID); err != nil {
+		logger.Errorf("error restoring reader: %v", err)
+		return err
+	}
+
+	logger.Info("reader successfully restored")
+
+	return nil
+}
+
+// Purge окончательно удаляет читателей, удаленных больше retention назад:
+// отзывы и бронирования переходят анонимному читателю
+func (s *DeletedReaderService) Purge(ctx context.Context) (int, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	before := time.Now().Add(-s.retention)
+
+	readerIDs, err := s.deletedReaderRepo.GetPurgeable(ctx, before)
+	if err != nil {
+		logger.Errorf("error getting purgeable readers: %v", err)
+		return 0, err
+	}
+
+	purged := 0
+	for _, readerID := range readerIDs {
+		// читателя могли восстановить после GetPurgeable
+		var purgeable bool
+		err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+			var err error
+			if purgeable, err = s.deletedReaderRepo.IsPurgeable(ctx, readerID, before); err != nil || !purgeable {
+				return err
+			}
+
+			return s.accountRepo.Delete(ctx, readerID)
+		})
+		if err != nil {
+			logger.Errorf("error purging reader %s: %v", readerID, err)
+			return purged, err
+		}
+		if purgeable {
+			purged++
+		}
+	}
+
+	if purged > 0 {
+		logger.Infof("purged %d deleted readers", purged)
+	}
+
+	return purged, nil
+}
+
+// Run удаляет читателей с истекшим сроком хранения каждые interval до отмены ctx
+func (s *DeletedReaderService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Purge(ctx); err != nil {
+			s.logger.Errorf("error purging deleted readers: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package integrationTests
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart/internal/migration"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (its *IntegrationTestSuite) TestMigration_AppAfterComponent_Success(t provider.T) {
+	t.Parallel()
+	var (
+		genericConnStr string
+		db             *sqlx.DB
+		migrator       *migration.Migrator
+		componentErr   error
+		checkErr       error
+		appVersion     uint
+		status         *migration.Status
+		err            error
+	)
+	t.Title("Integration Test App Migrations After Component Migrations Success")
+	t.Description("App migrations applied after the component ones keep their version in a separate table, so both stay up to date")
+	if isUnitTestsFailed() {
+		t.Skip()
+	}
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		// BeforeAll уже применил миграции компонента, а затем приложения
+		genericConnStr, err = getGenericPostgresConnectionString(context.Background(), its.postgreContainer)
+		t.Require().Nil(err)
+
+		db, err = GetPostgresClientForIntegrationTests(fmt.Sprintf("%sbooksmart?sslmode=disable", genericConnStr))
+		t.Require().Nil(err)
+		t.Cleanup(func() { _ = db.Close() })
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		// повторный запуск миграций компонента ничего не меняет, если версию
+		// компонента не затерли миграции приложения
+		componentErr = createSchemaMigration(genericConnStr)
+
+		migrator, err = migration.NewPostgresMigrator(db.DB, logging.GetLoggerForTests())
+		t.Require().Nil(err)
+		defer func() { _ = migrator.Close() }()
+
+		checkErr = migrator.Check()
+		status, err = migrator.Status()
+		t.Require().Nil(err)
+
+		query := `select version from ` + migration.MigrationsTable
+		err = db.GetContext(context.Background(), &appVersion, query)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(componentErr)
+		t.Assert().Nil(checkErr)
+		t.Assert().Nil(err)
+		t.Assert().Equal(status.Latest, appVersion)
+	})
+}
@@ -0,0 +1,421 @@
+package integrationTests
+
+import (
+	"context"
+	"fmt"
+	trmmongo "github.com/avito-tech/go-transaction-manager/drivers/mongo/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
+	implMongo "github.com/nikitalystsev/BookSmart-repo-mongo/impl"
+	implRepo "github.com/nikitalystsev/BookSmart-repo-postgres/impl"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart-services/intf"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/inventory"
+	"github.com/nikitalystsev/BookSmart/internal/migration"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	testredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Стресс-тесты бронирования не пропускаются при упавших юнит-тестах и
+// блокируют пайплайн: гонку за экземпляры книги в Postgres и Mongo ловят
+// только они
+
+func (its *IntegrationTestSuite) TestReservation_Create_NoOverbooking(t provider.T) {
+	t.Parallel()
+	const (
+		copiesNumber = 3
+		readersCount = 12
+	)
+	var (
+		reservationService intf.IReservationService
+		readers            []*models.ReaderModel
+		book               *models.BookModel
+		results            []error
+		findCopiesNumber   uint
+		reservationsCount  int
+		err                error
+	)
+
+	t.Title("Integration Test Create Reservation No Overbooking")
+	t.Description("Concurrent reservations of a book through the app decorators never take more copies than it has and never fail on the book version")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var transactionManager transact.ITransactionManager
+		if transactionManager, err = getTransactionManagerForIntegrationTests(its.db); err != nil {
+			t.Fatal(err)
+		}
+		reservationService, _, _ = newStressReservationService(stressRepos{
+			book:        implRepo.NewBookRepo(its.db, logging.GetLoggerForTests()),
+			reader:      implRepo.NewReaderRepo(its.db, its.client, logging.GetLoggerForTests()),
+			libCard:     implRepo.NewLibCardRepo(its.db, logging.GetLoggerForTests()),
+			reservation: implRepo.NewReservationRepo(its.db, logging.GetLoggerForTests()),
+			versions:    optimistic.NewPostgresRepo(its.db, logging.GetLoggerForTests()),
+			deletedBook: softdelete.NewPostgresRepo(its.db, logging.GetLoggerForTests()),
+			copies:      inventory.NewPostgresRepo(its.db, logging.GetLoggerForTests()),
+		}, transactionManager)
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(copiesNumber).Build()
+		_, err = its.db.ExecContext(
+			context.Background(), `insert into bs.book values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			book.ID,
+			book.Title,
+			book.Author,
+			book.Publisher,
+			book.CopiesNumber,
+			book.Rarity,
+			book.Genre,
+			book.PublishingYear,
+			book.Language,
+			book.AgeLimit,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < readersCount; i++ {
+			reader := tdbmodels.NewReaderModelBuilder().
+				WithPhoneNumber(fmt.Sprintf("555555555%02d", i)).Build()
+			_, err = its.db.ExecContext(
+				context.Background(), `insert into bs.reader values ($1, $2, $3, $4, $5, $6)`,
+				reader.ID,
+				reader.Fio,
+				reader.PhoneNumber,
+				reader.Age,
+				reader.Password,
+				reader.Role,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			libCard := tdbmodels.NewLibCardModelBuilder().
+				WithReaderID(reader.ID).
+				WithLibCardNum(fmt.Sprintf("55555555555%02d", i)).Build()
+			_, err = its.db.ExecContext(
+				context.Background(), `insert into bs.lib_card values ($1, $2, $3, $4, $5, $6)`,
+				libCard.ID,
+				libCard.ReaderID,
+				libCard.LibCardNum,
+				libCard.Validity,
+				libCard.IssueDate,
+				libCard.ActionStatus,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			readers = append(readers, reader)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		results = reserveConcurrently(reservationService, readers, book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		successCount := 0
+		for _, result := range results {
+			if result == nil {
+				successCount++
+				continue
+			}
+			t.Assert().ErrorIs(result, errs.ErrBookNoCopiesNum)
+		}
+		t.Assert().Equal(copiesNumber, successCount)
+
+		err = its.db.GetContext(context.Background(), &findCopiesNumber, `select copies_number from bs.book where id = $1`, book.ID)
+		t.Assert().Nil(err)
+		t.Assert().Equal(uint(0), findCopiesNumber)
+
+		err = its.db.GetContext(context.Background(), &reservationsCount, `select count(*) from bs.reservation where book_id = $1`, book.ID)
+		t.Assert().Nil(err)
+		t.Assert().Equal(copiesNumber, reservationsCount)
+	})
+}
+
+func (mits *MongoIntegrationTestSuite) TestReservation_Create_NoOverbooking(t provider.T) {
+	const (
+		copiesNumber = 3
+		readersCount = 12
+	)
+	var (
+		reservationService intf.IReservationService
+		bookRepo           intfRepo.IBookRepo
+		reservationRepo    intfRepo.IReservationRepo
+		readers            []*models.ReaderModel
+		book               *models.BookModel
+		results            []error
+		findBook           *models.BookModel
+		findReservations   []*models.ReservationModel
+		err                error
+	)
+
+	t.Title("Integration Test Create Reservation No Overbooking In Mongo")
+	t.Description("Concurrent reservations of a book in Mongo through the app decorators never take more copies than it has and never fail on the book version")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		readerRepo := implMongo.NewReaderRepo(mits.db, mits.redisClient, logging.GetLoggerForTests())
+		libCardRepo := implMongo.NewLibCardRepo(mits.db, logging.GetLoggerForTests())
+		var transactionManager transact.ITransactionManager
+		if transactionManager, err = getMongoTransactionManagerForIntegrationTests(mits.client); err != nil {
+			t.Fatal(err)
+		}
+		reservationService, bookRepo, reservationRepo = newStressReservationService(stressRepos{
+			book:        implMongo.NewBookRepo(mits.db, logging.GetLoggerForTests()),
+			reader:      readerRepo,
+			libCard:     libCardRepo,
+			reservation: implMongo.NewReservationRepo(mits.db, logging.GetLoggerForTests()),
+			versions:    optimistic.NewMongoRepo(mits.db, logging.GetLoggerForTests()),
+			deletedBook: softdelete.NewMongoRepo(mits.db, logging.GetLoggerForTests()),
+			copies:      inventory.NewMongoRepo(mits.db, logging.GetLoggerForTests()),
+		}, transactionManager)
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(copiesNumber).Build()
+		if err = bookRepo.Create(context.Background(), book); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < readersCount; i++ {
+			reader := tdbmodels.NewReaderModelBuilder().
+				WithPhoneNumber(fmt.Sprintf("555555555%02d", i)).Build()
+			if err = readerRepo.Create(context.Background(), reader); err != nil {
+				t.Fatal(err)
+			}
+			libCard := tdbmodels.NewLibCardModelBuilder().
+				WithReaderID(reader.ID).
+				WithLibCardNum(fmt.Sprintf("55555555555%02d", i)).Build()
+			if err = libCardRepo.Create(context.Background(), libCard); err != nil {
+				t.Fatal(err)
+			}
+			readers = append(readers, reader)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		results = reserveConcurrently(reservationService, readers, book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		successCount := 0
+		for _, result := range results {
+			if result == nil {
+				successCount++
+				continue
+			}
+			t.Assert().ErrorIs(result, errs.ErrBookNoCopiesNum)
+		}
+		t.Assert().Equal(copiesNumber, successCount)
+
+		findBook, err = bookRepo.GetByID(context.Background(), book.ID)
+		t.Assert().Nil(err)
+		t.Assert().Equal(uint(0), findBook.CopiesNumber)
+
+		findReservations, err = reservationRepo.GetByBookID(context.Background(), book.ID)
+		t.Assert().Nil(err)
+		t.Assert().Len(findReservations, copiesNumber)
+	})
+}
+
+// stressRepos - репозитории БД без декораторов
+type stressRepos struct {
+	book        intfRepo.IBookRepo
+	reader      intfRepo.IReaderRepo
+	libCard     intfRepo.ILibCardRepo
+	reservation intfRepo.IReservationRepo
+	versions    optimistic.IVersionRepo
+	deletedBook softdelete.IDeletedBookRepo
+	copies      inventory.ICopiesRepo
+}
+
+// newStressReservationService собирает сервис бронирования на тех же
+// декораторах, что и app.go: проверка версий, мягкое удаление и условное
+// изменение числа экземпляров. Возвращает и собранные репозитории книг и броней
+func newStressReservationService(
+	repos stressRepos,
+	transactionManager transact.ITransactionManager,
+) (intf.IReservationService, intfRepo.IBookRepo, intfRepo.IReservationRepo) {
+	bookRepo := optimistic.NewBookRepo(repos.book, repos.versions, transactionManager)
+	bookRepo = softdelete.NewBookRepo(bookRepo, repos.deletedBook, repos.versions)
+	bookRepo = inventory.NewBookRepo(bookRepo, repos.copies)
+	libCardRepo := optimistic.NewLibCardRepo(repos.libCard, repos.versions, transactionManager)
+	reservationRepo := optimistic.NewReservationRepo(repos.reservation, repos.versions, transactionManager)
+
+	reservationService := softdelete.NewReservationService(impl.NewReservationService(
+		reservationRepo,
+		bookRepo,
+		repos.reader,
+		libCardRepo,
+		optimistic.NewTransactionManager(inventory.NewTransactionManager(transactionManager)),
+		logging.GetLoggerForTests(),
+	))
+
+	return reservationService, bookRepo, reservationRepo
+}
+
+// reserveConcurrently одновременно бронирует книгу всеми читателями и
+// возвращает результаты в порядке читателей
+func reserveConcurrently(reservationService intf.IReservationService, readers []*models.ReaderModel, bookID uuid.UUID) []error {
+	results := make([]error, len(readers))
+
+	var wg sync.WaitGroup
+	for i, reader := range readers {
+		wg.Add(1)
+		go func(i int, reader *models.ReaderModel) {
+			defer wg.Done()
+			results[i] = reservationService.Create(context.Background(), reader.ID, bookID)
+		}(i, reader)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// MongoIntegrationTestSuite проверяет реализации, написанные отдельно для Mongo
+type MongoIntegrationTestSuite struct {
+	suite.Suite
+
+	mongoContainer testcontainers.Container
+	redisContainer *testredis.RedisContainer
+	client         *mongo.Client
+	redisClient    *redis.Client
+	db             *mongo.Database
+}
+
+func (mits *MongoIntegrationTestSuite) BeforeAll(t provider.T) {
+	var err error
+
+	if mits.mongoContainer, err = GetMongoForIntegrationTests(); err != nil {
+		t.Fatal(err)
+	}
+
+	if mits.redisContainer, err = GetRedisForIntegrationTests(); err != nil {
+		t.Fatal(err)
+	}
+
+	if mits.client, err = GetMongoClientForIntegrationTests(mits.mongoContainer); err != nil {
+		t.Fatal(err)
+	}
+	mits.db = mits.client.Database("booksmart")
+
+	migrator, err := migration.NewMongoMigrator(mits.client, mits.db.Name(), logging.GetLoggerForTests())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if mits.redisClient, err = GetRedisClientForIntegrationTests(mits.redisContainer); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (mits *MongoIntegrationTestSuite) AfterAll(t provider.T) {
+	if err := mits.client.Disconnect(context.Background()); err != nil {
+		t.Fatalf("failed to disconnect from mongo: %s", err)
+	}
+
+	if err := mits.mongoContainer.Terminate(context.Background()); err != nil {
+		t.Fatalf("failed to terminate container: %s", err)
+	}
+
+	if err := mits.redisContainer.Terminate(context.Background()); err != nil {
+		t.Fatalf("failed to terminate container: %s", err)
+	}
+}
+
+func TestMongoIntegrationTestSuite(t *testing.T) {
+	suite.RunSuite(t, new(MongoIntegrationTestSuite))
+}
+
+// GetMongoForIntegrationTests запускает Mongo из одного узла набора реплик:
+// транзакции Mongo работают только в наборе реплик
+func GetMongoForIntegrationTests() (testcontainers.Container, error) {
+	ctx := context.Background()
+
+	mongoContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "mongo:7",
+			ExposedPorts: []string{"27017/tcp"},
+			Cmd:          []string{"--replSet", "rs0", "--bind_ip_all"},
+			WaitingFor:   wait.ForLog("Waiting for connections").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+		Logger:  log.New(io.Discard, "", 0),
+	})
+	if err != nil {
+		fmt.Printf("Failed to start mongo container: %v\n", err)
+		return nil, err
+	}
+
+	exitCode, _, err := mongoContainer.Exec(ctx, []string{
+		"mongosh", "--quiet", "--eval", `rs.initiate({_id: "rs0", members: [{_id: 0, host: "localhost:27017"}]})`,
+	})
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("rs.initiate exited with code %d", exitCode)
+	}
+	if err != nil {
+		fmt.Printf("Failed to initiate mongo replica set: %v\n", err)
+		return nil, err
+	}
+
+	return mongoContainer, nil
+}
+
+// GetMongoClientForIntegrationTests подключается к узлу напрямую и ждет,
+// пока он станет первичным
+func GetMongoClientForIntegrationTests(container testcontainers.Container) (*mongo.Client, error) {
+	ctx := context.Background()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	port, err := container.MappedPort(ctx, "27017/tcp")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().
+		ApplyURI(fmt.Sprintf("mongodb://%s:%s/?directConnection=true", host, port.Port())))
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 30; attempt++ {
+		if err = client.Ping(ctx, readpref.Primary()); err == nil {
+			return client, nil
+		}
+		time.Sleep(time.Second)
+	}
+
+	return nil, err
+}
+
+func getMongoTransactionManagerForIntegrationTests(client *mongo.Client) (transact.ITransactionManager, error) {
+	trm, err := manager.New(trmmongo.NewDefaultFactory(client))
+	if err != nil {
+		return nil, err
+	}
+
+	transactionManager := transact.NewTransactionManager(trm)
+
+	return transactionManager, err
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/migration"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
 	"github.com/redis/go-redis/v9"
 	"github.com/testcontainers/testcontainers-go"
 	testpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
@@ -128,10 +130,31 @@ func ApplyMigrations(container *testpostgres.PostgresContainer) (*sqlx.DB, error
 	if err != nil {
 		return nil, err
 	}
+	if err = applyAppMigrations(genericConnStr); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
 
+// applyAppMigrations применяет встроенные миграции приложения (версии строк,
+// мягкое удаление) после миграций компонента, как это делает migrate.sh
+func applyAppMigrations(genericConnStr string) error {
+	db, err := sqlx.Open("postgres", fmt.Sprintf("%sbooksmart?sslmode=disable", genericConnStr))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	migrator, err := migration.NewPostgresMigrator(db.DB, logging.GetLoggerForTests())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = migrator.Close() }()
+
+	return migrator.Up()
+}
+
 func createDBMigration(genericConnStr string) error {
 	dsn := fmt.Sprintf("%s?sslmode=disable", genericConnStr)
 
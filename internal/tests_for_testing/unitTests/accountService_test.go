@@ -0,0 +1,189 @@
+package unitTests
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"testing"
+)
+
+type AccountServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (asts *AccountServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "account service", "suite", "steps")
+}
+
+type accountServiceMocks struct {
+	accountRepo        *mockrepo.MockIAccountRepo
+	readerRepo         *mockrepo.MockIReaderRepo
+	reservationRepo    *mockrepo.MockIReservationRepo
+	refreshTokenRepo   *mockrepo.MockIRefreshTokenRepo
+	hasher             *mockrepo.MockIPasswordHasher
+	transactionManager *mockrepo.MockITransactionManager
+}
+
+func newAccountService(t provider.T) (account.IAccountService, *accountServiceMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := &accountServiceMocks{
+		accountRepo:        mockrepo.NewMockIAccountRepo(ctrl),
+		readerRepo:         mockrepo.NewMockIReaderRepo(ctrl),
+		reservationRepo:    mockrepo.NewMockIReservationRepo(ctrl),
+		refreshTokenRepo:   mockrepo.NewMockIRefreshTokenRepo(ctrl),
+		hasher:             mockrepo.NewMockIPasswordHasher(ctrl),
+		transactionManager: mockrepo.NewMockITransactionManager(ctrl),
+	}
+
+	mocks.transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	accountService := account.NewAccountService(
+		mocks.accountRepo,
+		mocks.readerRepo,
+		mocks.reservationRepo,
+		mocks.refreshTokenRepo,
+		mocks.hasher,
+		mocks.transactionManager,
+		logging.GetLoggerForTests(),
+	)
+
+	return accountService, mocks
+}
+
+func (asts *AccountServiceTestsSuite) Test_ChangePassword_Success(t provider.T) {
+	var (
+		accountService account.IAccountService
+		reader         *models.ReaderModel
+		err            error
+	)
+
+	t.Title("Test Change Reader Password Success")
+	t.Description("The password was changed and all refresh tokens of the reader were revoked")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *accountServiceMocks
+		accountService, mocks = newAccountService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+		oldHash := reader.Password
+
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), reader.ID).Return(reader, nil)
+		mocks.hasher.EXPECT().Compare(oldHash, "oldPassword12").Return(nil)
+		mocks.hasher.EXPECT().Hash("newPassword12").Return("newHash", nil)
+		mocks.accountRepo.EXPECT().Update(gomock.Any(), reader).Return(nil)
+		mocks.refreshTokenRepo.EXPECT().DeleteByReaderID(gomock.Any(), reader.ID).Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = accountService.ChangePassword(context.Background(), reader.ID, "oldPassword12", "newPassword12")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("newHash", reader.Password)
+	})
+}
+
+func (asts *AccountServiceTestsSuite) Test_ChangePassword_ErrorWrongPassword(t provider.T) {
+	var (
+		accountService account.IAccountService
+		reader         *models.ReaderModel
+		err            error
+	)
+
+	t.Title("Test Change Reader Password Error: wrong password")
+	t.Description("The password was not changed because the current password is wrong")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *accountServiceMocks
+		accountService, mocks = newAccountService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), reader.ID).Return(reader, nil)
+		mocks.hasher.EXPECT().Compare(reader.Password, "wrongPassword").Return(errors.New("mismatch"))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = accountService.ChangePassword(context.Background(), reader.ID, "wrongPassword", "newPassword12")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(account.ErrWrongPassword, err)
+	})
+}
+
+func (asts *AccountServiceTestsSuite) Test_ChangePhoneNumber_ErrorReaderAlreadyExist(t provider.T) {
+	var (
+		accountService account.IAccountService
+		reader         *models.ReaderModel
+		err            error
+	)
+
+	t.Title("Test Change Reader Phone Number Error: reader already exists")
+	t.Description("The phone number was not changed because another reader uses it")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *accountServiceMocks
+		accountService, mocks = newAccountService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+		other := tdbmodels.NewReaderModelBuilder().WithPhoneNumber("89990001122").Build()
+
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), reader.ID).Return(reader, nil)
+		mocks.hasher.EXPECT().Compare(reader.Password, "password12").Return(nil)
+		mocks.readerRepo.EXPECT().GetByPhoneNumber(gomock.Any(), other.PhoneNumber).Return(other, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = accountService.ChangePhoneNumber(context.Background(), reader.ID, "password12", "89990001122")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(errs.ErrReaderAlreadyExist, err)
+	})
+}
+
+func (asts *AccountServiceTestsSuite) Test_Delete_ErrorReaderHasBooksOnLoan(t provider.T) {
+	var (
+		accountService account.IAccountService
+		reader         *models.ReaderModel
+		err            error
+	)
+
+	t.Title("Test Delete Reader Error: reader has books on loan")
+	t.Description("The account was not deleted because the reader has not returned all books")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *accountServiceMocks
+		accountService, mocks = newAccountService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+		reservation := tdbmodels.NewReservationModelBuilder().WithReaderID(reader.ID).Build()
+
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), reader.ID).Return(reader, nil)
+		mocks.hasher.EXPECT().Compare(reader.Password, "password12").Return(nil)
+		mocks.reservationRepo.EXPECT().GetActiveByReaderID(gomock.Any(), reader.ID).
+			Return([]*models.ReservationModel{reservation}, nil)
+		mocks.reservationRepo.EXPECT().GetExpiredByReaderID(gomock.Any(), reader.ID).
+			Return(nil, errs.ErrReservationDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = accountService.Delete(context.Background(), reader.ID, "password12")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(account.ErrReaderHasBooksOnLoan, err)
+	})
+}
+
+func TestAccountServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(AccountServiceTestsSuite))
+}
@@ -0,0 +1,216 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/cache"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"sync"
+	"testing"
+	"time"
+)
+
+type CacheTestsSuite struct {
+	suite.Suite
+}
+
+func (cts *CacheTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "cache", "suite", "steps")
+}
+
+var testCacheTTL = cache.TTL{Fresh: time.Minute, Stale: time.Minute}
+
+func newTestCache(t provider.T) (*cache.Cache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	return cache.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests()), mr
+}
+
+func (cts *CacheTestsSuite) Test_BookRepo_ReadThroughAndInvalidate(t provider.T) {
+	var (
+		repo                    intfRepo.IBookRepo
+		book, updated           *models.BookModel
+		first, cached, reloaded *models.BookModel
+	)
+
+	t.Title("Test Book Repo Read Through And Invalidate")
+	t.Description("A book is read from the database once and reloaded after an update")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().Build()
+		updated = tdbmodels.NewBookModelBuilder().WithID(book.ID).WithTitle("Updated").Build()
+
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+		gomock.InOrder(
+			bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil),
+			bookRepo.EXPECT().Update(gomock.Any(), updated).Return(nil),
+			bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(updated, nil),
+		)
+
+		testCache, _ := newTestCache(t)
+		repo = cache.NewBookRepo(bookRepo, testCache, testCacheTTL, testCacheTTL)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		first, _ = repo.GetByID(context.Background(), book.ID)
+		cached, _ = repo.GetByID(context.Background(), book.ID)
+		_ = repo.Update(context.Background(), updated)
+		reloaded, _ = repo.GetByID(context.Background(), book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(book, first)
+		t.Assert().Equal(book, cached)
+		t.Assert().Equal("Updated", reloaded.Title)
+	})
+}
+
+func (cts *CacheTestsSuite) Test_BookRepo_StampedeProtection(t provider.T) {
+	const callers = 20
+
+	var (
+		repo    intfRepo.IBookRepo
+		results [callers][]*models.BookModel
+	)
+
+	t.Title("Test Book Repo Stampede Protection")
+	t.Description("Concurrent misses for the same query result in a single database call")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+		bookRepo.EXPECT().GetByParams(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, params *dto.BookParamsDTO) ([]*models.BookModel, error) {
+				time.Sleep(50 * time.Millisecond)
+				return []*models.BookModel{tdbmodels.NewBookModelBuilder().Build()}, nil
+			}).Times(1)
+
+		testCache, _ := newTestCache(t)
+		repo = cache.NewBookRepo(bookRepo, testCache, testCacheTTL, testCacheTTL)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = repo.GetByParams(context.Background(), &dto.BookParamsDTO{Genre: "Fantasy", Limit: 10})
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		for _, result := range results {
+			t.Assert().Len(result, 1)
+		}
+	})
+}
+
+func (cts *CacheTestsSuite) Test_RatingRepo_ServesStaleWhileRefreshing(t provider.T) {
+	var (
+		repo             intfRepo.IRatingRepo
+		rating           *models.RatingModel
+		stale, refreshed []*models.RatingModel
+		refreshedRatings = []*models.RatingModel{tdbmodels.NewRatingModelBuilder().Build()}
+	)
+
+	t.Title("Test Rating Repo Serves Stale While Refreshing")
+	t.Description("After the soft TTL the old ratings are returned while new ones are loaded in the background")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		rating = tdbmodels.NewRatingModelBuilder().Build()
+
+		ratingRepo := mockrepo.NewMockIRatingRepo(gomock.NewController(t))
+		gomock.InOrder(
+			ratingRepo.EXPECT().GetByBookID(gomock.Any(), rating.BookID, 10, 0).Return([]*models.RatingModel{rating}, nil),
+			ratingRepo.EXPECT().GetByBookID(gomock.Any(), rating.BookID, 10, 0).Return(refreshedRatings, nil),
+		)
+
+		testCache, _ := newTestCache(t)
+		repo = cache.NewRatingRepo(ratingRepo, testCache, cache.TTL{Fresh: 10 * time.Millisecond, Stale: time.Minute})
+		_, _ = repo.GetByBookID(context.Background(), rating.BookID, 10, 0)
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		stale, _ = repo.GetByBookID(context.Background(), rating.BookID, 10, 0)
+
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			refreshed, _ = repo.GetByBookID(context.Background(), rating.BookID, 10, 0)
+			if len(refreshed) == 1 && refreshed[0].ID == refreshedRatings[0].ID {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal([]*models.RatingModel{rating}, stale)
+		t.Assert().Equal(refreshedRatings, refreshed)
+	})
+}
+
+func (cts *CacheTestsSuite) Test_TransactionManager_InvalidatesAfterCommit(t provider.T) {
+	var (
+		mr                      *miniredis.Miniredis
+		repo                    intfRepo.IRatingRepo
+		tm                      transact.ITransactionManager
+		rating                  *models.RatingModel
+		cachedInTx, cachedAfter bool
+		err                     error
+	)
+
+	t.Title("Test Transaction Manager Invalidates After Commit")
+	t.Description("A rating created in a transaction drops the book's cached ratings only when the transaction ends")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		rating = tdbmodels.NewRatingModelBuilder().Build()
+
+		ctrl := gomock.NewController(t)
+		ratingRepo := mockrepo.NewMockIRatingRepo(ctrl)
+		ratingRepo.EXPECT().GetByBookID(gomock.Any(), rating.BookID, 10, 0).Return([]*models.RatingModel{}, nil)
+		ratingRepo.EXPECT().Create(gomock.Any(), rating).Return(nil)
+		transactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		var testCache *cache.Cache
+		testCache, mr = newTestCache(t)
+		repo = cache.NewRatingRepo(ratingRepo, testCache, testCacheTTL)
+		tm = cache.NewTransactionManager(transactionManager, testCache)
+
+		_, _ = repo.GetByBookID(context.Background(), rating.BookID, 10, 0)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = tm.Do(context.Background(), func(ctx context.Context) error {
+			err := repo.Create(ctx, rating)
+			cachedInTx = mr.Exists("cache:ratings:" + rating.BookID.String())
+			return err
+		})
+		cachedAfter = mr.Exists("cache:ratings:" + rating.BookID.String())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().True(cachedInTx)
+		t.Assert().False(cachedAfter)
+	})
+}
+
+func TestCacheTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CacheTestsSuite))
+}
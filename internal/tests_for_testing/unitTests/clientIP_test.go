@@ -0,0 +1,111 @@
+package unitTests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+type ClientIPTestsSuite struct {
+	suite.Suite
+}
+
+func (cits *ClientIPTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "client ip", "suite", "steps")
+}
+
+// resolveClientIP прогоняет запрос через ClientIPMiddleware и возвращает
+// адрес клиента, который увидит обработчик
+func resolveClientIP(trustedProxies []netip.Prefix, request *http.Request) string {
+	var ip string
+
+	engine := gin.New()
+	engine.Use(session.ClientIPMiddleware(trustedProxies))
+	engine.GET("/", func(c *gin.Context) {
+		ip = session.ClientIP(c.Request)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), request)
+
+	return ip
+}
+
+func (cits *ClientIPTestsSuite) Test_ClientIP_RightmostUntrustedHop(t provider.T) {
+	var (
+		trustedProxies []netip.Prefix
+		request        *http.Request
+		ip             string
+		err            error
+	)
+
+	t.Title("Test Client IP: rightmost untrusted hop")
+	t.Description("A value spoofed by the client on the left of X-Forwarded-For is ignored")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		trustedProxies, err = session.ParseTrustedProxies([]string{"172.16.0.0/12"})
+		request = httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = "172.18.0.5:41000"
+		request.Header.Set("X-Forwarded-For", "1.1.1.1, 203.0.113.7, 172.18.0.4")
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		ip = resolveClientIP(trustedProxies, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("203.0.113.7", ip)
+	})
+}
+
+func (cits *ClientIPTestsSuite) Test_ClientIP_UntrustedRemoteIgnoresHeaders(t provider.T) {
+	var (
+		trustedProxies []netip.Prefix
+		request        *http.Request
+		ip             string
+		err            error
+	)
+
+	t.Title("Test Client IP: untrusted remote address")
+	t.Description("Forwarding headers from a client outside the trusted proxies are ignored")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		trustedProxies, err = session.ParseTrustedProxies([]string{"172.16.0.0/12"})
+		request = httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = "203.0.113.7:41000"
+		request.Header.Set("X-Forwarded-For", "1.1.1.1")
+		request.Header.Set("X-Real-IP", "1.1.1.1")
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		ip = resolveClientIP(trustedProxies, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("203.0.113.7", ip)
+	})
+}
+
+func (cits *ClientIPTestsSuite) Test_ParseTrustedProxies_ErrorInvalidCIDR(t provider.T) {
+	var err error
+
+	t.Title("Test Parse Trusted Proxies Error: invalid CIDR")
+	t.Description("A proxy address without a prefix length is rejected")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = session.ParseTrustedProxies([]string{"172.18.0.4"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().NotNil(err)
+	})
+}
+
+func TestClientIPTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ClientIPTestsSuite))
+}
@@ -0,0 +1,250 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/conditional"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ConditionalTestsSuite struct {
+	suite.Suite
+}
+
+func (cts *ConditionalTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "conditional", "suite", "steps")
+}
+
+// newConditionalServer подключает роутер с маршрутами книги за middleware
+// так же, как это делает приложение: версии книги хранятся в памяти рядом с
+// ее данными. edit выполняет правку книги в обработчике PUT
+func newConditionalServer(t provider.T, book *models.BookModel, edit func(context.Context, intfRepo.IBookRepo) error) (*gin.Engine, intfRepo.IBookRepo) {
+	gin.SetMode(gin.TestMode)
+
+	memoryStore := memory.NewStore(logging.GetLoggerForTests())
+	trm, err := manager.New(memoryStore.TrFactory)
+	t.Require().Nil(err)
+	versions := memory.NewVersionRepo(memoryStore, logging.GetLoggerForTests())
+
+	store := conditional.NewRedisVersionStore(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), logging.GetLoggerForTests())
+	t.Require().Nil(store.Touch(context.Background(), "book:"+book.ID.String()))
+
+	bookRepo := optimistic.NewBookRepo(memory.NewBookRepo(memoryStore, logging.GetLoggerForTests()), versions, trm)
+	bookRepo = conditional.NewBookRepo(bookRepo, store)
+	t.Require().Nil(bookRepo.Create(context.Background(), book))
+
+	router := gin.New()
+	router.GET("/api/v1/books/:id", func(c *gin.Context) {
+		read, err := bookRepo.GetByID(c.Request.Context(), book.ID)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"title": read.Title})
+	})
+	router.PUT("/api/v1/books/:id", func(c *gin.Context) {
+		if err := edit(c.Request.Context(), bookRepo); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	server := gin.New()
+	server.Use(conditional.Middleware(store, versions, middleware.NewRouteMatcher(router.Routes()), logging.GetLoggerForTests()))
+	server.NoRoute(gin.WrapH(router))
+
+	return server, bookRepo
+}
+
+func serveConditional(server *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	return response
+}
+
+func (cts *ConditionalTestsSuite) Test_Middleware_NotModified(t provider.T) {
+	var (
+		server                        *gin.Engine
+		path                          string
+		first, notModified, afterEdit *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Not Modified")
+	t.Description("A GET with the current ETag gets 304 until the book is updated")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book := tdbmodels.NewBookModelBuilder().Build()
+		path = "/api/v1/books/" + book.ID.String()
+		server, _ = newConditionalServer(t, book, func(ctx context.Context, repo intfRepo.IBookRepo) error {
+			return editBook(ctx, repo, book)
+		})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		first = serveConditional(server, http.MethodGet, path, nil)
+		notModified = serveConditional(server, http.MethodGet, path, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+		serveConditional(server, http.MethodPut, path, nil)
+		afterEdit = serveConditional(server, http.MethodGet, path, map[string]string{"If-None-Match": first.Header().Get("ETag")})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, first.Code)
+		t.Assert().NotEmpty(first.Header().Get("ETag"))
+		t.Assert().NotEmpty(first.Header().Get("Last-Modified"))
+		t.Assert().Equal(http.StatusNotModified, notModified.Code)
+		t.Assert().Empty(notModified.Body.String())
+		t.Assert().Equal(http.StatusOK, afterEdit.Code)
+		t.Assert().NotEqual(first.Header().Get("ETag"), afterEdit.Header().Get("ETag"))
+	})
+}
+
+func (cts *ConditionalTestsSuite) Test_Middleware_IfMatch(t provider.T) {
+	var (
+		server                         *gin.Engine
+		path                           string
+		current, stale, fresh, updated *httptest.ResponseRecorder
+		edits                          int
+	)
+
+	t.Title("Test Middleware If-Match")
+	t.Description("A write with an outdated ETag fails with 412 and does not reach the handler")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book := tdbmodels.NewBookModelBuilder().Build()
+		path = "/api/v1/books/" + book.ID.String()
+		server, _ = newConditionalServer(t, book, func(ctx context.Context, repo intfRepo.IBookRepo) error {
+			edits++
+			return editBook(ctx, repo, book)
+		})
+		current = serveConditional(server, http.MethodGet, path, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		fresh = serveConditional(server, http.MethodPut, path, map[string]string{"If-Match": current.Header().Get("ETag")})
+		stale = serveConditional(server, http.MethodPut, path, map[string]string{"If-Match": current.Header().Get("ETag")})
+		updated = serveConditional(server, http.MethodGet, path, nil)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusNoContent, fresh.Code)
+		t.Assert().NotEmpty(fresh.Header().Get("ETag"))
+		t.Assert().NotEqual(current.Header().Get("ETag"), fresh.Header().Get("ETag"))
+		// ETag после записи совпадает с ETag следующего чтения
+		t.Assert().Equal(updated.Header().Get("ETag"), fresh.Header().Get("ETag"))
+		t.Assert().Equal(http.StatusPreconditionFailed, stale.Code)
+		t.Assert().Equal(1, edits)
+	})
+}
+
+func (cts *ConditionalTestsSuite) Test_Middleware_IfMatchConcurrentWrite(t provider.T) {
+	var (
+		server        *gin.Engine
+		repo          intfRepo.IBookRepo
+		book          *models.BookModel
+		path          string
+		current, resp *httptest.ResponseRecorder
+		stored        *models.BookModel
+	)
+
+	t.Title("Test Middleware If-Match Concurrent Write")
+	t.Description("A write whose ETag matched but the book changed before the write is rejected by the version check with 412")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().Build()
+		path = "/api/v1/books/" + book.ID.String()
+		server, repo = newConditionalServer(t, book, func(ctx context.Context, repo intfRepo.IBookRepo) error {
+			// параллельная правка без If-Match проходит между проверкой
+			// If-Match и записью
+			if err := editBook(context.Background(), repo, book); err != nil {
+				return err
+			}
+			return editBook(ctx, repo, book)
+		})
+		current = serveConditional(server, http.MethodGet, path, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		resp = serveConditional(server, http.MethodPut, path, map[string]string{"If-Match": current.Header().Get("ETag")})
+		stored, _ = repo.GetByID(context.Background(), book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusPreconditionFailed, resp.Code)
+		// записана только параллельная правка
+		t.Assert().Equal(book.Title+" (2-е издание)", stored.Title)
+	})
+}
+
+func (cts *ConditionalTestsSuite) Test_TransactionManager_TouchesAfterCommit(t provider.T) {
+	var (
+		store                 conditional.IVersionStore
+		repo                  intfRepo.IReservationRepo
+		reservation           *models.ReservationModel
+		tm                    transact.ITransactionManager
+		knownInTx, knownAfter bool
+		err                   error
+	)
+
+	t.Title("Test Transaction Manager Touches After Commit")
+	t.Description("A reservation updated in a transaction gets a new version only when the transaction ends")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		reservation = tdbmodels.NewReservationModelBuilder().Build()
+
+		ctrl := gomock.NewController(t)
+		reservationRepo := mockrepo.NewMockIReservationRepo(ctrl)
+		reservationRepo.EXPECT().Update(gomock.Any(), reservation).Return(nil)
+		transactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		store = conditional.NewRedisVersionStore(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), logging.GetLoggerForTests())
+		repo = conditional.NewReservationRepo(reservationRepo, store)
+		tm = conditional.NewTransactionManager(transactionManager, store)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = tm.Do(context.Background(), func(ctx context.Context) error {
+			err := repo.Update(ctx, reservation)
+			_, knownInTx, _ = store.Get(ctx, "reservation:"+reservation.ID.String())
+			return err
+		})
+		_, knownAfter, _ = store.Get(context.Background(), "reservation:"+reservation.ID.String())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().False(knownInTx)
+		t.Assert().True(knownAfter)
+	})
+}
+
+func TestConditionalTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ConditionalTestsSuite))
+}
@@ -0,0 +1,247 @@
+package unitTests
+
+import (
+	"github.com/nikitalystsev/BookSmart/internal/config"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type ConfigTestsSuite struct {
+	suite.Suite
+}
+
+func (cts *ConfigTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "config", "suite", "steps")
+}
+
+const testConfigYAML = `
+db:
+  type: postgres
+rateLimit:
+  enabled: true
+  default:
+    rate: 5
+    burst: 15
+logging:
+  level: info
+`
+
+func writeTestConfig(t provider.T, dir, content string) {
+	// запись через переименование, чтобы наблюдатель не прочитал файл наполовину
+	tmp := filepath.Join(dir, "config.yml.tmp")
+	t.Require().Nil(os.WriteFile(tmp, []byte(content), 0o600))
+	t.Require().Nil(os.Rename(tmp, filepath.Join(dir, "config.yml")))
+}
+
+func setTestEnv(t provider.T, env map[string]string) {
+	for key, value := range env {
+		t.Require().Nil(os.Setenv(key, value))
+		t.Cleanup(func() { _ = os.Unsetenv(key) })
+	}
+}
+
+var testConfigEnv = map[string]string{
+	"POSTGRES_HOST":    "postgres",
+	"POSTGRES_DB_NAME": "booksmart",
+	"POSTGRES_DB_USER": "booksmart",
+	"REDIS_HOST":       "redis",
+	"JWT_SIGNING_KEY":  "secret",
+	"APP_PORT":         "8000",
+}
+
+func (cts *ConfigTestsSuite) Test_Init_Layers(t provider.T) {
+	var (
+		dir string
+		cfg *config.Config
+		err error
+	)
+
+	t.Title("Test Init Layers")
+	t.Description("Defaults are overridden by the file, the file by env and env by command line flags")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+		writeTestConfig(t, dir, testConfigYAML)
+		setTestEnv(t, testConfigEnv)
+		setTestEnv(t, map[string]string{"BOOKSMART_RATELIMIT_DEFAULT_BURST": "20"})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		cfg, err = config.Init(dir, []string{"--port", "9000", "--set", "logging.level=debug"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().Nil(err)
+		t.Assert().Equal(config.DBTypePostgres, cfg.DBType)
+		t.Assert().Equal("postgres", cfg.Postgres.Host)
+		t.Assert().Equal("5432", cfg.Postgres.Port)
+		t.Assert().Equal("secret", cfg.Auth.JWT.SigningKey)
+		t.Assert().Equal(2*time.Hour, cfg.Auth.JWT.AccessTokenTTL)
+		t.Assert().Equal(float64(5), cfg.RateLimit.Default.Rate)
+		t.Assert().Equal(20, cfg.RateLimit.Default.Burst)
+		t.Assert().Equal("9000", cfg.Port)
+		t.Assert().Equal("debug", cfg.Logging.Level)
+		t.Assert().Equal(20*time.Second, cfg.Server.ShutdownTimeout)
+	})
+}
+
+func (cts *ConfigTestsSuite) Test_Init_ValidationErrors(t provider.T) {
+	var (
+		dir string
+		err error
+	)
+
+	t.Title("Test Init Validation Errors")
+	t.Description("Every invalid setting is reported with its path")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+		writeTestConfig(t, dir, testConfigYAML+"tracing:\n  exporter: zipkin\n")
+		setTestEnv(t, map[string]string{"REDIS_HOST": "redis"})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = config.Init(dir, []string{"--set", "rateLimit.default.rate=0", "--log-level", "verbose"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().ErrorIs(err, config.ErrInvalidConfig)
+		for _, field := range []string{"postgres.host", "auth.signingKey", "rateLimit.default.rate",
+			"tracing.exporter", "logging.level"} {
+			t.Assert().Contains(err.Error(), field+":")
+		}
+		t.Assert().NotContains(err.Error(), "redis.host")
+	})
+}
+
+func (cts *ConfigTestsSuite) Test_Init_MemoryWithoutRedis(t provider.T) {
+	var (
+		dir string
+		cfg *config.Config
+		err error
+	)
+
+	t.Title("Test Init Memory Without Redis")
+	t.Description("The memory storage starts Redis in-process, so redis parameters are not required")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+		writeTestConfig(t, dir, testConfigYAML)
+		setTestEnv(t, map[string]string{"JWT_SIGNING_KEY": "secret", "APP_PORT": "8000"})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		cfg, err = config.Init(dir, []string{"--db-type", "memory"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().Nil(err)
+		t.Assert().Equal(config.DBTypeMemory, cfg.DBType)
+		t.Assert().Empty(cfg.Redis.Host)
+	})
+}
+
+func (cts *ConfigTestsSuite) Test_Init_ErrorUnknownKeys(t provider.T) {
+	var (
+		dir string
+		err error
+	)
+
+	t.Title("Test Init Error Unknown Keys")
+	t.Description("Misspelled or misplaced parameters are reported instead of being silently ignored")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+		writeTestConfig(t, dir, testConfigYAML+"passwordReset:\n  maxFailures: 10\nrateLimits:\n  enabled: true\n")
+		setTestEnv(t, testConfigEnv)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = config.Init(dir, []string{"--set", "db.tpye=mongo"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().ErrorIs(err, config.ErrInvalidConfig)
+		// viper приводит ключи к нижнему регистру
+		for _, field := range []string{"passwordreset.maxfailures", "ratelimits", "db.tpye"} {
+			t.Assert().Contains(err.Error(), field+": unknown parameter")
+		}
+	})
+}
+
+func (cts *ConfigTestsSuite) Test_Init_RepositoryConfig(t provider.T) {
+	var (
+		cfg *config.Config
+		err error
+	)
+
+	t.Title("Test Init Repository Config")
+	t.Description("configs/config.yml contains only known parameters and passes validation")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		setTestEnv(t, testConfigEnv)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		cfg, err = config.Init("../../../configs", []string{"--db-type", "postgres"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().Nil(err)
+		t.Assert().Equal(10, cfg.TwoFactor.MaxFailures)
+		t.Assert().Equal(15*time.Minute, cfg.TwoFactor.LockoutDuration)
+	})
+}
+
+func (cts *ConfigTestsSuite) Test_Watch_ReloadsValidChanges(t provider.T) {
+	var (
+		changes  = make(chan *config.Config, 10)
+		errs     = make(chan error, 10)
+		dir      string
+		reloaded *config.Config
+		rejected error
+	)
+
+	t.Title("Test Watch Reloads Valid Changes")
+	t.Description("A changed config file is reloaded and an invalid change is rejected")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+		writeTestConfig(t, dir, testConfigYAML)
+		setTestEnv(t, testConfigEnv)
+
+		loader, err := config.NewLoader(dir, nil)
+		t.Require().Nil(err)
+		loader.Watch(func(cfg *config.Config) { changes <- cfg }, func(err error) { errs <- err })
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		writeTestConfig(t, dir, testConfigYAML+"signInThrottle:\n  baseDelay: 2s\n")
+		select {
+		case reloaded = <-changes:
+		case <-time.After(5 * time.Second):
+		}
+
+		writeTestConfig(t, dir, testConfigYAML+"passwordReset:\n  maxAttempts: -1\n")
+		select {
+		case rejected = <-errs:
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().NotNil(reloaded)
+		t.Assert().Equal(2*time.Second, reloaded.SignInThrottle.BaseDelay)
+
+		t.Require().ErrorIs(rejected, config.ErrInvalidConfig)
+		t.Assert().Contains(rejected.Error(), "passwordReset.maxAttempts")
+	})
+}
+
+func TestConfigTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ConfigTestsSuite))
+}
@@ -0,0 +1,136 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/internal/favorite"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+type FavoriteBookServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (fbsts *FavoriteBookServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "favorite book service", "suite", "steps")
+}
+
+func (fbsts *FavoriteBookServiceTestsSuite) Test_GetByReaderID_Success(t provider.T) {
+	var (
+		favoriteBookService favorite.IFavoriteBookService
+		readerID            uuid.UUID
+		book                *models.BookModel
+		mock                sqlxmock.Sqlmock
+		books               []*models.BookModel
+		expectations        error
+		err                 error
+	)
+
+	t.Title("Test Get Favorite Books By Reader ID Success")
+	t.Description("Favorite books are selected together with the books in one query")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var db *sqlx.DB
+		db, mock, err = sqlxmock.Newx()
+		t.Require().Nil(err)
+		readerID = uuid.New()
+		book = tdbmodels.NewBookModelBuilder().Build()
+
+		// книги читаются тем же запросом, что и страница избранного
+		mock.ExpectQuery(`select b.id, b.title, .* from bs.favorite_books f join bs.book b on b.id = f.book_id`).
+			WithArgs(readerID, favorite.FavoriteBooksPageLimit, 0).
+			WillReturnRows(sqlxmock.NewRows([]string{
+				"id", "title", "author", "publisher", "copies_number", "rarity", "genre", "publishing_year", "language", "age_limit",
+			}).AddRow(
+				book.ID, book.Title, book.Author, book.Publisher, book.CopiesNumber,
+				book.Rarity, book.Genre, book.PublishingYear, book.Language, book.AgeLimit,
+			))
+
+		favoriteBookService = favorite.NewFavoriteBookService(favorite.NewPostgresRepo(db, logging.GetLoggerForTests()),
+			logging.GetLoggerForTests())
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		books, err = favoriteBookService.GetByReaderID(context.Background(), readerID, favorite.FavoriteBooksPageLimit, 0)
+		expectations = mock.ExpectationsWereMet()
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal([]*models.BookModel{book}, books)
+		t.Assert().Nil(expectations)
+	})
+}
+
+func (fbsts *FavoriteBookServiceTestsSuite) Test_GetByReaderID_ErrorFavoriteBookDoesNotExists(t provider.T) {
+	var (
+		favoriteBookService favorite.IFavoriteBookService
+		readerID            uuid.UUID
+		books               []*models.BookModel
+		err                 error
+	)
+
+	t.Title("Test Get Favorite Books By Reader ID Error: favorite book does not exists")
+	t.Description("The page past the last favorite book is not found")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockFavoriteBookRepo := mockrepo.NewMockIFavoriteBookRepo(ctrl)
+		favoriteBookService = favorite.NewFavoriteBookService(mockFavoriteBookRepo, logging.GetLoggerForTests())
+		readerID = uuid.New()
+
+		mockFavoriteBookRepo.EXPECT().GetByReaderID(gomock.Any(), readerID, favorite.FavoriteBooksPageLimit, favorite.FavoriteBooksPageLimit).
+			Return(nil, favorite.ErrFavoriteBookDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		books, err = favoriteBookService.GetByReaderID(context.Background(), readerID,
+			favorite.FavoriteBooksPageLimit, favorite.FavoriteBooksPageLimit)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(books)
+		t.Assert().ErrorIs(err, favorite.ErrFavoriteBookDoesNotExists)
+	})
+}
+
+func (fbsts *FavoriteBookServiceTestsSuite) Test_Delete_ErrorFavoriteBookDoesNotExists(t provider.T) {
+	var (
+		favoriteBookService favorite.IFavoriteBookService
+		readerID, bookID    uuid.UUID
+		err                 error
+	)
+
+	t.Title("Test Delete Favorite Book Error: favorite book does not exists")
+	t.Description("A book that is not in the reader's favorites was not deleted")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockFavoriteBookRepo := mockrepo.NewMockIFavoriteBookRepo(ctrl)
+		favoriteBookService = favorite.NewFavoriteBookService(mockFavoriteBookRepo, logging.GetLoggerForTests())
+		readerID, bookID = uuid.New(), uuid.New()
+
+		mockFavoriteBookRepo.EXPECT().Delete(gomock.Any(), readerID, bookID).Return(favorite.ErrFavoriteBookDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = favoriteBookService.Delete(context.Background(), readerID, bookID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, favorite.ErrFavoriteBookDoesNotExists)
+	})
+}
+
+func TestFavoriteBookServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(FavoriteBookServiceTestsSuite))
+}
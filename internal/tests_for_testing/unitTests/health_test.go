@@ -0,0 +1,153 @@
+package unitTests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/health"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type HealthTestsSuite struct {
+	suite.Suite
+}
+
+func (hts *HealthTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "health", "suite", "steps")
+}
+
+func (hts *HealthTestsSuite) Test_Readiness_AllUp(t provider.T) {
+	var (
+		router   *gin.Engine
+		response *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Readiness All Up")
+	t.Description("Readiness is ok when every dependency answers and reports per-dependency status")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+		checker := health.NewChecker(health.DefaultCheckTimeout)
+		checker.Add("redis", health.RedisCheck(client))
+		checker.Add("postgres", func(ctx context.Context) error { return nil })
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		health.NewHandler(checker).InitRoutes(router)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, response.Code)
+
+		var readinessDTO dto.ReadinessOutputDTO
+		t.Require().Nil(json.Unmarshal(response.Body.Bytes(), &readinessDTO))
+		t.Assert().Equal("ok", readinessDTO.Status)
+		t.Require().Len(readinessDTO.Checks, 2)
+		t.Assert().Equal(health.StatusUp, readinessDTO.Checks["redis"].Status)
+		t.Assert().Equal(health.StatusUp, readinessDTO.Checks["postgres"].Status)
+		t.Assert().Empty(readinessDTO.Checks["redis"].Error)
+	})
+}
+
+func (hts *HealthTestsSuite) Test_Readiness_DependencyDown(t provider.T) {
+	var (
+		router   *gin.Engine
+		response *httptest.ResponseRecorder
+		elapsed  time.Duration
+	)
+
+	t.Title("Test Readiness Dependency Down")
+	t.Description("A hanging or unreachable dependency makes the instance unavailable without blocking liveness")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		mr.Close()
+
+		checker := health.NewChecker(50 * time.Millisecond)
+		checker.Add("redis", health.RedisCheck(client))
+		checker.Add("mongo", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		health.NewHandler(checker).InitRoutes(router)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		start := time.Now()
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		elapsed = time.Since(start)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusServiceUnavailable, response.Code)
+		t.Assert().Less(elapsed, time.Second)
+
+		var readinessDTO dto.ReadinessOutputDTO
+		t.Require().Nil(json.Unmarshal(response.Body.Bytes(), &readinessDTO))
+		t.Assert().Equal("unavailable", readinessDTO.Status)
+		t.Assert().Equal(health.StatusDown, readinessDTO.Checks["redis"].Status)
+		t.Assert().Equal(health.StatusDown, readinessDTO.Checks["mongo"].Status)
+		t.Assert().NotEmpty(readinessDTO.Checks["mongo"].Error)
+		t.Assert().GreaterOrEqual(readinessDTO.Checks["mongo"].LatencyMs, float64(50))
+
+		liveness := httptest.NewRecorder()
+		router.ServeHTTP(liveness, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		t.Assert().Equal(http.StatusOK, liveness.Code)
+	})
+}
+
+func (hts *HealthTestsSuite) Test_Probe(t provider.T) {
+	var (
+		ready, unavailable *httptest.Server
+		readyErr, downErr  error
+	)
+
+	t.Title("Test Probe")
+	t.Description("The healthcheck command succeeds only when /readyz answers 200")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ready = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		unavailable = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		readyErr = health.Probe(context.Background(), ready.URL+"/readyz")
+		downErr = health.Probe(context.Background(), unavailable.URL+"/readyz")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		ready.Close()
+		unavailable.Close()
+
+		t.Assert().Nil(readyErr)
+		t.Assert().NotNil(downErr)
+	})
+}
+
+func TestHealthTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(HealthTestsSuite))
+}
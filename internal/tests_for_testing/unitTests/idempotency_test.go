@@ -0,0 +1,189 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/idempotency"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type IdempotencyTestsSuite struct {
+	suite.Suite
+}
+
+func (its *IdempotencyTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "idempotency", "suite", "steps")
+}
+
+const idempotencyTestPath = "/api/v1/readers/1/reservations"
+
+// newIdempotencyServer подключает роутер бронирования за middleware; statuses -
+// коды, которые обработчик вернет на очередные вызовы
+func newIdempotencyServer(t provider.T, calls *int, statuses ...int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/api/v1/readers/:id/reservations", func(c *gin.Context) {
+		status := statuses[*calls]
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	})
+
+	store := idempotency.NewRedisRecordStore(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), logging.GetLoggerForTests())
+
+	server := gin.New()
+	server.Use(idempotency.Middleware(store, middleware.NewRouteMatcher(router.Routes()), time.Hour, logging.GetLoggerForTests()))
+	server.NoRoute(gin.WrapH(router))
+
+	return server
+}
+
+func serveIdempotent(server *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, idempotencyTestPath, strings.NewReader(body))
+	request.Header.Set(idempotency.HeaderIdempotencyKey, key)
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	return response
+}
+
+func (its *IdempotencyTestsSuite) Test_Middleware_ReplaysFirstResponse(t provider.T) {
+	var (
+		server          *gin.Engine
+		calls           int
+		first, repeated *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Replays First Response")
+	t.Description("A retry with the same key and payload gets the stored response without calling the handler")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		server = newIdempotencyServer(t, &calls, http.StatusCreated, http.StatusCreated)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		first = serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+		repeated = serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(1, calls)
+		t.Assert().Equal(http.StatusCreated, first.Code)
+		t.Assert().Equal(http.StatusCreated, repeated.Code)
+		t.Assert().Equal(first.Body.String(), repeated.Body.String())
+		t.Assert().Equal(first.Header().Get("Content-Type"), repeated.Header().Get("Content-Type"))
+		t.Assert().Equal("true", repeated.Header().Get(idempotency.HeaderReplayed))
+	})
+}
+
+func (its *IdempotencyTestsSuite) Test_Middleware_RejectsDifferentPayload(t provider.T) {
+	var (
+		server    *gin.Engine
+		calls     int
+		different *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Rejects Different Payload")
+	t.Description("Reusing a key for a request with another payload fails with 422")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		server = newIdempotencyServer(t, &calls, http.StatusCreated, http.StatusCreated)
+		serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		different = serveIdempotent(server, "key-1", `{"book_id":"2"}`)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(1, calls)
+		t.Assert().Equal(http.StatusUnprocessableEntity, different.Code)
+	})
+}
+
+func (its *IdempotencyTestsSuite) Test_Middleware_RetriesAfterServerError(t provider.T) {
+	var (
+		server        *gin.Engine
+		calls         int
+		failed, retry *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Retries After Server Error")
+	t.Description("A 5xx response is not stored, so a retry with the same key reaches the handler")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		server = newIdempotencyServer(t, &calls, http.StatusInternalServerError, http.StatusCreated)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		failed = serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+		retry = serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(2, calls)
+		t.Assert().Equal(http.StatusInternalServerError, failed.Code)
+		t.Assert().Equal(http.StatusCreated, retry.Code)
+		t.Assert().Empty(retry.Header().Get(idempotency.HeaderReplayed))
+	})
+}
+
+func (its *IdempotencyTestsSuite) Test_Middleware_SavesAfterClientDisconnect(t provider.T) {
+	var (
+		server   *gin.Engine
+		calls    int
+		ctx      context.Context
+		cancel   context.CancelFunc
+		repeated *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Saves After Client Disconnect")
+	t.Description("The response is stored even if the client disconnected while the handler ran, so a retry is replayed")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		gin.SetMode(gin.TestMode)
+		ctx, cancel = context.WithCancel(context.Background())
+
+		// клиент отключается, пока обработчик создает бронь
+		router := gin.New()
+		router.POST("/api/v1/readers/:id/reservations", func(c *gin.Context) {
+			calls++
+			cancel()
+			c.JSON(http.StatusCreated, gin.H{"call": calls})
+		})
+
+		store := idempotency.NewRedisRecordStore(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), logging.GetLoggerForTests())
+		server = gin.New()
+		server.Use(idempotency.Middleware(store, middleware.NewRouteMatcher(router.Routes()), time.Hour, logging.GetLoggerForTests()))
+		server.NoRoute(gin.WrapH(router))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodPost, idempotencyTestPath, strings.NewReader(`{"book_id":"1"}`)).WithContext(ctx)
+		request.Header.Set(idempotency.HeaderIdempotencyKey, "key-1")
+		server.ServeHTTP(httptest.NewRecorder(), request)
+
+		repeated = serveIdempotent(server, "key-1", `{"book_id":"1"}`)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(1, calls)
+		t.Assert().Equal(http.StatusCreated, repeated.Code)
+		t.Assert().Equal("true", repeated.Header().Get(idempotency.HeaderReplayed))
+	})
+}
+
+func TestIdempotencyTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(IdempotencyTestsSuite))
+}
@@ -0,0 +1,234 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/inventory"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+type InventoryTestsSuite struct {
+	suite.Suite
+}
+
+func (its *InventoryTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "inventory", "suite", "steps")
+}
+
+// reserveCopy повторяет шаги сервиса бронирования: чтение книги и запись
+// с уменьшенным числом экземпляров в одной транзакции
+func reserveCopy(tm transact.ITransactionManager, repo intfRepo.IBookRepo, book *models.BookModel) error {
+	return tm.Do(context.Background(), func(ctx context.Context) error {
+		read, err := repo.GetByID(ctx, book.ID)
+		if err != nil {
+			return err
+		}
+
+		updated := *read
+		updated.CopiesNumber--
+
+		return repo.Update(ctx, &updated)
+	})
+}
+
+func newInventoryTransactionManager(ctrl *gomock.Controller) transact.ITransactionManager {
+	transactionManager := mockrepo.NewMockITransactionManager(ctrl)
+	transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	return inventory.NewTransactionManager(transactionManager)
+}
+
+func (its *InventoryTestsSuite) Test_BookRepo_TakesCopyAtomically(t provider.T) {
+	var (
+		tm           transact.ITransactionManager
+		repo         intfRepo.IBookRepo
+		book         *models.BookModel
+		mock         sqlxmock.Sqlmock
+		writes       int
+		expectations error
+		err          error
+	)
+
+	t.Title("Test Book Repo Takes Copy Atomically")
+	t.Description("Decreasing copies in a transaction becomes a conditional decrement that also bumps the version, and the book is not written again")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(1).Build()
+
+		var db *sqlx.DB
+		db, mock, err = sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectQuery(`update bs.book\s+set copies_number = copies_number \+ \$2, version = version \+ 1`).WithArgs(book.ID, -1).
+			WillReturnRows(sqlxmock.NewRows([]string{"copies_number", "version"}).AddRow(0, 3))
+
+		ctrl := gomock.NewController(t)
+		bookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil)
+		bookRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, updated *models.BookModel) error {
+				writes++
+				return nil
+			}).AnyTimes()
+
+		tm = newInventoryTransactionManager(ctrl)
+		repo = inventory.NewBookRepo(bookRepo, inventory.NewPostgresRepo(db, logging.GetLoggerForTests()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = reserveCopy(tm, repo, book)
+		expectations = mock.ExpectationsWereMet()
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(0, writes)
+		t.Assert().Nil(expectations)
+	})
+}
+
+func (its *InventoryTestsSuite) Test_BookRepo_WritesOtherFieldsAfterAdjust(t provider.T) {
+	var (
+		tm            transact.ITransactionManager
+		repo          intfRepo.IBookRepo
+		book, written *models.BookModel
+		err           error
+	)
+
+	t.Title("Test Book Repo Writes Other Fields After Adjust")
+	t.Description("When other fields change together with copies, the book is written with the adjusted copies and the version check accounts for the bump made by the adjustment")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(1).Build()
+
+		db, mock, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectQuery(`select id, version from bs.book`).WithArgs(book.ID).
+			WillReturnRows(sqlxmock.NewRows([]string{"id", "version"}).AddRow(book.ID, 2))
+		mock.ExpectQuery(`update bs.book\s+set copies_number`).WithArgs(book.ID, -1).
+			WillReturnRows(sqlxmock.NewRows([]string{"copies_number", "version"}).AddRow(0, 3))
+		mock.ExpectQuery(`update bs.book\s+set version`).WithArgs(book.ID, 3).
+			WillReturnRows(sqlxmock.NewRows([]string{"version"}).AddRow(4))
+
+		ctrl := gomock.NewController(t)
+		bookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil)
+		bookRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, updated *models.BookModel) error {
+				written = updated
+				return nil
+			})
+
+		tm = optimistic.NewTransactionManager(newInventoryTransactionManager(ctrl))
+		repo = inventory.NewBookRepo(
+			optimistic.NewBookRepo(bookRepo, optimistic.NewPostgresRepo(db, logging.GetLoggerForTests()), newPassThroughTransactionManager(ctrl)),
+			inventory.NewPostgresRepo(db, logging.GetLoggerForTests()),
+		)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = tm.Do(context.Background(), func(ctx context.Context) error {
+			read, err := repo.GetByID(ctx, book.ID)
+			if err != nil {
+				return err
+			}
+
+			updated := *read
+			updated.CopiesNumber--
+			updated.Title += " (2-е издание)"
+
+			return repo.Update(ctx, &updated)
+		})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(uint(0), written.CopiesNumber)
+		t.Assert().Equal(book.Title+" (2-е издание)", written.Title)
+	})
+}
+
+func (its *InventoryTestsSuite) Test_BookRepo_LastCopyAlreadyTaken(t provider.T) {
+	var (
+		tm   transact.ITransactionManager
+		repo intfRepo.IBookRepo
+		book *models.BookModel
+		err  error
+	)
+
+	t.Title("Test Book Repo Last Copy Already Taken")
+	t.Description("If another transaction took the last copy after the read, the update fails and the book is not written")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(1).Build()
+
+		db, mock, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectQuery(`update bs.book`).WithArgs(book.ID, -1).
+			WillReturnRows(sqlxmock.NewRows([]string{"copies_number", "version"}))
+
+		ctrl := gomock.NewController(t)
+		bookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil)
+
+		tm = newInventoryTransactionManager(ctrl)
+		repo = inventory.NewBookRepo(bookRepo, inventory.NewPostgresRepo(db, logging.GetLoggerForTests()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = reserveCopy(tm, repo, book)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, errs.ErrBookNoCopiesNum)
+	})
+}
+
+func (its *InventoryTestsSuite) Test_BookRepo_UpdatesAsIsOutsideTransaction(t provider.T) {
+	var (
+		repo intfRepo.IBookRepo
+		book *models.BookModel
+		err  error
+	)
+
+	t.Title("Test Book Repo Updates As Is Outside Transaction")
+	t.Description("An admin edit outside a transaction writes the copies number it was given")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithCopiesNumber(7).Build()
+
+		db, _, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+		bookRepo.EXPECT().Update(gomock.Any(), book).Return(nil)
+
+		repo = inventory.NewBookRepo(bookRepo, inventory.NewPostgresRepo(db, logging.GetLoggerForTests()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = repo.Update(context.Background(), book)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+	})
+}
+
+func TestInventoryTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(InventoryTestsSuite))
+}
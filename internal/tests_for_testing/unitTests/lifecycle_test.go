@@ -0,0 +1,161 @@
+package unitTests
+
+import (
+	"context"
+	"errors"
+	"github.com/nikitalystsev/BookSmart/pkg/lifecycle"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type LifecycleTestsSuite struct {
+	suite.Suite
+}
+
+func (lts *LifecycleTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "lifecycle", "suite", "steps")
+}
+
+func freeAddr(t provider.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	t.Require().Nil(err)
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func (lts *LifecycleTestsSuite) Test_ServeContext_DrainsAndStopsInOrder(t provider.T) {
+	var (
+		mu        sync.Mutex
+		events    []string
+		status    int
+		serveErr  error
+		requestIn = make(chan struct{})
+	)
+
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	t.Title("Test Serve Context Drains And Stops In Order")
+	t.Description("In-flight requests finish, then workers stop, then resources close in reverse order")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		lc := lifecycle.New(logging.GetLoggerForTests(), 5*time.Second)
+		lc.OnStop("redis client", func(context.Context) error { record("redis closed"); return nil })
+		lc.OnStop("postgres pool", func(context.Context) error { record("postgres closed"); return nil })
+		lc.Go("worker", func(ctx context.Context) {
+			<-ctx.Done()
+			record("worker stopped")
+		})
+
+		server := &http.Server{
+			Addr: freeAddr(t),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(requestIn)
+				time.Sleep(100 * time.Millisecond)
+				record("request finished")
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			serveErr = lc.ServeContext(ctx, server)
+			close(done)
+		}()
+
+		responses := make(chan int, 1)
+		go func() {
+			for {
+				resp, err := http.Get("http://" + server.Addr)
+				if err == nil {
+					resp.Body.Close()
+					responses <- resp.StatusCode
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+
+		<-requestIn
+		cancel()
+		status = <-responses
+		<-done
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(serveErr)
+		t.Assert().Equal(http.StatusOK, status)
+		t.Assert().Equal([]string{"request finished", "worker stopped", "postgres closed", "redis closed"}, events)
+	})
+}
+
+func (lts *LifecycleTestsSuite) Test_Stop_JoinsErrors(t provider.T) {
+	var (
+		err    error
+		closed []string
+	)
+
+	t.Title("Test Stop Joins Errors")
+	t.Description("A failing resource does not prevent the others from closing")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		lc := lifecycle.New(logging.GetLoggerForTests(), time.Second)
+		lc.OnStop("first", func(context.Context) error { closed = append(closed, "first"); return nil })
+		lc.OnStop("second", func(context.Context) error { return errors.New("close error") })
+		err = lc.Stop(context.Background())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().NotNil(err)
+		t.Assert().Equal([]string{"first"}, closed)
+	})
+}
+
+func (lts *LifecycleTestsSuite) Test_Retry(t provider.T) {
+	var (
+		calls, exhaustedCalls int
+		err, exhaustedErr     error
+	)
+
+	t.Title("Test Retry")
+	t.Description("Dependencies are retried with backoff until they answer or attempts run out")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		policy := lifecycle.RetryPolicy{Attempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+		err = lifecycle.Retry(context.Background(), policy, logging.GetLoggerForTests(), "postgres", func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		exhaustedErr = lifecycle.Retry(context.Background(), policy, logging.GetLoggerForTests(), "redis", func(ctx context.Context) error {
+			exhaustedCalls++
+			return errors.New("connection refused")
+		})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(3, calls)
+		t.Assert().EqualError(exhaustedErr, "connection refused")
+		t.Assert().Equal(3, exhaustedCalls)
+	})
+}
+
+func TestLifecycleTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(LifecycleTestsSuite))
+}
@@ -0,0 +1,136 @@
+package unitTests
+
+import (
+	"context"
+	"errors"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type MemoryStoreTestsSuite struct {
+	suite.Suite
+}
+
+func (mts *MemoryStoreTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "memory", "suite", "steps")
+}
+
+func (mts *MemoryStoreTestsSuite) Test_TransactionManager_RollsBackOnError(t provider.T) {
+	var (
+		trm      *manager.Manager
+		bookRepo intfRepo.IBookRepo
+		book     *models.BookModel
+		txErr    error
+		err      error
+	)
+
+	t.Title("Test Transaction Manager Rolls Back On Error")
+	t.Description("Changes made in a failed transaction are not visible after it")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		bookRepo = memory.NewBookRepo(store, logging.GetLoggerForTests())
+		book = tdbmodels.NewBookModelBuilder().Build()
+
+		trm, err = manager.New(store.TrFactory)
+		t.Require().Nil(err)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		txErr = trm.Do(context.Background(), func(ctx context.Context) error {
+			if err := bookRepo.Create(ctx, book); err != nil {
+				return err
+			}
+			return errors.New("reservation failed")
+		})
+		_, err = bookRepo.GetByID(context.Background(), book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().EqualError(txErr, "reservation failed")
+		t.Assert().ErrorIs(err, errs.ErrBookDoesNotExists)
+	})
+}
+
+func (mts *MemoryStoreTestsSuite) Test_Store_SnapshotRoundTrip(t provider.T) {
+	var (
+		restoredRepo intfRepo.IBookRepo
+		book         *models.BookModel
+		restored     *models.BookModel
+		err          error
+	)
+
+	t.Title("Test Store Snapshot Round Trip")
+	t.Description("Data saved to a snapshot is available after loading it into a new store")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+		book = tdbmodels.NewBookModelBuilder().Build()
+
+		store := memory.NewStore(logging.GetLoggerForTests())
+		t.Require().Nil(memory.NewBookRepo(store, logging.GetLoggerForTests()).Create(context.Background(), book))
+		t.Require().Nil(store.Save(context.Background(), path))
+
+		restoredStore := memory.NewStore(logging.GetLoggerForTests())
+		t.Require().Nil(restoredStore.Load(context.Background(), path))
+		restoredRepo = memory.NewBookRepo(restoredStore, logging.GetLoggerForTests())
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		restored, err = restoredRepo.GetByID(context.Background(), book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(book, restored)
+	})
+}
+
+func (mts *MemoryStoreTestsSuite) Test_ReservationRepo_ReturnsOverdueAsExpired(t provider.T) {
+	var (
+		reservationRepo intfRepo.IReservationRepo
+		reservation     *models.ReservationModel
+		expired         []*models.ReservationModel
+		err             error
+	)
+
+	t.Title("Test Reservation Repo Returns Overdue As Expired")
+	t.Description("An issued reservation past its return date is read as expired, like in reservation_view")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		reservationRepo = memory.NewReservationRepo(store, logging.GetLoggerForTests())
+		reservation = tdbmodels.NewReservationModelBuilder().
+			WithIssueDate(time.Now().AddDate(0, 0, -20)).
+			WithState(impl.ReservationIssued).Build()
+		reservation.ReturnDate = time.Now().AddDate(0, 0, -6)
+
+		t.Require().Nil(reservationRepo.Create(context.Background(), reservation))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		expired, err = reservationRepo.GetExpiredByReaderID(context.Background(), reservation.ReaderID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Require().Len(expired, 1)
+		t.Assert().Equal(reservation.ID, expired[0].ID)
+		t.Assert().Equal(impl.ReservationExpired, expired[0].State)
+	})
+}
+
+func TestMemoryStoreTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(MemoryStoreTestsSuite))
+}
@@ -0,0 +1,171 @@
+package unitTests
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/impl"
+	"github.com/nikitalystsev/BookSmart/internal/metrics"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MetricsTestsSuite struct {
+	suite.Suite
+}
+
+func (mts *MetricsTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "metrics", "suite", "steps")
+}
+
+func (mts *MetricsTestsSuite) Test_RouteMatcher_Match(t provider.T) {
+	var (
+		matcher *middleware.RouteMatcher
+		routes  []string
+	)
+
+	t.Title("Test Route Matcher Match")
+	t.Description("Paths are reduced to route templates, static segments win over parameters")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		matcher = middleware.NewRouteMatcher(gin.RoutesInfo{
+			{Method: http.MethodGet, Path: "/api/v1/books/:id"},
+			{Method: http.MethodGet, Path: "/api/v1/books/similar"},
+			{Method: http.MethodGet, Path: "/api/v1/readers/:id/reading_lists/:list_id"},
+		})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		routes = []string{
+			matcher.Match(http.MethodGet, "/api/v1/books/7f1c2f5e-53b7-4bd7-8f4a-0a54b3b7e2d1"),
+			matcher.Match(http.MethodGet, "/api/v1/books/similar"),
+			matcher.Match(http.MethodGet, "/api/v1/readers/1/reading_lists/2"),
+			matcher.Match(http.MethodPost, "/api/v1/books/1"),
+			matcher.Match(http.MethodGet, "/api/v1/books/1/extra"),
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal([]string{
+			"/api/v1/books/:id",
+			"/api/v1/books/similar",
+			"/api/v1/readers/:id/reading_lists/:list_id",
+			"unmatched",
+			"unmatched",
+		}, routes)
+	})
+}
+
+func (mts *MetricsTestsSuite) Test_ReservationRepo_BusinessCounters(t provider.T) {
+	var (
+		registry        *prometheus.Registry
+		reservationRepo *mockrepo.MockIReservationRepo
+		err             error
+	)
+
+	t.Title("Test Reservation Repo Business Counters")
+	t.Description("Successful creations and extensions are counted, failed calls are not")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		registry = prometheus.NewRegistry()
+		reservationRepo = mockrepo.NewMockIReservationRepo(gomock.NewController(t))
+		reservationRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		reservationRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+		reservationRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		repo := metrics.NewReservationRepo(reservationRepo, metrics.New(registry), "postgres")
+		reservation := tdbmodels.NewReservationModelBuilder().Build()
+
+		_ = repo.Create(context.Background(), reservation)
+		err = repo.Create(context.Background(), reservation)
+
+		reservation.State = impl.ReservationExtended
+		_ = repo.Update(context.Background(), reservation)
+		reservation.State = impl.ReservationClosed
+		_ = repo.Update(context.Background(), reservation)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().NotNil(err)
+		t.Assert().Nil(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP booksmart_reservations_created_total Number of created reservations.
+# TYPE booksmart_reservations_created_total counter
+booksmart_reservations_created_total 1
+# HELP booksmart_reservations_extended_total Number of reservation extensions.
+# TYPE booksmart_reservations_extended_total counter
+booksmart_reservations_extended_total 1
+`), "booksmart_reservations_created_total", "booksmart_reservations_extended_total"))
+		t.Assert().Equal(3, testutil.CollectAndCount(registry, "booksmart_repo_call_duration_seconds"))
+	})
+}
+
+func (mts *MetricsTestsSuite) Test_HTTPMiddleware_SignIns(t provider.T) {
+	var (
+		registry *prometheus.Registry
+		router   *gin.Engine
+	)
+
+	t.Title("Test HTTP Middleware Sign-Ins")
+	t.Description("Requests are observed per route template, sign-in and second factor outcomes are counted")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		registry = prometheus.NewRegistry()
+		appMetrics := metrics.New(registry)
+
+		gin.SetMode(gin.TestMode)
+		inner := gin.New()
+		inner.POST("/api/v1/auth/sign-in", func(c *gin.Context) {
+			if c.Query("ok") != "" {
+				c.Status(http.StatusOK)
+				return
+			}
+			c.Status(http.StatusConflict)
+		})
+		inner.POST("/api/v1/auth/sign-in/2fa", func(c *gin.Context) {
+			if c.Query("ok") != "" {
+				c.Status(http.StatusOK)
+				return
+			}
+			c.Status(http.StatusUnauthorized)
+		})
+
+		router = gin.New()
+		router.Use(appMetrics.HTTPMiddleware(middleware.NewRouteMatcher(inner.Routes())))
+		router.NoRoute(gin.WrapH(inner))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		for _, target := range []string{
+			"/api/v1/auth/sign-in?ok=1", "/api/v1/auth/sign-in", "/api/v1/auth/sign-in",
+			"/api/v1/auth/sign-in/2fa", "/api/v1/auth/sign-in/2fa?ok=1",
+		} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, target, nil))
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP booksmart_sign_ins_total Number of sign-in attempts by result.
+# TYPE booksmart_sign_ins_total counter
+booksmart_sign_ins_total{result="failure"} 3
+booksmart_sign_ins_total{result="success"} 2
+`), "booksmart_sign_ins_total"))
+		t.Assert().Equal(4, testutil.CollectAndCount(registry, "booksmart_http_request_duration_seconds"))
+	})
+}
+
+func TestMetricsTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(MetricsTestsSuite))
+}
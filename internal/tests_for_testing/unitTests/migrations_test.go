@@ -0,0 +1,76 @@
+package unitTests
+
+import (
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/nikitalystsev/BookSmart/migrations"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+type MigrationsTestsSuite struct {
+	suite.Suite
+}
+
+func (mts *MigrationsTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "migrations", "suite", "steps")
+}
+
+func (mts *MigrationsTestsSuite) Test_Migrations_VersionsMatchAcrossBackends(t provider.T) {
+	var (
+		postgresFS, mongoFS             fs.FS
+		postgresVersions, mongoVersions []uint
+		postgresErr, mongoErr           error
+	)
+
+	t.Title("Test Migrations Versions Match Across Backends")
+	t.Description("Postgres and Mongo embedded migrations have the same versions, so Check compares them with the same latest version")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		postgresFS = migrations.Postgres
+		mongoFS = migrations.Mongo
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		postgresVersions, postgresErr = migrationVersions(postgresFS, "postgres")
+		mongoVersions, mongoErr = migrationVersions(mongoFS, "mongo")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(postgresErr)
+		t.Assert().Nil(mongoErr)
+		t.Assert().NotEmpty(postgresVersions)
+		t.Assert().Equal(postgresVersions, mongoVersions)
+	})
+}
+
+func migrationVersions(fsys fs.FS, dir string) ([]uint, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{version}
+	for {
+		version, err = src.Next(version)
+		if os.IsNotExist(err) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
+
+func TestMigrationsTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(MigrationsTestsSuite))
+}
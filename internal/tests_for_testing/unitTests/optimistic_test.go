@@ -0,0 +1,166 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type OptimisticTestsSuite struct {
+	suite.Suite
+}
+
+func (ots *OptimisticTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "optimistic", "suite", "steps")
+}
+
+// newStaleBookRepo возвращает репозиторий книг, в котором версия book
+// меняется между чтением и записью
+func newStaleBookRepo(t provider.T, book *models.BookModel) intfRepo.IBookRepo {
+	db, mock, err := sqlxmock.Newx()
+	t.Require().Nil(err)
+	mock.ExpectQuery(`select id, version from bs.book`).WithArgs(book.ID).
+		WillReturnRows(sqlxmock.NewRows([]string{"id", "version"}).AddRow(book.ID, 2))
+	mock.ExpectQuery(`update bs.book`).WithArgs(book.ID, 2).
+		WillReturnRows(sqlxmock.NewRows([]string{"version"}))
+
+	ctrl := gomock.NewController(t)
+	bookRepo := mockrepo.NewMockIBookRepo(ctrl)
+	bookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil)
+
+	return optimistic.NewBookRepo(bookRepo, optimistic.NewPostgresRepo(db, logging.GetLoggerForTests()),
+		newPassThroughTransactionManager(ctrl))
+}
+
+func newPassThroughTransactionManager(ctrl *gomock.Controller) transact.ITransactionManager {
+	transactionManager := mockrepo.NewMockITransactionManager(ctrl)
+	transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+
+	return transactionManager
+}
+
+// editBook повторяет шаги правки книги: чтение и запись с новым названием
+func editBook(ctx context.Context, repo intfRepo.IBookRepo, book *models.BookModel) error {
+	read, err := repo.GetByID(ctx, book.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := *read
+	updated.Title += " (2-е издание)"
+
+	return repo.Update(ctx, &updated)
+}
+
+func (ots *OptimisticTestsSuite) Test_BookRepo_RejectsStaleUpdate(t provider.T) {
+	var (
+		repo intfRepo.IBookRepo
+		book *models.BookModel
+		ctx  context.Context
+		err  error
+	)
+
+	t.Title("Test Book Repo Rejects Stale Update")
+	t.Description("An update of a book modified after it was read fails and the book is not written")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().Build()
+		repo = newStaleBookRepo(t, book)
+		ctx = optimistic.WithTracking(context.Background())
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = editBook(ctx, repo, book)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, optimistic.ErrConcurrentModification)
+		t.Assert().True(optimistic.Conflicted(ctx))
+	})
+}
+
+func (ots *OptimisticTestsSuite) Test_TransactionManager_RetriesOnConflict(t provider.T) {
+	var (
+		tm       transact.ITransactionManager
+		attempts int
+		err      error
+	)
+
+	t.Title("Test Transaction Manager Retries On Conflict")
+	t.Description("A transaction that failed with a version conflict is run again and its result is returned")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		tm = optimistic.NewTransactionManager(newPassThroughTransactionManager(gomock.NewController(t)))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = tm.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return optimistic.ErrConcurrentModification
+			}
+			return nil
+		})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(2, attempts)
+	})
+}
+
+func (ots *OptimisticTestsSuite) Test_Middleware_RespondsConflict(t provider.T) {
+	var (
+		server   *gin.Engine
+		response *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Responds Conflict")
+	t.Description("A handler error caused by a version conflict is replaced with 409")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book := tdbmodels.NewBookModelBuilder().Build()
+		repo := newStaleBookRepo(t, book)
+
+		gin.SetMode(gin.TestMode)
+		server = gin.New()
+		server.Use(optimistic.Middleware())
+		server.PUT("/api/v1/books/:id", func(c *gin.Context) {
+			if err := editBook(c.Request.Context(), repo, book); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error()})
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, httptest.NewRequest(http.MethodPut, "/api/v1/books/1", nil))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusConflict, response.Code)
+		t.Assert().Contains(response.Body.String(), optimistic.ErrMsgConcurrentModification)
+	})
+}
+
+func TestOptimisticTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(OptimisticTestsSuite))
+}
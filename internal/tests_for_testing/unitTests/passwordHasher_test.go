@@ -0,0 +1,183 @@
+package unitTests
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/passwordhash"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"strings"
+	"testing"
+)
+
+type PasswordHasherTestsSuite struct {
+	suite.Suite
+}
+
+func (phts *PasswordHasherTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "password hasher", "suite", "steps")
+}
+
+// argon2 с минимальными параметрами, чтобы тесты не тратили время
+var testArgon2Params = passwordhash.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func newPasswordHasher(t provider.T, params passwordhash.Params) (*passwordhash.Hasher, *mockrepo.MockIPasswordHasher) {
+	legacy := mockrepo.NewMockIPasswordHasher(gomock.NewController(t))
+
+	hasher, err := passwordhash.NewHasher(params, legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hasher, legacy
+}
+
+func (phts *PasswordHasherTestsSuite) Test_Hash_PerUserSalt(t provider.T) {
+	var (
+		hasher       *passwordhash.Hasher
+		first        string
+		second       string
+		errFirst     error
+		errSecond    error
+		errCompare   error
+		needsRehash  bool
+		passwordText = "password00"
+	)
+
+	t.Title("Test Hash Password With Per-User Salt")
+	t.Description("The same password gives different argon2id hashes that both verify")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		hasher, _ = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		first, errFirst = hasher.Hash(passwordText)
+		second, errSecond = hasher.Hash(passwordText)
+		errCompare = hasher.Compare(first, passwordText)
+		needsRehash = hasher.NeedsRehash(first)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(errFirst)
+		t.Assert().Nil(errSecond)
+		t.Assert().True(strings.HasPrefix(first, "$argon2id$v=19$m=1024,t=1,p=1$"))
+		t.Assert().NotEqual(first, second)
+		t.Assert().Nil(errCompare)
+		t.Assert().False(needsRehash)
+	})
+}
+
+func (phts *PasswordHasherTestsSuite) Test_Compare_ErrorMismatchedPassword(t provider.T) {
+	var (
+		hasher *passwordhash.Hasher
+		hashed string
+		err    error
+	)
+
+	t.Title("Test Compare Password Error Mismatched Password")
+	t.Description("A wrong password is rejected and the hash is not upgraded")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		hasher, _ = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Bcrypt, BcryptCost: 4})
+		hashed, err = hasher.Hash("password00")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = hasher.Compare(hashed, "password01")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, passwordhash.ErrMismatchedPassword)
+	})
+}
+
+func (phts *PasswordHasherTestsSuite) Test_Compare_LegacyHash(t provider.T) {
+	var (
+		hasher      *passwordhash.Hasher
+		legacy      *mockrepo.MockIPasswordHasher
+		needsRehash bool
+		err         error
+	)
+
+	t.Title("Test Compare Password Legacy Hash")
+	t.Description("A legacy hash is verified by the old hasher and reported as needing a rehash")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		hasher, legacy = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params})
+		legacy.EXPECT().Compare("legacyhash", "password00").Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = hasher.Compare("legacyhash", "password00")
+		needsRehash = hasher.NeedsRehash("legacyhash")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().True(needsRehash)
+	})
+}
+
+func (phts *PasswordHasherTestsSuite) Test_Compare_ErrorLegacyMismatch(t provider.T) {
+	var (
+		hasher *passwordhash.Hasher
+		legacy *mockrepo.MockIPasswordHasher
+		err    error
+	)
+
+	t.Title("Test Compare Password Error Legacy Mismatch")
+	t.Description("A wrong password for a legacy hash is rejected without rehashing")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		hasher, legacy = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params})
+		legacy.EXPECT().Compare("legacyhash", "password01").Return(errors.New("mismatch"))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = hasher.Compare("legacyhash", "password01")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().NotNil(err)
+	})
+}
+
+func (phts *PasswordHasherTestsSuite) Test_NeedsRehash_ChangedAlgorithm(t provider.T) {
+	var (
+		bcryptHasher *passwordhash.Hasher
+		argonHasher  *passwordhash.Hasher
+		hashed       string
+		needsRehash  bool
+		err          error
+	)
+
+	t.Title("Test Needs Rehash Changed Algorithm")
+	t.Description("A bcrypt hash is upgraded once argon2id becomes the configured algorithm")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		bcryptHasher, _ = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Bcrypt, BcryptCost: 4})
+		argonHasher, _ = newPasswordHasher(t, passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params})
+		hashed, err = bcryptHasher.Hash("password00")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		needsRehash = argonHasher.NeedsRehash(hashed)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().True(needsRehash)
+		t.Assert().False(bcryptHasher.NeedsRehash(hashed))
+	})
+}
+
+func TestPasswordHasherTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(PasswordHasherTestsSuite))
+}
@@ -0,0 +1,114 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/passwordhash"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type PasswordRehasherTestsSuite struct {
+	suite.Suite
+}
+
+func (prts *PasswordRehasherTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "password rehasher", "suite", "steps")
+}
+
+const testSignInBody = `{"phone_number": "89999999999", "password": "password00"}`
+
+// signInWithStatus возвращает обработчик входа, отвечающий кодом status
+func signInWithStatus(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	})
+}
+
+func (prts *PasswordRehasherTestsSuite) Test_Wrap_RehashesSignedInReader(t provider.T) {
+	var (
+		rehasher         *account.PasswordRehasher
+		hasher           *passwordhash.Hasher
+		reader           *models.ReaderModel
+		rehashedReaderID uuid.UUID
+		oldHash, newHash string
+		recorder         *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Password Rehasher: signed in reader is rehashed")
+	t.Description("After a successful sign-in only the signed in reader's legacy hash is replaced, guarded by the old hash")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		legacy := mockrepo.NewMockIPasswordHasher(ctrl)
+		mockReaderRepo := mockrepo.NewMockIReaderRepo(ctrl)
+		mockAccountRepo := mockrepo.NewMockIAccountRepo(ctrl)
+		hasher, _ = passwordhash.NewHasher(passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params}, legacy)
+		rehasher = account.NewPasswordRehasher(mockReaderRepo, mockAccountRepo, hasher, logging.GetLoggerForTests())
+		reader = tdbmodels.NewReaderModelBuilder().WithPassword("legacyhash").Build()
+		recorder = httptest.NewRecorder()
+
+		mockReaderRepo.EXPECT().GetByPhoneNumber(gomock.Any(), "89999999999").Return(reader, nil)
+		legacy.EXPECT().Compare("legacyhash", "password00").Return(nil)
+		mockAccountRepo.EXPECT().ReplacePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, readerID uuid.UUID, replacedHash, replacementHash string) error {
+				rehashedReaderID, oldHash, newHash = readerID, replacedHash, replacementHash
+				return nil
+			})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sign-in", strings.NewReader(testSignInBody))
+		rehasher.Wrap(signInWithStatus(http.StatusOK)).ServeHTTP(recorder, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, recorder.Code)
+		t.Assert().Equal(reader.ID, rehashedReaderID)
+		t.Assert().Equal("legacyhash", oldHash)
+		t.Assert().True(strings.HasPrefix(newHash, "$argon2id$"))
+		t.Assert().Nil(hasher.Compare(newHash, "password00"))
+	})
+}
+
+func (prts *PasswordRehasherTestsSuite) Test_Wrap_FailedSignInIsNotRehashed(t provider.T) {
+	var (
+		rehasher *account.PasswordRehasher
+		recorder *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Password Rehasher: failed sign-in")
+	t.Description("A failed sign-in neither looks up the reader nor touches the hash")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		hasher, _ := passwordhash.NewHasher(passwordhash.Params{Algorithm: passwordhash.Argon2id, Argon2: testArgon2Params},
+			mockrepo.NewMockIPasswordHasher(ctrl))
+		rehasher = account.NewPasswordRehasher(mockrepo.NewMockIReaderRepo(ctrl), mockrepo.NewMockIAccountRepo(ctrl),
+			hasher, logging.GetLoggerForTests())
+		recorder = httptest.NewRecorder()
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sign-in", strings.NewReader(testSignInBody))
+		rehasher.Wrap(signInWithStatus(http.StatusNotFound)).ServeHTTP(recorder, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestPasswordRehasherTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(PasswordRehasherTestsSuite))
+}
@@ -0,0 +1,207 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/passwordreset"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type PasswordResetServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (prsts *PasswordResetServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "password reset service", "suite", "steps")
+}
+
+type passwordResetServiceMocks struct {
+	codeStore        *mockrepo.MockICodeStore
+	readerRepo       *mockrepo.MockIReaderRepo
+	accountRepo      *mockrepo.MockIAccountRepo
+	refreshTokenRepo *mockrepo.MockIRefreshTokenRepo
+	hasher           *mockrepo.MockIPasswordHasher
+	sender           *mockrepo.MockISender
+}
+
+func newPasswordResetService(t provider.T) (passwordreset.IPasswordResetService, *passwordResetServiceMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := &passwordResetServiceMocks{
+		codeStore:        mockrepo.NewMockICodeStore(ctrl),
+		readerRepo:       mockrepo.NewMockIReaderRepo(ctrl),
+		accountRepo:      mockrepo.NewMockIAccountRepo(ctrl),
+		refreshTokenRepo: mockrepo.NewMockIRefreshTokenRepo(ctrl),
+		hasher:           mockrepo.NewMockIPasswordHasher(ctrl),
+		sender:           mockrepo.NewMockISender(ctrl),
+	}
+
+	passwordResetService := passwordreset.NewPasswordResetService(
+		mocks.codeStore,
+		mocks.readerRepo,
+		mocks.accountRepo,
+		mocks.refreshTokenRepo,
+		mocks.hasher,
+		mocks.sender,
+		logging.GetLoggerForTests(),
+		passwordreset.DefaultCodeTTL,
+		passwordreset.DefaultMaxAttempts,
+		passwordreset.DefaultResendInterval,
+	)
+
+	return passwordResetService, mocks
+}
+
+func (prsts *PasswordResetServiceTestsSuite) Test_RequestCode_Success(t provider.T) {
+	var (
+		passwordResetService passwordreset.IPasswordResetService
+		reader               *models.ReaderModel
+		message              string
+		err                  error
+	)
+
+	t.Title("Test Request Password Reset Code Success")
+	t.Description("A one-time code was generated, stored and sent to the reader")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *passwordResetServiceMocks
+		passwordResetService, mocks = newPasswordResetService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+
+		mocks.codeStore.EXPECT().LockResend(gomock.Any(), reader.PhoneNumber, passwordreset.DefaultResendInterval).Return(true, nil)
+		mocks.readerRepo.EXPECT().GetByPhoneNumber(gomock.Any(), reader.PhoneNumber).Return(reader, nil)
+		mocks.codeStore.EXPECT().Save(gomock.Any(), reader.PhoneNumber, gomock.Any(), passwordreset.DefaultCodeTTL).Return(nil)
+		mocks.sender.EXPECT().Send(gomock.Any(), reader.PhoneNumber, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, phoneNumber, text string) error {
+				message = text
+				return nil
+			})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = passwordResetService.RequestCode(context.Background(), reader.PhoneNumber)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Regexp(regexp.MustCompile(`\d{6}`), message)
+	})
+}
+
+func (prsts *PasswordResetServiceTestsSuite) Test_RequestCode_UnknownPhoneNumber(t provider.T) {
+	var (
+		passwordResetService passwordreset.IPasswordResetService
+		err                  error
+	)
+
+	t.Title("Test Request Password Reset Code: unknown phone number")
+	t.Description("No code is sent for an unknown phone number, but no error is returned")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *passwordResetServiceMocks
+		passwordResetService, mocks = newPasswordResetService(t)
+
+		mocks.codeStore.EXPECT().LockResend(gomock.Any(), "89990001122", gomock.Any()).Return(true, nil)
+		mocks.readerRepo.EXPECT().GetByPhoneNumber(gomock.Any(), "89990001122").Return(nil, errs.ErrReaderDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = passwordResetService.RequestCode(context.Background(), "89990001122")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+	})
+}
+
+func (prsts *PasswordResetServiceTestsSuite) Test_Reset_ErrorResetAttemptsExceeded(t provider.T) {
+	var (
+		passwordResetService passwordreset.IPasswordResetService
+		err                  error
+	)
+
+	t.Title("Test Reset Password Error: attempts exceeded")
+	t.Description("The last allowed wrong code removes the stored code")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *passwordResetServiceMocks
+		passwordResetService, mocks = newPasswordResetService(t)
+
+		mocks.codeStore.EXPECT().Attempt(gomock.Any(), "89990001122").
+			Return(&passwordreset.CodeModel{CodeHash: "hash", Attempts: passwordreset.DefaultMaxAttempts}, nil)
+		mocks.codeStore.EXPECT().Delete(gomock.Any(), "89990001122").Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = passwordResetService.Reset(context.Background(), "89990001122", "000000", "newPassword12")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(passwordreset.ErrResetAttemptsExceeded, err)
+	})
+}
+
+func (prsts *PasswordResetServiceTestsSuite) Test_Reset_Success(t provider.T) {
+	var (
+		passwordResetService passwordreset.IPasswordResetService
+		reader               *models.ReaderModel
+		code                 string
+		err                  error
+	)
+
+	t.Title("Test Reset Password Success")
+	t.Description("The code sent to the reader sets the new password and revokes refresh tokens")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mocks *passwordResetServiceMocks
+		passwordResetService, mocks = newPasswordResetService(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+
+		var stored *passwordreset.CodeModel
+		mocks.codeStore.EXPECT().LockResend(gomock.Any(), reader.PhoneNumber, gomock.Any()).Return(true, nil)
+		mocks.readerRepo.EXPECT().GetByPhoneNumber(gomock.Any(), reader.PhoneNumber).Return(reader, nil).Times(2)
+		mocks.codeStore.EXPECT().Save(gomock.Any(), reader.PhoneNumber, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, phoneNumber string, c *passwordreset.CodeModel, ttl time.Duration) error {
+				stored = c
+				return nil
+			})
+		mocks.sender.EXPECT().Send(gomock.Any(), reader.PhoneNumber, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, phoneNumber, text string) error {
+				code = regexp.MustCompile(`\d{6}`).FindString(text)
+				return nil
+			})
+		if err = passwordResetService.RequestCode(context.Background(), reader.PhoneNumber); err != nil {
+			t.Fatal(err)
+		}
+
+		mocks.codeStore.EXPECT().Attempt(gomock.Any(), reader.PhoneNumber).
+			DoAndReturn(func(ctx context.Context, phoneNumber string) (*passwordreset.CodeModel, error) {
+				return &passwordreset.CodeModel{CodeHash: stored.CodeHash, Attempts: stored.Attempts + 1}, nil
+			})
+		mocks.codeStore.EXPECT().Delete(gomock.Any(), reader.PhoneNumber).Return(nil)
+		mocks.hasher.EXPECT().Hash("newPassword12").Return("newHash", nil)
+		mocks.accountRepo.EXPECT().Update(gomock.Any(), reader).Return(nil)
+		mocks.refreshTokenRepo.EXPECT().DeleteByReaderID(gomock.Any(), reader.ID).Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = passwordResetService.Reset(context.Background(), reader.PhoneNumber, code, "newPassword12")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("newHash", reader.Password)
+	})
+}
+
+func TestPasswordResetServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(PasswordResetServiceTestsSuite))
+}
@@ -0,0 +1,197 @@
+package unitTests
+
+import (
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/ratelimit"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type RateLimitTestsSuite struct {
+	suite.Suite
+}
+
+func (rlts *RateLimitTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "rate limit", "suite", "steps")
+}
+
+var testRateLimitPolicy = &ratelimit.Policy{
+	Default: ratelimit.Limit{Rate: 1, Burst: 3},
+	Roles:   map[string]ratelimit.Limit{"librarian": {Rate: 10, Burst: 30}},
+	Rules: []ratelimit.Rule{
+		{Prefix: "/api/v1/auth", Limit: ratelimit.Limit{Rate: 1, Burst: 2}},
+		{
+			Prefix: "/api/v1/books",
+			Limit:  ratelimit.Limit{Rate: 5, Burst: 10},
+			Roles:  map[string]ratelimit.Limit{"librarian": {Rate: 20, Burst: 50}},
+		},
+	},
+}
+
+func newRateLimitedRouter(t provider.T) *gin.Engine {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	limiter := ratelimit.NewRedisLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+	tokenManager := mockrepo.NewMockITokenManager(gomock.NewController(t))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.IdentifyClient(tokenManager),
+		ratelimit.Middleware(limiter, ratelimit.NewPolicyStore(testRateLimitPolicy), logging.GetLoggerForTests()))
+	router.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+func (rlts *RateLimitTestsSuite) Test_Resolve_GroupAndRole(t provider.T) {
+	var (
+		groups []string
+		limits []ratelimit.Limit
+	)
+
+	t.Title("Test Resolve Rate Limit Group And Role")
+	t.Description("The longest matching group wins, and a role limit inside the group overrides the group limit")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		for _, request := range []struct{ path, role string }{
+			{"/api/v1/books/123", "Librarian"},
+			{"/api/v1/books/123", "Reader"},
+			{"/api/v1/auth/sign-in", "Librarian"},
+			{"/api/v1/readers/123", "Librarian"},
+			{"/api/v1/readers/123", ""},
+		} {
+			group, limit := testRateLimitPolicy.Resolve(request.path, request.role)
+			groups = append(groups, group)
+			limits = append(limits, limit)
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal([]string{"/api/v1/books", "/api/v1/books", "/api/v1/auth", "default", "default"}, groups)
+		t.Assert().Equal([]ratelimit.Limit{
+			{Rate: 20, Burst: 50},
+			{Rate: 5, Burst: 10},
+			{Rate: 1, Burst: 2},
+			{Rate: 10, Burst: 30},
+			{Rate: 1, Burst: 3},
+		}, limits)
+	})
+}
+
+func (rlts *RateLimitTestsSuite) Test_Middleware_Headers(t provider.T) {
+	var (
+		router   *gin.Engine
+		recorder *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Rate Limit Middleware Headers")
+	t.Description("An allowed request carries RateLimit headers with the remaining budget")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		router = newRateLimitedRouter(t)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/books", nil))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, recorder.Code)
+		t.Assert().Equal("10", recorder.Header().Get("RateLimit-Limit"))
+		t.Assert().Equal("9", recorder.Header().Get("RateLimit-Remaining"))
+		t.Assert().Equal("10;w=2", recorder.Header().Get("RateLimit-Policy"))
+	})
+}
+
+func (rlts *RateLimitTestsSuite) Test_Middleware_ErrorLimitExceeded(t provider.T) {
+	var (
+		router   *gin.Engine
+		recorder *httptest.ResponseRecorder
+		response dto.ErrorResponse
+	)
+
+	t.Title("Test Rate Limit Middleware Error Limit Exceeded")
+	t.Description("A request beyond the burst is rejected with 429, Retry-After and an error body")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		router = newRateLimitedRouter(t)
+		for i := 0; i < 2; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/auth/sign-in", nil))
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/auth/sign-in", nil))
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusTooManyRequests, recorder.Code)
+		t.Assert().Equal("1", recorder.Header().Get("Retry-After"))
+		t.Assert().Equal("0", recorder.Header().Get("RateLimit-Remaining"))
+		t.Assert().Equal(ratelimit.ErrMsgRateLimitExceeded, response.ErrorMsg)
+	})
+}
+
+func (rlts *RateLimitTestsSuite) Test_Middleware_ParsesTokenOnce(t provider.T) {
+	var (
+		router   *gin.Engine
+		parses   int
+		recorder *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Rate Limit Middleware Parses Token Once")
+	t.Description("The access token is parsed once per request and the reader role is shared with logging and rate limiting")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		tokenManager := mockrepo.NewMockITokenManager(gomock.NewController(t))
+		tokenManager.EXPECT().Parse("token").DoAndReturn(func(string) (string, string, error) {
+			parses++
+			return "7f1c2f5e-53b7-4bd7-8f4a-0a54b3b7e2d1", "Librarian", nil
+		}).AnyTimes()
+
+		limiter := ratelimit.NewRedisLimiter(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), logging.GetLoggerForTests())
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(
+			middleware.IdentifyClient(tokenManager),
+			middleware.RequestID(middleware.NewRouteMatcher(nil), logging.GetLoggerForTests()),
+			ratelimit.Middleware(limiter, ratelimit.NewPolicyStore(testRateLimitPolicy), logging.GetLoggerForTests()),
+		)
+		router.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+		request.Header.Set("Authorization", "Bearer token")
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(1, parses)
+		t.Assert().Equal("50", recorder.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestRateLimitTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(RateLimitTestsSuite))
+}
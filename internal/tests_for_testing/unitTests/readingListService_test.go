@@ -0,0 +1,242 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/readinglist"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"testing"
+	"time"
+)
+
+type ReadingListServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (rlsts *ReadingListServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "reading list service", "suite", "steps")
+}
+
+func newReadingList(readerID uuid.UUID, bookIDs ...uuid.UUID) *readinglist.ReadingListModel {
+	return &readinglist.ReadingListModel{
+		ID:        uuid.New(),
+		ReaderID:  readerID,
+		Name:      "to read",
+		BookIDs:   bookIDs,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (rlsts *ReadingListServiceTestsSuite) Test_Create_Success(t provider.T) {
+	var (
+		readingListService readinglist.IReadingListService
+		readingList        *readinglist.ReadingListModel
+		readerID           uuid.UUID
+		err                error
+	)
+
+	t.Title("Test Create Reading List Success")
+	t.Description("The new reading list was successfully created")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockReadingListRepo := mockrepo.NewMockIReadingListRepo(ctrl)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockTransactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		readingListService = readinglist.NewReadingListService(
+			mockReadingListRepo,
+			mockBookRepo,
+			mockTransactionManager,
+			logging.GetLoggerForTests(),
+		)
+		readerID = uuid.New()
+
+		mockTransactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+		mockReadingListRepo.EXPECT().GetByReaderID(gomock.Any(), readerID).Return(nil, readinglist.ErrReadingListDoesNotExists)
+		mockReadingListRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		readingList, err = readingListService.Create(context.Background(), readerID, " summer 2026 ")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("summer 2026", readingList.Name)
+		t.Assert().Equal(readerID, readingList.ReaderID)
+	})
+}
+
+func (rlsts *ReadingListServiceTestsSuite) Test_Create_ErrorReadingListAlreadyExist(t provider.T) {
+	var (
+		readingListService readinglist.IReadingListService
+		readerID           uuid.UUID
+		err                error
+	)
+
+	t.Title("Test Create Reading List Error: reading list already exists")
+	t.Description("The new reading list was not created because the reader already has a list with this name")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockReadingListRepo := mockrepo.NewMockIReadingListRepo(ctrl)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockTransactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		readingListService = readinglist.NewReadingListService(
+			mockReadingListRepo,
+			mockBookRepo,
+			mockTransactionManager,
+			logging.GetLoggerForTests(),
+		)
+		readerID = uuid.New()
+
+		mockTransactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+		mockReadingListRepo.EXPECT().GetByReaderID(gomock.Any(), readerID).
+			Return([]*readinglist.ReadingListModel{newReadingList(readerID)}, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = readingListService.Create(context.Background(), readerID, "To Read")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(readinglist.ErrReadingListAlreadyExist, err)
+	})
+}
+
+func (rlsts *ReadingListServiceTestsSuite) Test_AddBook_ErrorBookDoesNotExists(t provider.T) {
+	var (
+		readingListService readinglist.IReadingListService
+		readingList        *readinglist.ReadingListModel
+		bookID             uuid.UUID
+		err                error
+	)
+
+	t.Title("Test Add Book To Reading List Error: book does not exists")
+	t.Description("The book was not added to the reading list because it does not exist")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockReadingListRepo := mockrepo.NewMockIReadingListRepo(ctrl)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockTransactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		readingListService = readinglist.NewReadingListService(
+			mockReadingListRepo,
+			mockBookRepo,
+			mockTransactionManager,
+			logging.GetLoggerForTests(),
+		)
+		readingList = newReadingList(uuid.New())
+		bookID = uuid.New()
+
+		mockTransactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+		mockReadingListRepo.EXPECT().GetByID(gomock.Any(), readingList.ID).Return(readingList, nil)
+		mockBookRepo.EXPECT().GetByID(gomock.Any(), bookID).Return(nil, errs.ErrBookDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = readingListService.AddBook(context.Background(), readingList.ReaderID, readingList.ID, bookID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(errs.ErrBookDoesNotExists, err)
+	})
+}
+
+func (rlsts *ReadingListServiceTestsSuite) Test_Reorder_Success(t provider.T) {
+	var (
+		readingListService readinglist.IReadingListService
+		readingList        *readinglist.ReadingListModel
+		first, second      uuid.UUID
+		err                error
+	)
+
+	t.Title("Test Reorder Reading List Success")
+	t.Description("The books of the reading list were successfully reordered")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockReadingListRepo := mockrepo.NewMockIReadingListRepo(ctrl)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockTransactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		readingListService = readinglist.NewReadingListService(
+			mockReadingListRepo,
+			mockBookRepo,
+			mockTransactionManager,
+			logging.GetLoggerForTests(),
+		)
+		first, second = uuid.New(), uuid.New()
+		readingList = newReadingList(uuid.New(), first, second)
+
+		mockTransactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+		mockReadingListRepo.EXPECT().GetByID(gomock.Any(), readingList.ID).Return(readingList, nil)
+		mockReadingListRepo.EXPECT().Update(gomock.Any(), readingList).Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = readingListService.Reorder(context.Background(), readingList.ReaderID, readingList.ID,
+			[]uuid.UUID{second, first})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal([]uuid.UUID{second, first}, readingList.BookIDs)
+	})
+}
+
+func (rlsts *ReadingListServiceTestsSuite) Test_Share_ErrorReadingListOfAnotherReader(t provider.T) {
+	var (
+		readingListService readinglist.IReadingListService
+		readingList        *readinglist.ReadingListModel
+		shareToken         string
+		err                error
+	)
+
+	t.Title("Test Share Reading List Error: reading list of another reader")
+	t.Description("The reading list was not shared because it belongs to another reader")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockReadingListRepo := mockrepo.NewMockIReadingListRepo(ctrl)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockTransactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		readingListService = readinglist.NewReadingListService(
+			mockReadingListRepo,
+			mockBookRepo,
+			mockTransactionManager,
+			logging.GetLoggerForTests(),
+		)
+		readingList = newReadingList(uuid.New())
+
+		mockTransactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+		mockReadingListRepo.EXPECT().GetByID(gomock.Any(), readingList.ID).Return(readingList, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		shareToken, err = readingListService.Share(context.Background(), uuid.New(), readingList.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Empty(shareToken)
+		t.Assert().Equal(readinglist.ErrReadingListDoesNotExists, err)
+	})
+}
+
+func TestReadingListServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ReadingListServiceTestsSuite))
+}
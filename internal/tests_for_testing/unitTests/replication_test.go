@@ -0,0 +1,231 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	"github.com/nikitalystsev/BookSmart/internal/replication"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type ReplicationTestsSuite struct {
+	suite.Suite
+}
+
+func (rts *ReplicationTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "replication", "suite", "steps")
+}
+
+// newReplicaRouter возвращает роутер с одной репликой, отставание которой
+// при проверке равно lagSeconds
+func newReplicaRouter(t provider.T, lagSeconds float64) *replication.Router {
+	replicaDB, mock, err := sqlxmock.Newx()
+	t.Require().Nil(err)
+	mock.ExpectQuery("pg_last_wal_replay_lsn").WillReturnRows(sqlxmock.NewRows([]string{"lag"}).AddRow(lagSeconds))
+
+	router := replication.NewRouter([]*sqlx.DB{replicaDB}, time.Second, logging.GetLoggerForTests())
+	router.CheckLag(context.Background())
+
+	return router
+}
+
+func (rts *ReplicationTestsSuite) Test_BookRepo_ReadsFromReplica(t provider.T) {
+	var (
+		repo                          intfRepo.IBookRepo
+		book                          *models.BookModel
+		ctx                           context.Context
+		readErr, updateErr, pinnedErr error
+	)
+
+	t.Title("Test Book Repo Reads From Replica")
+	t.Description("Reads go to an up-to-date replica until the request writes, then to the primary")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		primary, replica := mockrepo.NewMockIBookRepo(ctrl), mockrepo.NewMockIBookRepo(ctrl)
+		book = tdbmodels.NewBookModelBuilder().Build()
+
+		gomock.InOrder(
+			replica.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil),
+			primary.EXPECT().Update(gomock.Any(), book).Return(nil),
+			primary.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil),
+		)
+
+		repo = replication.NewBookRepo(newReplicaRouter(t, 0.2), primary, replica)
+		ctx = replication.WithPin(context.Background(), false)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, readErr = repo.GetByID(ctx, book.ID)
+		updateErr = repo.Update(ctx, book)
+		_, pinnedErr = repo.GetByID(ctx, book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(readErr)
+		t.Assert().Nil(updateErr)
+		t.Assert().Nil(pinnedErr)
+		t.Assert().True(replication.Written(ctx))
+	})
+}
+
+func (rts *ReplicationTestsSuite) Test_BookRepo_FallsBackToPrimary(t provider.T) {
+	var (
+		laggingRepo, repo intfRepo.IBookRepo
+		trm               *manager.Manager
+		laggingErr, txErr error
+	)
+
+	t.Title("Test Book Repo Falls Back To Primary")
+	t.Description("A lagging replica is skipped and reads inside a transaction stay on the primary")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		primary, replica := mockrepo.NewMockIBookRepo(ctrl), mockrepo.NewMockIBookRepo(ctrl)
+		primary.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+		laggingRepo = replication.NewBookRepo(newReplicaRouter(t, 5), primary, replica)
+		repo = replication.NewBookRepo(newReplicaRouter(t, 0), primary, replica)
+
+		primaryDB, mock, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		trm, err = manager.New(trmsqlx.NewDefaultFactory(primaryDB))
+		t.Require().Nil(err)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, laggingErr = laggingRepo.GetByID(context.Background(), uuid.New())
+		txErr = trm.Do(context.Background(), func(ctx context.Context) error {
+			_, err := repo.GetByID(ctx, uuid.New())
+			return err
+		})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(laggingErr)
+		t.Assert().Nil(txErr)
+	})
+}
+
+func (rts *ReplicationTestsSuite) Test_DeletedBookRepo_SearchesReplica(t provider.T) {
+	var (
+		repo                              intfRepo.IBookRepo
+		primaryBook, replicaBook          *models.BookModel
+		ctx                               context.Context
+		replicaBooks, primaryBooks        []*models.BookModel
+		replicaErr, deleteErr, primaryErr error
+	)
+
+	t.Title("Test Deleted Book Repo Searches Replica")
+	t.Description("The search without deleted books reads an up-to-date replica until the request deletes a book")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		primaryStore, replicaStore := memory.NewStore(logging.GetLoggerForTests()), memory.NewStore(logging.GetLoggerForTests())
+		primaryBook = tdbmodels.NewBookModelBuilder().WithTitle("Бесы").Build()
+		replicaBook = tdbmodels.NewBookModelBuilder().WithTitle("Война и мир").Build()
+		t.Require().Nil(memory.NewBookRepo(primaryStore, logging.GetLoggerForTests()).Create(context.Background(), primaryBook))
+		t.Require().Nil(memory.NewBookRepo(replicaStore, logging.GetLoggerForTests()).Create(context.Background(), replicaBook))
+
+		deletedBookRepo := replication.NewDeletedBookRepo(newReplicaRouter(t, 0),
+			memory.NewDeletedBookRepo(primaryStore, logging.GetLoggerForTests()),
+			memory.NewDeletedBookRepo(replicaStore, logging.GetLoggerForTests()))
+		repo = softdelete.NewBookRepo(memory.NewBookRepo(primaryStore, logging.GetLoggerForTests()), deletedBookRepo,
+			memory.NewVersionRepo(primaryStore, logging.GetLoggerForTests()))
+		ctx = replication.WithPin(context.Background(), false)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		replicaBooks, replicaErr = repo.GetByParams(ctx, &dto.BookParamsDTO{})
+		deleteErr = repo.Delete(ctx, primaryBook.ID)
+		primaryBooks, primaryErr = repo.GetByParams(ctx, &dto.BookParamsDTO{})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(replicaErr)
+		t.Assert().Nil(deleteErr)
+		t.Assert().Equal([]*models.BookModel{replicaBook}, replicaBooks)
+		t.Assert().ErrorIs(primaryErr, errs.ErrBookDoesNotExists)
+		t.Assert().Empty(primaryBooks)
+	})
+}
+
+func (rts *ReplicationTestsSuite) Test_Middleware_PinsClientAfterWrite(t provider.T) {
+	var (
+		mr       *miniredis.Miniredis
+		router   *gin.Engine
+		statuses []int
+	)
+
+	t.Title("Test Middleware Pins Client After Write")
+	t.Description("After a write the client's next requests read from the primary until stickiness expires")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		mr = miniredis.RunT(t)
+		store := replication.NewRedisPinStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+
+		ctrl := gomock.NewController(t)
+		primary, replica := mockrepo.NewMockIBookRepo(ctrl), mockrepo.NewMockIBookRepo(ctrl)
+		gomock.InOrder(
+			primary.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+			primary.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, nil),
+			replica.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, nil),
+		)
+		repo := replication.NewBookRepo(newReplicaRouter(t, 0), primary, replica)
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(replication.Middleware(store, 5*time.Second))
+		router.PUT("/books/:id", func(c *gin.Context) {
+			_ = repo.Update(c.Request.Context(), tdbmodels.NewBookModelBuilder().Build())
+			c.Status(http.StatusOK)
+		})
+		router.GET("/books/:id", func(c *gin.Context) {
+			_, _ = repo.GetByID(c.Request.Context(), uuid.New())
+			c.Status(http.StatusOK)
+		})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		serve := func(method string) {
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, httptest.NewRequest(method, "/books/1", nil))
+			statuses = append(statuses, response.Code)
+		}
+
+		serve(http.MethodPut)
+		serve(http.MethodGet)
+		mr.FastForward(5 * time.Second)
+		serve(http.MethodGet)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal([]int{http.StatusOK, http.StatusOK, http.StatusOK}, statuses)
+	})
+}
+
+func TestReplicationTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ReplicationTestsSuite))
+}
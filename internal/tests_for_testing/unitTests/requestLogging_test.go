@@ -0,0 +1,185 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type RequestLoggingTestsSuite struct {
+	suite.Suite
+}
+
+func (rlts *RequestLoggingTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "logging", "suite", "steps")
+}
+
+func newRequestLoggingRouter(t provider.T, hook **test.Hook) *gin.Engine {
+	testLogger, testHook := test.NewNullLogger()
+	*hook = testHook
+	logger := logrus.NewEntry(testLogger)
+
+	tokenManager := mockrepo.NewMockITokenManager(gomock.NewController(t))
+	tokenManager.EXPECT().Parse("valid").Return("7f1c2f5e-53b7-4bd7-8f4a-0a54b3b7e2d1", "Reader", nil).AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	inner := gin.New()
+	inner.GET("/api/v1/books/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context(), logger).Info("getting book")
+		c.Status(http.StatusOK)
+	})
+
+	router := gin.New()
+	router.Use(middleware.IdentifyClient(tokenManager), middleware.RequestID(middleware.NewRouteMatcher(inner.Routes()), logger))
+	router.NoRoute(gin.WrapH(inner))
+
+	return router
+}
+
+func (rlts *RequestLoggingTestsSuite) Test_RequestID_Propagated(t provider.T) {
+	var (
+		hook     *test.Hook
+		router   *gin.Engine
+		response *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Request ID Propagated")
+	t.Description("An incoming X-Request-ID is returned and added with the reader and route to every log entry")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		router = newRequestLoggingRouter(t, &hook)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/books/1", nil)
+		request.Header.Set("X-Request-ID", "req-42")
+		request.Header.Set("Authorization", "Bearer valid")
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal("req-42", response.Header().Get("X-Request-ID"))
+
+		entries := hook.AllEntries()
+		t.Require().Len(entries, 2)
+		for _, entry := range entries {
+			t.Assert().Equal("req-42", entry.Data["request_id"])
+			t.Assert().Equal("7f1c2f5e-53b7-4bd7-8f4a-0a54b3b7e2d1", entry.Data["reader_id"])
+			t.Assert().Equal("/api/v1/books/:id", entry.Data["route"])
+		}
+		t.Assert().Equal("request completed", entries[1].Message)
+		t.Assert().Equal(http.StatusOK, entries[1].Data["status"])
+	})
+}
+
+func (rlts *RequestLoggingTestsSuite) Test_RequestID_Generated(t provider.T) {
+	var (
+		hook     *test.Hook
+		router   *gin.Engine
+		response *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Request ID Generated")
+	t.Description("A missing or unsafe X-Request-ID is replaced with a new one, anonymous requests have no reader")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		router = newRequestLoggingRouter(t, &hook)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/books/1", nil)
+		request.Header.Set("X-Request-ID", "bad\nid")
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		requestID := response.Header().Get("X-Request-ID")
+		t.Assert().Len(requestID, 36)
+
+		entry := hook.LastEntry()
+		t.Require().NotNil(entry)
+		t.Assert().Equal(requestID, entry.Data["request_id"])
+		t.Assert().NotContains(entry.Data, "reader_id")
+	})
+}
+
+func (rlts *RequestLoggingTestsSuite) Test_WithFields_Merges(t provider.T) {
+	var entry *logrus.Entry
+
+	t.Title("Test With Fields Merges")
+	t.Description("Fields added later are merged with the request fields and a context without fields keeps the logger")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		ctx := logging.WithFields(context.Background(), logrus.Fields{"request_id": "1", "route": "/a"})
+		ctx = logging.WithFields(ctx, logrus.Fields{"route": "/b"})
+		entry = logging.FromContext(ctx, logging.GetLoggerForTests())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(logrus.Fields{"request_id": "1", "route": "/b"}, entry.Data)
+
+		logger := logging.GetLoggerForTests()
+		t.Assert().Same(logger, logging.FromContext(context.Background(), logger))
+	})
+}
+
+func (rlts *RequestLoggingTestsSuite) Test_RotatingFile_RotatesBySize(t provider.T) {
+	var (
+		dir     string
+		backups []string
+		err     error
+	)
+
+	t.Title("Test Rotating File Rotates By Size")
+	t.Description("The file is rotated when the next entry would exceed max size and old backups are removed")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		dir = t.TempDir()
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		var file *logging.RotatingFile
+		file, err = logging.NewRotatingFile(filepath.Join(dir, "all.log"), 10, 0, 2)
+		t.Require().Nil(err)
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err = file.Write([]byte(line))
+			t.Require().Nil(err)
+		}
+
+		backups, err = file.Backups()
+		_ = file.Close()
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Len(backups, 2)
+
+		current, readErr := os.ReadFile(filepath.Join(dir, "all.log"))
+		t.Assert().Nil(readErr)
+		t.Assert().Equal("fourth\n", string(current))
+
+		for _, backup := range backups {
+			t.Assert().True(strings.HasPrefix(filepath.Base(backup), "all-"))
+		}
+	})
+}
+
+func TestRequestLoggingTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(RequestLoggingTestsSuite))
+}
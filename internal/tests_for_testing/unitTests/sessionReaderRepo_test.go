@@ -0,0 +1,120 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"testing"
+	"time"
+)
+
+type SessionReaderRepoTestsSuite struct {
+	suite.Suite
+}
+
+func (srrts *SessionReaderRepoTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "session reader repo", "suite", "steps")
+}
+
+func newSessionReaderRepo(t provider.T) (intfRepo.IReaderRepo, *session.RedisStore, *mockrepo.MockIReaderRepo) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	store := session.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+	mockReaderRepo := mockrepo.NewMockIReaderRepo(gomock.NewController(t))
+
+	return session.NewReaderRepo(mockReaderRepo, store, time.Hour, logging.GetLoggerForTests()), store, mockReaderRepo
+}
+
+func (srrts *SessionReaderRepoTestsSuite) Test_SaveRefreshToken_MultipleSessions(t provider.T) {
+	var (
+		readerRepo intfRepo.IReaderRepo
+		store      *session.RedisStore
+		reader     *models.ReaderModel
+		sessions   []*session.SessionModel
+		err        error
+	)
+
+	t.Title("Test Save Refresh Token: multiple sessions")
+	t.Description("A second sign-in creates a second session instead of replacing the first one")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		readerRepo, store, _ = newSessionReaderRepo(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		if err = readerRepo.SaveRefreshToken(context.Background(), reader.ID, "first", time.Hour); err != nil {
+			return
+		}
+		if err = readerRepo.SaveRefreshToken(context.Background(), reader.ID, "second", time.Hour); err != nil {
+			return
+		}
+		sessions, err = store.GetByReaderID(context.Background(), reader.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Len(sessions, 2)
+	})
+}
+
+func (srrts *SessionReaderRepoTestsSuite) Test_GetByRefreshToken_ErrorRefreshTokenReused(t provider.T) {
+	var (
+		readerRepo intfRepo.IReaderRepo
+		store      *session.RedisStore
+		reader     *models.ReaderModel
+		err        error
+	)
+
+	t.Title("Test Get Reader By Refresh Token Error: refresh token reused")
+	t.Description("Reusing a rotated refresh token revokes the whole session")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mockReaderRepo *mockrepo.MockIReaderRepo
+		readerRepo, store, mockReaderRepo = newSessionReaderRepo(t)
+		reader = tdbmodels.NewReaderModelBuilder().Build()
+
+		mockReaderRepo.EXPECT().GetByID(gomock.Any(), reader.ID).Return(reader, nil)
+
+		if err = readerRepo.SaveRefreshToken(context.Background(), reader.ID, "first", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+
+		refreshCtx := session.NewContext(context.Background(), &session.RequestInfo{})
+		if _, err = readerRepo.GetByRefreshToken(refreshCtx, "first"); err != nil {
+			t.Fatal(err)
+		}
+		if err = readerRepo.SaveRefreshToken(refreshCtx, reader.ID, "second", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = readerRepo.GetByRefreshToken(context.Background(), "first")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(errs.ErrReaderDoesNotExists, err)
+
+		_, err = store.GetByReaderID(context.Background(), reader.ID)
+		t.Assert().Equal(session.ErrSessionDoesNotExists, err)
+	})
+}
+
+func TestSessionReaderRepoTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SessionReaderRepoTestsSuite))
+}
@@ -0,0 +1,188 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"testing"
+	"time"
+)
+
+type SessionServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (ssts *SessionServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "session service", "suite", "steps")
+}
+
+func newSessionStore(t provider.T) (*session.RedisStore, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	return session.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests()), mr
+}
+
+func (ssts *SessionServiceTestsSuite) Test_Revoke_DeniesLastAccessToken(t provider.T) {
+	var (
+		sessionService   session.ISessionService
+		store            *session.RedisStore
+		readerID         uuid.UUID
+		sessionModel     *session.SessionModel
+		expiresAt        time.Time
+		revokedTokenID   string
+		revokedExpiresAt time.Time
+		err, getErr      error
+	)
+
+	t.Title("Test Revoke Session: last access token is denied")
+	t.Description("Revoking a session from another device denies the access token issued to it, as logout does")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockAccessTokenRevoker := mockrepo.NewMockIAccessTokenRevoker(ctrl)
+		store, _ = newSessionStore(t)
+		sessionService = session.NewSessionService(store, mockAccessTokenRevoker, logging.GetLoggerForTests())
+		readerID = uuid.New()
+		expiresAt = time.Now().Add(15 * time.Minute).Truncate(time.Second)
+		sessionModel = &session.SessionModel{ID: uuid.New(), ReaderID: readerID, CreatedAt: time.Now(), LastUsedAt: time.Now()}
+
+		if err = store.Create(context.Background(), sessionModel, "refresh", time.Hour); err != nil {
+			t.Fatalf("an error '%s' was not expected when creating session", err)
+		}
+		if err = store.SetAccessToken(context.Background(), "refresh", "jti", expiresAt); err != nil {
+			t.Fatalf("an error '%s' was not expected when saving access token", err)
+		}
+
+		mockAccessTokenRevoker.EXPECT().RevokeID(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tokenID string, tokenExpiresAt time.Time) error {
+				revokedTokenID, revokedExpiresAt = tokenID, tokenExpiresAt
+				return nil
+			})
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = sessionService.Revoke(context.Background(), readerID, sessionModel.ID)
+		_, getErr = store.GetByID(context.Background(), sessionModel.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().ErrorIs(getErr, session.ErrSessionDoesNotExists)
+		t.Assert().Equal("jti", revokedTokenID)
+		t.Assert().True(expiresAt.Equal(revokedExpiresAt))
+	})
+}
+
+func (ssts *SessionServiceTestsSuite) Test_Revoke_ErrorSessionDoesNotExists(t provider.T) {
+	var (
+		sessionService session.ISessionService
+		store          *session.RedisStore
+		sessionModel   *session.SessionModel
+		err            error
+	)
+
+	t.Title("Test Revoke Session Error: session does not exists")
+	t.Description("A reader cannot revoke another reader's session")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockAccessTokenRevoker := mockrepo.NewMockIAccessTokenRevoker(ctrl)
+		store, _ = newSessionStore(t)
+		sessionService = session.NewSessionService(store, mockAccessTokenRevoker, logging.GetLoggerForTests())
+		sessionModel = &session.SessionModel{ID: uuid.New(), ReaderID: uuid.New(), CreatedAt: time.Now(), LastUsedAt: time.Now()}
+
+		if err = store.Create(context.Background(), sessionModel, "refresh", time.Hour); err != nil {
+			t.Fatalf("an error '%s' was not expected when creating session", err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = sessionService.Revoke(context.Background(), uuid.New(), sessionModel.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, session.ErrSessionDoesNotExists)
+	})
+}
+
+func (ssts *SessionServiceTestsSuite) Test_Create_ReaderIndexExpires(t provider.T) {
+	var (
+		store        *session.RedisStore
+		mr           *miniredis.Miniredis
+		sessionModel *session.SessionModel
+		ttl          time.Duration
+		err          error
+	)
+
+	t.Title("Test Create Session: reader index expires")
+	t.Description("The reader's session index gets the refresh token TTL and does not outlive the sessions")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store, mr = newSessionStore(t)
+		sessionModel = &session.SessionModel{ID: uuid.New(), ReaderID: uuid.New(), CreatedAt: time.Now(), LastUsedAt: time.Now()}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = store.Create(context.Background(), sessionModel, "refresh", time.Hour)
+		ttl = mr.TTL("session:reader:" + sessionModel.ReaderID.String())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(time.Hour, ttl)
+	})
+}
+
+func (ssts *SessionServiceTestsSuite) Test_Consume_ReusedTokenDeniesAccessToken(t provider.T) {
+	var (
+		store        *session.RedisStore
+		denylist     token.IDenylist
+		sessionModel *session.SessionModel
+		denied       bool
+		err, getErr  error
+	)
+
+	t.Title("Test Consume Refresh Token: reuse denies access token")
+	t.Description("A reused refresh token ends the session and denies its last access token")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var mr *miniredis.Miniredis
+		store, mr = newSessionStore(t)
+		denylist = token.NewRedisDenylist(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+		sessionModel = &session.SessionModel{ID: uuid.New(), ReaderID: uuid.New(), CreatedAt: time.Now(), LastUsedAt: time.Now()}
+
+		t.Require().Nil(store.Create(context.Background(), sessionModel, "refresh", time.Hour))
+		t.Require().Nil(store.SetAccessToken(context.Background(), "refresh", "jti", time.Now().Add(15*time.Minute)))
+		// первое погашение - обычное обновление токенов
+		_, err = store.Consume(context.Background(), "refresh", time.Hour)
+		t.Require().Nil(err)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = store.Consume(context.Background(), "refresh", time.Hour)
+		_, getErr = store.GetByID(context.Background(), sessionModel.ID)
+		denied, _ = denylist.IsDenied(context.Background(), "jti", sessionModel.ReaderID.String(), time.Now())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, session.ErrRefreshTokenReused)
+		t.Assert().ErrorIs(getErr, session.ErrSessionDoesNotExists)
+		t.Assert().True(denied)
+	})
+}
+
+func TestSessionServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SessionServiceTestsSuite))
+}
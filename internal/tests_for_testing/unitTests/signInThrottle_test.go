@@ -0,0 +1,318 @@
+package unitTests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/internal/signinthrottle"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SignInThrottleTestsSuite struct {
+	suite.Suite
+}
+
+func (sits *SignInThrottleTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "sign-in throttle", "suite", "steps")
+}
+
+var testSignInPolicy = signinthrottle.Policy{
+	FailureWindow:   15 * time.Minute,
+	BaseDelay:       time.Second,
+	MaxDelay:        4 * time.Second,
+	LockoutDuration: 15 * time.Minute,
+	Phone:           signinthrottle.Limits{FreeAttempts: 2, MaxFailures: 6},
+	IP:              signinthrottle.Limits{FreeAttempts: 10, MaxFailures: 50},
+}
+
+func newSignInThrottle(t provider.T) (*signinthrottle.Throttle, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	store := signinthrottle.NewRedisAttemptStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+
+	return signinthrottle.NewThrottle(store, testSignInPolicy, logging.GetLoggerForTests()), mr
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Failure_EscalatingDelay(t provider.T) {
+	var (
+		throttle *signinthrottle.Throttle
+		mr       *miniredis.Miniredis
+		delays   []time.Duration
+		ctx      = context.Background()
+	)
+
+	t.Title("Test Sign-In Failure Escalating Delay")
+	t.Description("After the free attempts each failure doubles the delay up to the maximum, then the account is locked")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, mr = newSignInThrottle(t)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		for i := 0; i < 6; i++ {
+			if err := throttle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+			retryAfter, err := throttle.Check(ctx, "89998887766", "10.0.0.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			delays = append(delays, retryAfter)
+			mr.FastForward(retryAfter)
+		}
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal([]time.Duration{
+			0, 0, time.Second, 2 * time.Second, 4 * time.Second, 15 * time.Minute,
+		}, delays)
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Failure_LockoutWritesAudit(t provider.T) {
+	var (
+		throttle *signinthrottle.Throttle
+		mr       *miniredis.Miniredis
+		entries  []miniredis.StreamEntry
+		err      error
+		ctx      = context.Background()
+	)
+
+	t.Title("Test Sign-In Failure Lockout Writes Audit")
+	t.Description("Locking the account adds exactly one record to the audit stream")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, mr = newSignInThrottle(t)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		for i := 0; i < testSignInPolicy.Phone.MaxFailures+1; i++ {
+			if err = throttle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		entries, err = mr.Stream(signinthrottle.AuditStreamKey)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Require().Len(entries, 1)
+		t.Assert().Subset(entries[0].Values, []string{
+			"event", "account_locked", "phone_number", "89998887766", "ip", "10.0.0.1", "failures", "6",
+		})
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Failure_ParallelFailuresKeepLockout(t provider.T) {
+	var (
+		throttle   *signinthrottle.Throttle
+		retryAfter time.Duration
+		err        error
+		ctx        = context.Background()
+	)
+
+	t.Title("Test Sign-In Parallel Failures Keep Lockout")
+	t.Description("Failures counted in parallel are all registered and a shorter delay never replaces the lockout")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, _ = newSignInThrottle(t)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = throttle.Failure(ctx, "89998887766", "10.0.0.1")
+			}()
+		}
+		wg.Wait()
+
+		retryAfter, err = throttle.Check(ctx, "89998887766", "10.0.0.1")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(testSignInPolicy.LockoutDuration, retryAfter)
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Wrap_UnknownAccountKeepsPhoneOpen(t provider.T) {
+	var (
+		throttle   *signinthrottle.Throttle
+		handler    http.Handler
+		retryAfter time.Duration
+		err        error
+		ctx        = context.Background()
+	)
+
+	t.Title("Test Sign-In Wrap Unknown Account Keeps Phone Open")
+	t.Description("Sign-ins for an unknown phone number are counted by IP only and do not lock the phone number")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, _ = newSignInThrottle(t)
+		handler = throttle.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		for i := 0; i < testSignInPolicy.Phone.MaxFailures; i++ {
+			request := newSignInRequest()
+			request.Header.Set("X-Real-IP", fmt.Sprintf("10.0.0.%d", i+1))
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+		}
+		retryAfter, err = throttle.Check(ctx, "89998887766", "10.0.1.1")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Zero(retryAfter)
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Success_ResetsPhoneFailures(t provider.T) {
+	var (
+		throttle   *signinthrottle.Throttle
+		retryAfter time.Duration
+		err        error
+		ctx        = context.Background()
+	)
+
+	t.Title("Test Sign-In Success Resets Phone Failures")
+	t.Description("A successful sign-in clears the delay for the phone number")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, _ = newSignInThrottle(t)
+		for i := 0; i < 3; i++ {
+			if err = throttle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		if err = throttle.Success(ctx, "89998887766"); err != nil {
+			t.Fatal(err)
+		}
+		retryAfter, err = throttle.Check(ctx, "89998887766", "10.0.0.1")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Zero(retryAfter)
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Wrap_ErrorTooManyAttempts(t provider.T) {
+	var (
+		handler  http.Handler
+		calls    int
+		recorder *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Sign-In Wrap Error Too Many Attempts")
+	t.Description("Once the phone number is delayed the request is answered with 429 and Retry-After")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, _ := newSignInThrottle(t)
+		handler = throttle.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusConflict)
+		}))
+		for i := 0; i < 3; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), newSignInRequest())
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newSignInRequest())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusTooManyRequests, recorder.Code)
+		t.Assert().Equal("1", recorder.Header().Get("Retry-After"))
+		t.Assert().Equal(3, calls)
+	})
+}
+
+func (sits *SignInThrottleTestsSuite) Test_Wrap_ChallengeKeepsFailures(t provider.T) {
+	var (
+		throttle   *signinthrottle.Throttle
+		handler    http.Handler
+		recorder   *httptest.ResponseRecorder
+		retryAfter time.Duration
+		err        error
+		ctx        = context.Background()
+	)
+
+	t.Title("Test Sign-In Wrap Challenge Keeps Failures")
+	t.Description("A correct password answered with a 2FA challenge does not reset the failures of the phone number")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		throttle, _ = newSignInThrottle(t)
+		for i := 0; i < 2; i++ {
+			if err = throttle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		twoFactorService, mocks := newTwoFactorService(t)
+		readerID := uuid.New()
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), readerID).
+			Return(tdbmodels.NewReaderModelBuilder().WithID(readerID).Build(), nil)
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), readerID).
+			Return(&twofactor.TwoFactorModel{ReaderID: readerID, Enabled: true}, nil)
+
+		handler = throttle.Wrap(newSignInHandler(twoFactorService, mocks.readerRepo, readerID))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newSignInRequest())
+
+		if err = throttle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+		retryAfter, err = throttle.Check(ctx, "89998887766", "10.0.0.1")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(http.StatusOK, recorder.Code)
+		t.Assert().Contains(recorder.Body.String(), "challenge_token")
+		// третья неудача подряд: бесплатные попытки исчерпаны и после входа с challenge
+		t.Assert().Equal(time.Second, retryAfter)
+	})
+}
+
+func newSignInRequest() *http.Request {
+	body := []byte(`{"phone_number": "89998887766", "password": "password01"}`)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sign-in", bytes.NewReader(body))
+	r.Header.Set("X-Real-IP", "10.0.0.1")
+
+	return r
+}
+
+func TestSignInThrottleTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SignInThrottleTestsSuite))
+}
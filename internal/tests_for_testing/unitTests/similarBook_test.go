@@ -0,0 +1,225 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	"github.com/nikitalystsev/BookSmart/internal/similar"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"testing"
+)
+
+type SimilarBookTestsSuite struct {
+	suite.Suite
+}
+
+func (sbts *SimilarBookTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "similar books", "suite", "steps")
+}
+
+// newSimilarBookStore возвращает хранилище в памяти с книгами books, в
+// котором каждый читатель из readers забронировал свои книги
+func newSimilarBookStore(t provider.T, books []*models.BookModel, readers ...[]*models.BookModel) *memory.Store {
+	store := memory.NewStore(logging.GetLoggerForTests())
+	bookRepo := memory.NewBookRepo(store, logging.GetLoggerForTests())
+	reservationRepo := memory.NewReservationRepo(store, logging.GetLoggerForTests())
+
+	for _, book := range books {
+		t.Require().Nil(bookRepo.Create(context.Background(), book))
+	}
+	for _, reserved := range readers {
+		readerID := uuid.New()
+		for _, book := range reserved {
+			reservation := tdbmodels.NewReservationModelBuilder().WithReaderID(readerID).WithBookID(book.ID).Build()
+			t.Require().Nil(reservationRepo.Create(context.Background(), reservation))
+		}
+	}
+
+	return store
+}
+
+func (sbts *SimilarBookTestsSuite) Test_GetByBookID_Success(t provider.T) {
+	var (
+		similarBookService *similar.SimilarBookService
+		book               *models.BookModel
+		coReserved         *models.BookModel
+		sameAuthor         *models.BookModel
+		similarBooks       []*similar.ScoredBook
+		err                error
+	)
+
+	t.Title("Test Get Similar Books By Book ID Success")
+	t.Description("Books reserved by the same readers are ranked above books by the same author")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithAuthor("author").WithGenre("fantasy").Build()
+		coReserved = tdbmodels.NewBookModelBuilder().WithAuthor("other author").WithGenre("drama").Build()
+		sameAuthor = tdbmodels.NewBookModelBuilder().WithAuthor(" Author ").WithGenre("poetry").Build()
+
+		store := newSimilarBookStore(t, []*models.BookModel{book, coReserved, sameAuthor},
+			[]*models.BookModel{book, coReserved})
+		similarBookRepo := memory.NewSimilarBookRepo(store, logging.GetLoggerForTests())
+		similarBookService = similar.NewSimilarBookService(memory.NewBookRepo(store, logging.GetLoggerForTests()),
+			similarBookRepo, logging.GetLoggerForTests())
+
+		t.Require().Nil(similarBookRepo.Refresh(context.Background()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		similarBooks, err = similarBookService.GetByBookID(context.Background(), book.ID, similar.SimilarBooksPageLimit, 0)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Require().Len(similarBooks, 2)
+		t.Assert().Equal(coReserved, similarBooks[0].Book)
+		t.Assert().Equal(similar.CoReservationWeight, similarBooks[0].Score)
+		t.Assert().Equal(sameAuthor, similarBooks[1].Book)
+		t.Assert().Equal(similar.SameAuthorWeight, similarBooks[1].Score)
+	})
+}
+
+func (sbts *SimilarBookTestsSuite) Test_GetByBookID_CoReservationsBeforeRefresh(t provider.T) {
+	var (
+		similarBookService *similar.SimilarBookService
+		book               *models.BookModel
+		similarBooks       []*similar.ScoredBook
+		err                error
+	)
+
+	t.Title("Test Get Similar Books By Book ID Before Refresh")
+	t.Description("Reservations are not used until the co-reservation table is refreshed")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithAuthor("author").WithGenre("fantasy").Build()
+		coReserved := tdbmodels.NewBookModelBuilder().WithAuthor("other author").WithGenre("drama").Build()
+
+		store := newSimilarBookStore(t, []*models.BookModel{book, coReserved}, []*models.BookModel{book, coReserved})
+		similarBookService = similar.NewSimilarBookService(memory.NewBookRepo(store, logging.GetLoggerForTests()),
+			memory.NewSimilarBookRepo(store, logging.GetLoggerForTests()), logging.GetLoggerForTests())
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		similarBooks, err = similarBookService.GetByBookID(context.Background(), book.ID, similar.SimilarBooksPageLimit, 0)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(similarBooks)
+		t.Assert().Equal(similar.ErrSimilarBooksDoesNotExists, err)
+	})
+}
+
+func (sbts *SimilarBookTestsSuite) Test_GetByBookID_SecondPageSkipsDeletedBooks(t provider.T) {
+	var (
+		similarBookService *similar.SimilarBookService
+		book               *models.BookModel
+		sameAuthor         []*models.BookModel
+		similarBooks       []*similar.ScoredBook
+		err                error
+	)
+
+	t.Title("Test Get Similar Books By Book ID Second Page")
+	t.Description("Deleted co-reserved books do not shorten pages")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().WithAuthor("author").WithGenre("fantasy").Build()
+		deleted := tdbmodels.NewBookModelBuilder().WithAuthor("other author").WithGenre("drama").Build()
+		books := []*models.BookModel{book, deleted}
+		for range 3 {
+			sameAuthorBook := tdbmodels.NewBookModelBuilder().WithAuthor("author").WithGenre("poetry").Build()
+			sameAuthor = append(sameAuthor, sameAuthorBook)
+			books = append(books, sameAuthorBook)
+		}
+
+		store := newSimilarBookStore(t, books, []*models.BookModel{book, deleted})
+		similarBookRepo := memory.NewSimilarBookRepo(store, logging.GetLoggerForTests())
+		similarBookService = similar.NewSimilarBookService(memory.NewBookRepo(store, logging.GetLoggerForTests()),
+			similarBookRepo, logging.GetLoggerForTests())
+
+		t.Require().Nil(similarBookRepo.Refresh(context.Background()))
+		t.Require().Nil(memory.NewDeletedBookRepo(store, logging.GetLoggerForTests()).Delete(context.Background(), deleted.ID))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		similarBooks, err = similarBookService.GetByBookID(context.Background(), book.ID, 2, 2)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Require().Len(similarBooks, 1)
+		t.Assert().Contains(sameAuthor, similarBooks[0].Book)
+	})
+}
+
+func (sbts *SimilarBookTestsSuite) Test_GetByBookID_ErrorBookDoesNotExists(t provider.T) {
+	var (
+		similarBookService *similar.SimilarBookService
+		similarBooks       []*similar.ScoredBook
+		book               *models.BookModel
+		err                error
+	)
+
+	t.Title("Test Get Similar Books By Book ID Error: book does not exists")
+	t.Description("Similar books were not retrieved because the book does not exist")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockSimilarBookRepo := mockrepo.NewMockISimilarBookRepo(ctrl)
+		similarBookService = similar.NewSimilarBookService(mockBookRepo, mockSimilarBookRepo, logging.GetLoggerForTests())
+		book = tdbmodels.NewBookModelBuilder().Build()
+		mockBookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(nil, errs.ErrBookDoesNotExists)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		similarBooks, err = similarBookService.GetByBookID(context.Background(), book.ID, similar.SimilarBooksPageLimit, 0)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(similarBooks)
+		t.Assert().Equal(errs.ErrBookDoesNotExists, err)
+	})
+}
+
+func (sbts *SimilarBookTestsSuite) Test_GetByBookID_ErrorSimilarBooksDoesNotExists(t provider.T) {
+	var (
+		similarBookService *similar.SimilarBookService
+		similarBooks       []*similar.ScoredBook
+		book               *models.BookModel
+		err                error
+	)
+
+	t.Title("Test Get Similar Books By Book ID Error: similar books does not exists")
+	t.Description("Similar books were not retrieved because no book shares readers, author or genre")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		ctrl := gomock.NewController(t)
+		mockBookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		mockSimilarBookRepo := mockrepo.NewMockISimilarBookRepo(ctrl)
+		similarBookService = similar.NewSimilarBookService(mockBookRepo, mockSimilarBookRepo, logging.GetLoggerForTests())
+		book = tdbmodels.NewBookModelBuilder().Build()
+		mockBookRepo.EXPECT().GetByID(gomock.Any(), book.ID).Return(book, nil)
+		mockSimilarBookRepo.EXPECT().GetByBook(gomock.Any(), book, similar.SimilarBooksPageLimit, 0).
+			Return([]*similar.ScoredBook{}, nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		similarBooks, err = similarBookService.GetByBookID(context.Background(), book.ID, similar.SimilarBooksPageLimit, 0)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(similarBooks)
+		t.Assert().Equal(similar.ErrSimilarBooksDoesNotExists, err)
+	})
+}
+
+func TestSimilarBookTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SimilarBookTestsSuite))
+}
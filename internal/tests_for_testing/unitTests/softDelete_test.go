@@ -0,0 +1,357 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/intf"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/internal/memory"
+	"github.com/nikitalystsev/BookSmart/internal/optimistic"
+	"github.com/nikitalystsev/BookSmart/internal/softdelete"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+type SoftDeleteTestsSuite struct {
+	suite.Suite
+}
+
+func (sts *SoftDeleteTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "softdelete", "suite", "steps")
+}
+
+func (sts *SoftDeleteTestsSuite) Test_BookRepo_HidesDeletedFromCatalogue(t provider.T) {
+	var (
+		repo         intfRepo.IBookRepo
+		mock         sqlxmock.Sqlmock
+		params       *dto.BookParamsDTO
+		available    *models.BookModel
+		books        []*models.BookModel
+		expectations error
+		err          error
+	)
+
+	t.Title("Test Book Repo Hides Deleted From Catalogue")
+	t.Description("Soft-deleted books are excluded in the search query before limit and offset are applied")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		available = tdbmodels.NewBookModelBuilder().Build()
+		params = &dto.BookParamsDTO{Title: available.Title, Limit: 10, Offset: 20}
+
+		var db *sqlx.DB
+		db, mock, err = sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectQuery(`from bs.book\s+where deleted_at is null .* limit nullif\(\$10, 0\) offset \$11`).
+			WithArgs(available.Title, "", "", uint(0), "", "", uint(0), "", uint(0), uint(10), 20).
+			WillReturnRows(sqlxmock.NewRows([]string{
+				"id", "title", "author", "publisher", "copies_number", "rarity", "genre", "publishing_year", "language", "age_limit",
+			}).AddRow(
+				available.ID, available.Title, available.Author, available.Publisher, available.CopiesNumber,
+				available.Rarity, available.Genre, available.PublishingYear, available.Language, available.AgeLimit,
+			))
+
+		// поиск не обращается к репозиторию книг
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+
+		repo = softdelete.NewBookRepo(bookRepo, softdelete.NewPostgresRepo(db, logging.GetLoggerForTests()),
+			optimistic.NewPostgresRepo(db, logging.GetLoggerForTests()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		books, err = repo.GetByParams(context.Background(), params)
+		expectations = mock.ExpectationsWereMet()
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal([]*models.BookModel{available}, books)
+		t.Assert().Nil(expectations)
+	})
+}
+
+func (sts *SoftDeleteTestsSuite) Test_DeletedBookRepo_FullPageWithDeletedBooks(t provider.T) {
+	var (
+		repo          intfRepo.IBookRepo
+		deletedBookID uuid.UUID
+		books         []*models.BookModel
+		err           error
+	)
+
+	t.Title("Test Deleted Book Repo Full Page With Deleted Books")
+	t.Description("A search page is filled up to the limit even if deleted books would fall on it")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		deletedBookRepo := memory.NewDeletedBookRepo(store, logging.GetLoggerForTests())
+		repo = softdelete.NewBookRepo(memory.NewBookRepo(store, logging.GetLoggerForTests()), deletedBookRepo,
+			memory.NewVersionRepo(store, logging.GetLoggerForTests()))
+
+		for _, title := range []string{"Анна Каренина", "Бесы", "Война и мир"} {
+			book := tdbmodels.NewBookModelBuilder().WithTitle(title).Build()
+			t.Require().Nil(repo.Create(context.Background(), book))
+			if deletedBookID == uuid.Nil {
+				deletedBookID = book.ID
+			}
+		}
+		t.Require().Nil(repo.Delete(context.Background(), deletedBookID))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		books, err = repo.GetByParams(context.Background(), &dto.BookParamsDTO{Limit: 2})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Len(books, 2)
+		for _, book := range books {
+			t.Assert().NotEqual(deletedBookID, book.ID)
+		}
+	})
+}
+
+func (sts *SoftDeleteTestsSuite) Test_BookRepo_HidesDeletedTitle(t provider.T) {
+	var (
+		repo intfRepo.IBookRepo
+		book *models.BookModel
+		err  error
+	)
+
+	t.Title("Test Book Repo Hides Deleted Title")
+	t.Description("A soft-deleted book is not found by title, so a new book with its title can be added")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		book = tdbmodels.NewBookModelBuilder().Build()
+
+		db, mock, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock.ExpectQuery(`select id from bs.book`).
+			WillReturnRows(sqlxmock.NewRows([]string{"id"}).AddRow(book.ID))
+
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+		bookRepo.EXPECT().GetByTitle(gomock.Any(), book.Title).Return(book, nil)
+
+		repo = softdelete.NewBookRepo(bookRepo, softdelete.NewPostgresRepo(db, logging.GetLoggerForTests()),
+			optimistic.NewPostgresRepo(db, logging.GetLoggerForTests()))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = repo.GetByTitle(context.Background(), book.Title)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, errs.ErrBookDoesNotExists)
+	})
+}
+
+// reservationServiceStub бронирует книгу, только читая ее из репозитория
+type reservationServiceStub struct {
+	intf.IReservationService
+	bookRepo intfRepo.IBookRepo
+}
+
+func (s *reservationServiceStub) Create(ctx context.Context, _, bookID uuid.UUID) error {
+	_, err := s.bookRepo.GetByID(ctx, bookID)
+	return err
+}
+
+func (sts *SoftDeleteTestsSuite) Test_ReservationService_ErrorDeletedBook(t provider.T) {
+	var (
+		reservationService intf.IReservationService
+		repo               intfRepo.IBookRepo
+		book, findBook     *models.BookModel
+		reserveErr, getErr error
+	)
+
+	t.Title("Test Reservation Service Error Deleted Book")
+	t.Description("A soft-deleted book is not found when it is reserved, but is still read by ID to return its copy")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		repo = softdelete.NewBookRepo(memory.NewBookRepo(store, logging.GetLoggerForTests()),
+			memory.NewDeletedBookRepo(store, logging.GetLoggerForTests()), memory.NewVersionRepo(store, logging.GetLoggerForTests()))
+		reservationService = softdelete.NewReservationService(&reservationServiceStub{bookRepo: repo})
+
+		book = tdbmodels.NewBookModelBuilder().Build()
+		t.Require().Nil(repo.Create(context.Background(), book))
+		t.Require().Nil(repo.Delete(context.Background(), book.ID))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		reserveErr = reservationService.Create(context.Background(), uuid.New(), book.ID)
+		findBook, getErr = repo.GetByID(context.Background(), book.ID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(reserveErr, errs.ErrBookDoesNotExists)
+		t.Assert().Nil(getErr)
+		t.Assert().Equal(book.CopiesNumber, findBook.CopiesNumber)
+	})
+}
+
+func (sts *SoftDeleteTestsSuite) Test_DeletedBookService_PurgesExpiredBooks(t provider.T) {
+	var (
+		service *softdelete.DeletedBookService
+		bookID  uuid.UUID
+		changed []uuid.UUID
+		mock    sqlxmock.Sqlmock
+		purged  int
+		err     error
+	)
+
+	t.Title("Test Deleted Book Service Purges Expired Books")
+	t.Description("Books deleted longer than the retention period ago are removed with their ratings and reservations")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		bookID = uuid.New()
+
+		db, dbMock, err := sqlxmock.Newx()
+		t.Require().Nil(err)
+		mock = dbMock
+		mock.ExpectQuery(`select b.id`).
+			WillReturnRows(sqlxmock.NewRows([]string{"id"}).AddRow(bookID))
+		mock.ExpectQuery(`select id from bs.book where id = \$1 and deleted_at < \$2 for update`).
+			WillReturnRows(sqlxmock.NewRows([]string{"id"}).AddRow(bookID))
+		for _, table := range []string{"rating", "reservation", "favorite_books", "book"} {
+			mock.ExpectExec(`delete from bs.` + table).WithArgs(bookID).WillReturnResult(sqlxmock.NewResult(0, 1))
+		}
+
+		service = softdelete.NewDeletedBookService(
+			softdelete.NewPostgresRepo(db, logging.GetLoggerForTests()),
+			newPassThroughTransactionManager(gomock.NewController(t)),
+			logging.GetLoggerForTests(),
+			24*time.Hour,
+			func(ctx context.Context, bookID uuid.UUID) { changed = append(changed, bookID) },
+		)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		purged, err = service.Purge(context.Background())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(1, purged)
+		t.Assert().Equal([]uuid.UUID{bookID}, changed)
+		t.Assert().Nil(mock.ExpectationsWereMet())
+	})
+}
+
+func (sts *SoftDeleteTestsSuite) Test_ReaderRepo_BlocksDeletedReader(t provider.T) {
+	var (
+		accountRepo   account.IAccountRepo
+		readerRepo    intfRepo.IReaderRepo
+		service       *softdelete.DeletedReaderService
+		readerID      uuid.UUID
+		deletedErr    error
+		restoredErr   error
+		deletedReader *models.ReaderModel
+		deletedGetErr error
+	)
+
+	t.Title("Test Reader Repo Blocks Deleted Reader")
+	t.Description("A soft-deleted reader cannot get refresh tokens until an admin restores the account")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		reader := tdbmodels.NewReaderModelBuilder().Build()
+		readerID = reader.ID
+
+		deleted := memory.NewDeletedReaderRepo(store, logging.GetLoggerForTests())
+		readerRepo = softdelete.NewReaderRepo(memory.NewReaderRepo(store, logging.GetLoggerForTests()), deleted)
+		accountRepo = softdelete.NewAccountRepo(memory.NewAccountRepo(store, logging.GetLoggerForTests()), deleted)
+		service = softdelete.NewDeletedReaderService(deleted, memory.NewAccountRepo(store, logging.GetLoggerForTests()),
+			newPassThroughTransactionManager(gomock.NewController(t)), logging.GetLoggerForTests(), 24*time.Hour)
+
+		t.Require().Nil(readerRepo.Create(context.Background(), reader))
+		t.Require().Nil(readerRepo.SaveRefreshToken(context.Background(), readerID, "before", time.Hour))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		ctx := context.Background()
+		t.Require().Nil(accountRepo.Delete(ctx, readerID))
+		deletedErr = readerRepo.SaveRefreshToken(ctx, readerID, "after", time.Hour)
+		_, deletedGetErr = readerRepo.GetByRefreshToken(ctx, "before")
+		// история удаленного читателя остается доступной по идентификатору
+		deletedReader, _ = readerRepo.GetByID(ctx, readerID)
+
+		t.Require().Nil(service.Restore(ctx, readerID))
+		restoredErr = readerRepo.SaveRefreshToken(ctx, readerID, "after", time.Hour)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(deletedErr, errs.ErrReaderDoesNotExists)
+		t.Assert().ErrorIs(deletedGetErr, errs.ErrReaderDoesNotExists)
+		t.Assert().NotNil(deletedReader)
+		t.Assert().Nil(restoredErr)
+	})
+}
+
+func (sts *SoftDeleteTestsSuite) Test_DeletedReaderService_PurgesExpiredReaders(t provider.T) {
+	var (
+		service    *softdelete.DeletedReaderService
+		readerRepo intfRepo.IReaderRepo
+		purgedID   uuid.UUID
+		restoredID uuid.UUID
+		purged     int
+		purgedErr  error
+		restored   *models.ReaderModel
+		err        error
+	)
+
+	t.Title("Test Deleted Reader Service Purges Expired Readers")
+	t.Description("Readers deleted longer than the retention period ago are removed, restored readers are kept")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		store := memory.NewStore(logging.GetLoggerForTests())
+		deleted := memory.NewDeletedReaderRepo(store, logging.GetLoggerForTests())
+		readerRepo = memory.NewReaderRepo(store, logging.GetLoggerForTests())
+		accountRepo := softdelete.NewAccountRepo(memory.NewAccountRepo(store, logging.GetLoggerForTests()), deleted)
+
+		for _, reader := range []*models.ReaderModel{
+			tdbmodels.NewReaderModelBuilder().WithPhoneNumber("89000000001").Build(),
+			tdbmodels.NewReaderModelBuilder().WithPhoneNumber("89000000002").Build(),
+		} {
+			t.Require().Nil(readerRepo.Create(context.Background(), reader))
+			t.Require().Nil(accountRepo.Delete(context.Background(), reader.ID))
+			if purgedID == uuid.Nil {
+				purgedID = reader.ID
+			} else {
+				restoredID = reader.ID
+			}
+		}
+		t.Require().Nil(deleted.Restore(context.Background(), restoredID))
+
+		// нулевой срок хранения: удаленные читатели сразу подлежат окончательному удалению
+		service = softdelete.NewDeletedReaderService(deleted, memory.NewAccountRepo(store, logging.GetLoggerForTests()),
+			newPassThroughTransactionManager(gomock.NewController(t)), logging.GetLoggerForTests(), 0)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		purged, err = service.Purge(context.Background())
+		_, purgedErr = readerRepo.GetByID(context.Background(), purgedID)
+		restored, _ = readerRepo.GetByID(context.Background(), restoredID)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(1, purged)
+		t.Assert().ErrorIs(purgedErr, errs.ErrReaderDoesNotExists)
+		t.Assert().NotNil(restored)
+	})
+}
+
+func TestSoftDeleteTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SoftDeleteTestsSuite))
+}
@@ -0,0 +1,214 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/token"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"testing"
+	"time"
+)
+
+type TokenManagerTestsSuite struct {
+	suite.Suite
+}
+
+func (tmts *TokenManagerTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "token manager", "suite", "steps")
+}
+
+func newTokenManager(t provider.T, activeKeyID string, keys ...*token.Key) *token.Manager {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	denylist := token.NewRedisDenylist(redis.NewClient(&redis.Options{Addr: mr.Addr()}), logging.GetLoggerForTests())
+	legacy := mockrepo.NewMockITokenManager(gomock.NewController(t))
+
+	tokenManager, err := token.NewManager(activeKeyID, keys, legacy, time.Time{}, denylist, time.Hour, logging.GetLoggerForTests())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenManager
+}
+
+func (tmts *TokenManagerTestsSuite) Test_Parse_AfterKeyRotation(t provider.T) {
+	var (
+		rotated     *token.Manager
+		accessToken string
+		readerID    uuid.UUID
+		parsedID    string
+		role        string
+		err         error
+	)
+
+	t.Title("Test Parse Access Token After Key Rotation")
+	t.Description("A token signed with the previous key stays valid after a new key becomes active")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		oldKey, _ := token.NewHMACKey("old", []byte("old secret"))
+		newKey, _ := token.NewHMACKey("new", []byte("new secret"))
+		readerID = uuid.New()
+
+		accessToken, err = newTokenManager(t, "old", oldKey).NewJWT(readerID, "Reader", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotated = newTokenManager(t, "new", newKey, oldKey)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		parsedID, role, err = rotated.Parse(accessToken)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal(readerID.String(), parsedID)
+		t.Assert().Equal("Reader", role)
+	})
+}
+
+func (tmts *TokenManagerTestsSuite) Test_Parse_ErrorRevokedToken(t provider.T) {
+	var (
+		tokenManager *token.Manager
+		accessToken  string
+		err          error
+	)
+
+	t.Title("Test Parse Access Token Error: revoked token")
+	t.Description("A token added to the denylist is rejected before it expires")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		key, _ := token.NewHMACKey("default", []byte("secret"))
+		tokenManager = newTokenManager(t, "", key)
+
+		accessToken, err = tokenManager.NewJWT(uuid.New(), "Reader", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tokenManager.Revoke(context.Background(), accessToken); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, _, err = tokenManager.Parse(accessToken)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(token.ErrRevokedToken, err)
+	})
+}
+
+func (tmts *TokenManagerTestsSuite) Test_Parse_ErrorUnknownKeyID(t provider.T) {
+	var (
+		accessToken string
+		err         error
+	)
+
+	t.Title("Test Parse Access Token Error: unknown key id")
+	t.Description("A token signed with a key that was removed from the configuration is rejected")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		removedKey, _ := token.NewHMACKey("removed", []byte("removed secret"))
+		accessToken, err = newTokenManager(t, "", removedKey).NewJWT(uuid.New(), "Reader", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		key, _ := token.NewHMACKey("current", []byte("current secret"))
+		_, _, err = newTokenManager(t, "", key).Parse(accessToken)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, token.ErrUnknownKeyID)
+	})
+}
+
+func (tmts *TokenManagerTestsSuite) Test_Parse_RevokeReaderKeepsLaterToken(t provider.T) {
+	var (
+		tokenManager                *token.Manager
+		oldAccessToken, accessToken string
+		oldErr, err                 error
+	)
+
+	t.Title("Test Parse Access Token: token issued right after reader revocation")
+	t.Description("Revoking all reader tokens denies earlier tokens but not a token issued within the same second afterwards")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		key, _ := token.NewHMACKey("default", []byte("secret"))
+		tokenManager = newTokenManager(t, "", key)
+		readerID := uuid.New()
+
+		oldAccessToken, err = tokenManager.NewJWT(readerID, "Reader", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		if err = tokenManager.RevokeReader(context.Background(), readerID); err != nil {
+			t.Fatal(err)
+		}
+		accessToken, err = tokenManager.NewJWT(readerID, "Reader", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, _, oldErr = tokenManager.Parse(oldAccessToken)
+		_, _, err = tokenManager.Parse(accessToken)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(token.ErrRevokedToken, oldErr)
+		t.Assert().Nil(err)
+	})
+}
+
+func (tmts *TokenManagerTestsSuite) Test_Parse_ErrorLegacyTokenAfterCutoff(t provider.T) {
+	var (
+		tokenManager *token.Manager
+		accessToken  string
+		err          error
+	)
+
+	t.Title("Test Parse Access Token Error: legacy token after cut-off")
+	t.Description("A token without kid is rejected without checking the legacy signing key once auth.legacyTokensUntil has passed")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		key, _ := token.NewHMACKey("default", []byte("secret"))
+		tokenManager = newTokenManager(t, "", key)
+
+		accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, _, err = tokenManager.Parse(accessToken)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, token.ErrInvalidToken)
+	})
+}
+
+func TestTokenManagerTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TokenManagerTestsSuite))
+}
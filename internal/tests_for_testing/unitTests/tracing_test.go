@@ -0,0 +1,182 @@
+package unitTests
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/internal/tracing"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type TracingTestsSuite struct {
+	suite.Suite
+}
+
+func (tts *TracingTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "tracing", "suite", "steps")
+}
+
+func newTestTracerProvider() (*tracing.Provider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return tracing.NewSDKProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+func (tts *TracingTestsSuite) Test_Middleware_ContinuesIncomingTrace(t provider.T) {
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	var (
+		recorder *tracetest.SpanRecorder
+		router   *gin.Engine
+		response *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Middleware Continues Incoming Trace")
+	t.Description("The server span continues the incoming traceparent and repository spans become its children")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var tracerProvider *tracing.Provider
+		tracerProvider, recorder = newTestTracerProvider()
+
+		bookRepo := mockrepo.NewMockIBookRepo(gomock.NewController(t))
+		bookRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(tdbmodels.NewBookModelBuilder().Build(), nil)
+		repo := tracing.NewBookRepo(bookRepo, tracerProvider, "postgres")
+
+		gin.SetMode(gin.TestMode)
+		inner := gin.New()
+		inner.GET("/api/v1/books/:id", func(c *gin.Context) {
+			_, _ = repo.GetByID(c.Request.Context(), uuid.New())
+			c.Status(http.StatusOK)
+		})
+
+		router = gin.New()
+		router.Use(tracing.Middleware(tracerProvider, middleware.NewRouteMatcher(inner.Routes())))
+		router.NoRoute(gin.WrapH(inner))
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/books/"+uuid.NewString(), nil)
+		request.Header.Set("traceparent", traceparent)
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		spans := recorder.Ended()
+		t.Require().Len(spans, 2)
+
+		repoSpan, serverSpan := spans[0], spans[1]
+		t.Assert().Equal("GET /api/v1/books/:id", serverSpan.Name())
+		t.Assert().Equal(traceID, serverSpan.SpanContext().TraceID().String())
+		t.Assert().Equal("00f067aa0ba902b7", serverSpan.Parent().SpanID().String())
+		t.Assert().Equal("200", spanAttribute(serverSpan, "http.response.status_code"))
+
+		t.Assert().Equal("book.GetByID", repoSpan.Name())
+		t.Assert().Equal(serverSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+		t.Assert().Equal("postgresql", spanAttribute(repoSpan, "db.system"))
+		t.Assert().Equal("GetByID", spanAttribute(repoSpan, "db.operation.name"))
+
+		t.Assert().Contains(response.Header().Get("traceparent"), traceID)
+	})
+}
+
+func (tts *TracingTestsSuite) Test_TransactionManager_ParentsRepoSpans(t provider.T) {
+	var (
+		recorder *tracetest.SpanRecorder
+		repo     intfRepo.IBookRepo
+		tm       transact.ITransactionManager
+		err      error
+	)
+
+	t.Title("Test Transaction Manager Parents Repo Spans")
+	t.Description("Repository calls inside a transaction are children of the transaction span and errors are recorded")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		var tracerProvider *tracing.Provider
+		tracerProvider, recorder = newTestTracerProvider()
+
+		ctrl := gomock.NewController(t)
+		bookRepo := mockrepo.NewMockIBookRepo(ctrl)
+		bookRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+		transactionManager := mockrepo.NewMockITransactionManager(ctrl)
+		transactionManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		repo = tracing.NewBookRepo(bookRepo, tracerProvider, "mongo")
+		tm = tracing.NewTransactionManager(transactionManager, tracerProvider)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = tm.Do(context.Background(), func(ctx context.Context) error {
+			return repo.Delete(ctx, uuid.New())
+		})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().NotNil(err)
+
+		spans := recorder.Ended()
+		t.Require().Len(spans, 2)
+
+		repoSpan, transactionSpan := spans[0], spans[1]
+		t.Assert().Equal("transaction", transactionSpan.Name())
+		t.Assert().Equal(transactionSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+		t.Assert().Equal("mongodb", spanAttribute(repoSpan, "db.system"))
+		t.Assert().Equal(codes.Error, repoSpan.Status().Code)
+		t.Assert().Equal(codes.Error, transactionSpan.Status().Code)
+	})
+}
+
+func (tts *TracingTestsSuite) Test_NewProvider_Exporters(t provider.T) {
+	var (
+		disabled *tracing.Provider
+		err      error
+	)
+
+	t.Title("Test New Provider Exporters")
+	t.Description("Tracing is disabled without an exporter and unknown exporters are rejected")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		disabled, _ = tracing.NewProvider(context.Background(), tracing.Options{Exporter: tracing.ExporterNone})
+		_, err = tracing.NewProvider(context.Background(), tracing.Options{Exporter: "zipkin"})
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().False(disabled.Enabled())
+		t.Assert().Nil(disabled.Shutdown(context.Background()))
+		t.Assert().ErrorIs(err, tracing.ErrUnknownExporter)
+	})
+}
+
+func TestTracingTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TracingTestsSuite))
+}
@@ -0,0 +1,525 @@
+package unitTests
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/signinthrottle"
+	mockrepo "github.com/nikitalystsev/BookSmart/internal/tests/unitTests/serviceTests/mocks"
+	tdbmodels "github.com/nikitalystsev/BookSmart/internal/tests_for_testing/unitTests/testDataBuilder/models"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/totp"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+type TwoFactorServiceTestsSuite struct {
+	suite.Suite
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) BeforeEach(t provider.T) {
+	t.Tags("BookSmart", "two-factor service", "suite", "steps")
+}
+
+type twoFactorServiceMocks struct {
+	twoFactorRepo  *mockrepo.MockITwoFactorRepo
+	readerRepo     *mockrepo.MockIReaderRepo
+	tokenManager   *mockrepo.MockITokenManager
+	signInThrottle *signinthrottle.Throttle
+}
+
+func newTwoFactorService(t provider.T, enforcedRoles ...string) (twofactor.ITwoFactorService, *twoFactorServiceMocks) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when running miniredis", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	ctrl := gomock.NewController(t)
+	mocks := &twoFactorServiceMocks{
+		twoFactorRepo: mockrepo.NewMockITwoFactorRepo(ctrl),
+		readerRepo:    mockrepo.NewMockIReaderRepo(ctrl),
+		tokenManager:  mockrepo.NewMockITokenManager(ctrl),
+		signInThrottle: signinthrottle.NewThrottle(signinthrottle.NewRedisAttemptStore(client, logging.GetLoggerForTests()),
+			testSignInPolicy, logging.GetLoggerForTests()),
+	}
+
+	twoFactorService := twofactor.NewTwoFactorService(
+		mocks.twoFactorRepo,
+		twofactor.NewRedisChallengeStore(client, logging.GetLoggerForTests()),
+		mocks.readerRepo,
+		mocks.signInThrottle,
+		mocks.tokenManager,
+		logging.GetLoggerForTests(),
+		time.Hour,
+		24*time.Hour,
+		enforcedRoles,
+		twofactor.DefaultChallengeTTL,
+		twofactor.DefaultMaxChallengeAttempts,
+		twofactor.DefaultMaxFailures,
+		twofactor.DefaultLockoutDuration,
+	)
+
+	return twoFactorService, mocks
+}
+
+// expectSignIn разрешает выдачу токенов читателю после проверки второго фактора
+func (mocks *twoFactorServiceMocks) expectSignIn(readerID uuid.UUID) {
+	mocks.tokenManager.EXPECT().NewJWT(readerID, gomock.Any(), time.Hour).Return("access", nil).AnyTimes()
+	mocks.tokenManager.EXPECT().NewRefreshToken().Return("refresh", nil).AnyTimes()
+	mocks.readerRepo.EXPECT().SaveRefreshToken(gomock.Any(), readerID, "refresh", 24*time.Hour).Return(nil).AnyTimes()
+}
+
+// newSignInHandler возвращает вход за SignInGuard: обработчик, как внешний
+// обработчик входа, сохраняет refresh-токен читателя и отдает пару токенов
+func newSignInHandler(twoFactorService twofactor.ITwoFactorService, readerRepo intfRepo.IReaderRepo, readerID uuid.UUID) http.Handler {
+	readerRepo = twofactor.NewReaderRepo(readerRepo, twoFactorService, logging.GetLoggerForTests())
+
+	return twofactor.SignInGuard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := readerRepo.SaveRefreshToken(r.Context(), readerID, "refresh", time.Hour); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"access","refresh_token":"refresh"}`))
+	}))
+}
+
+func currentTOTPCode(t provider.T, secret string) string {
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_TOTP_RFC6238Vector(t provider.T) {
+	var code string
+
+	t.Title("Test TOTP RFC 6238 Test Vector")
+	t.Description("The code for T = 59 s matches the SHA1 test vector from RFC 6238")
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		code, _ = totp.Code("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", totp.Step(time.Unix(59, 0)))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal("287082", code)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_Confirm_Success(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		recoveryCodes    []string
+		err              error
+	)
+
+	t.Title("Test Confirm Two-Factor Success")
+	t.Description("A valid code enables 2FA and returns recovery codes whose hashes are saved")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil)
+		mocks.twoFactorRepo.EXPECT().Save(gomock.Any(), twoFactor).Return(nil)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recoveryCodes, err = twoFactorService.Confirm(context.Background(), twoFactor.ReaderID, currentTOTPCode(t, twoFactor.Secret))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().True(twoFactor.Enabled)
+		t.Assert().Len(recoveryCodes, twofactor.RecoveryCodesCount)
+		t.Assert().Len(twoFactor.RecoveryCodeHashes, twofactor.RecoveryCodesCount)
+		t.Assert().NotContains(twoFactor.RecoveryCodeHashes, recoveryCodes[0])
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_VerifyChallenge_RecoveryCode(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		challengeToken   string
+		recoveryCodes    []string
+		signIn           *twofactor.SignInModel
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Verify Two-Factor Challenge Recovery Code")
+	t.Description("A recovery code completes the sign-in once and cannot be reused")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil).AnyTimes()
+		mocks.twoFactorRepo.EXPECT().Save(gomock.Any(), twoFactor).Return(nil).AnyTimes()
+		// код удаляется условным обновлением: повторно удалить его нельзя
+		mocks.twoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), twoFactor.ReaderID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, codeHash string) (bool, error) {
+				return slices.Contains(twoFactor.RecoveryCodeHashes, codeHash), nil
+			}).AnyTimes()
+		mocks.expectSignIn(twoFactor.ReaderID)
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).Build()
+
+		if recoveryCodes, err = twoFactorService.Confirm(ctx, twoFactor.ReaderID, currentTOTPCode(t, secret)); err != nil {
+			t.Fatal(err)
+		}
+		if challengeToken, _, err = twoFactorService.StartChallenge(ctx, reader); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		signIn, err = twoFactorService.VerifyChallenge(ctx, challengeToken, recoveryCodes[0])
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Equal("access", signIn.AccessToken)
+		t.Assert().Equal("refresh", signIn.RefreshToken)
+		t.Assert().Len(twoFactor.RecoveryCodeHashes, twofactor.RecoveryCodesCount-1)
+
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).Build()
+		challengeToken, _, _ = twoFactorService.StartChallenge(ctx, reader)
+		_, err = twoFactorService.VerifyChallenge(ctx, challengeToken, recoveryCodes[0])
+		t.Assert().ErrorIs(err, twofactor.ErrInvalidTwoFactorCode)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_VerifyChallenge_ErrorReusedCode(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		challengeToken   string
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Verify Two-Factor Challenge Error Reused Code")
+	t.Description("A TOTP code whose step was already taken by a concurrent request is rejected even if the loaded settings are stale")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		// в загруженных настройках интервал еще не использован, но условное обновление не проходит
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret, Enabled: true}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil).AnyTimes()
+		mocks.twoFactorRepo.EXPECT().UseStep(gomock.Any(), twoFactor.ReaderID, gomock.Any()).Return(false, nil)
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).Build()
+
+		if challengeToken, _, err = twoFactorService.StartChallenge(ctx, reader); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		_, err = twoFactorService.VerifyChallenge(ctx, challengeToken, currentTOTPCode(t, twoFactor.Secret))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, twofactor.ErrInvalidTwoFactorCode)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_VerifyChallenge_ErrorLocked(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		failureErrs      []error
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Verify Two-Factor Challenge Error Locked")
+	t.Description("Invalid codes are counted per reader across challenges, after the limit even a valid code is rejected")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret, Enabled: true}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil).AnyTimes()
+		mocks.twoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), twoFactor.ReaderID, gomock.Any()).Return(false, nil).AnyTimes()
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).Build()
+		// каждый неверный код - в новом challenge, чтобы не упереться в лимит попыток challenge
+		for i := 0; i < twofactor.DefaultMaxFailures; i++ {
+			challengeToken, _, startErr := twoFactorService.StartChallenge(ctx, reader)
+			if startErr != nil {
+				t.Fatal(startErr)
+			}
+			_, verifyErr := twoFactorService.VerifyChallenge(ctx, challengeToken, "invalid")
+			failureErrs = append(failureErrs, verifyErr)
+		}
+
+		challengeToken, _, startErr := twoFactorService.StartChallenge(ctx, reader)
+		if startErr != nil {
+			t.Fatal(startErr)
+		}
+		_, err = twoFactorService.VerifyChallenge(ctx, challengeToken, currentTOTPCode(t, twoFactor.Secret))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		for _, failureErr := range failureErrs {
+			t.Assert().ErrorIs(failureErr, twofactor.ErrInvalidTwoFactorCode)
+		}
+		t.Assert().ErrorIs(err, twofactor.ErrTwoFactorLocked)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_VerifyChallenge_ResetsSignInThrottle(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		retryAfter       time.Duration
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Verify Two-Factor Challenge Resets Sign-In Throttle")
+	t.Description("Sign-in failures of the phone number are cleared only once the second factor is verified")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret, Enabled: true}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil).AnyTimes()
+		mocks.twoFactorRepo.EXPECT().UseStep(gomock.Any(), twoFactor.ReaderID, gomock.Any()).Return(true, nil)
+		mocks.expectSignIn(twoFactor.ReaderID)
+
+		for i := 0; i < 3; i++ {
+			if err = mocks.signInThrottle.Failure(ctx, "89998887766", "10.0.0.1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).WithPhoneNumber("89998887766").Build()
+		challengeToken, _, startErr := twoFactorService.StartChallenge(ctx, reader)
+		if startErr != nil {
+			t.Fatal(startErr)
+		}
+		if _, err = twoFactorService.VerifyChallenge(ctx, challengeToken, currentTOTPCode(t, twoFactor.Secret)); err != nil {
+			t.Fatal(err)
+		}
+		retryAfter, err = mocks.signInThrottle.Check(ctx, "89998887766", "10.0.0.1")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Zero(retryAfter)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_SignInGuard_ChallengeWithoutSession(t provider.T) {
+	var (
+		handler     http.Handler
+		savedTokens []string
+		recorder    *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Sign-In Guard Challenge Without Session")
+	t.Description("A reader with 2FA gets a challenge token, the tokens of the sign-in handler are dropped and no session is created")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks := newTwoFactorService(t)
+		readerID := uuid.New()
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), readerID).
+			Return(tdbmodels.NewReaderModelBuilder().WithID(readerID).Build(), nil)
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), readerID).
+			Return(&twofactor.TwoFactorModel{ReaderID: readerID, Enabled: true}, nil)
+		mocks.readerRepo.EXPECT().SaveRefreshToken(gomock.Any(), readerID, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, token string, _ time.Duration) error {
+				savedTokens = append(savedTokens, token)
+				return nil
+			}).AnyTimes()
+
+		handler = newSignInHandler(twoFactorService, mocks.readerRepo, readerID)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newSignInRequest())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, recorder.Code)
+		t.Assert().Contains(recorder.Body.String(), "challenge_token")
+		t.Assert().NotContains(recorder.Body.String(), "refresh")
+		t.Assert().Empty(savedTokens)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_SignInGuard_WithoutTwoFactor(t provider.T) {
+	var (
+		handler     http.Handler
+		savedTokens []string
+		recorder    *httptest.ResponseRecorder
+	)
+
+	t.Title("Test Sign-In Guard Without Two-Factor")
+	t.Description("A reader without 2FA signs in directly and the session is created by the sign-in handler")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks := newTwoFactorService(t)
+		readerID := uuid.New()
+		mocks.readerRepo.EXPECT().GetByID(gomock.Any(), readerID).
+			Return(tdbmodels.NewReaderModelBuilder().WithID(readerID).Build(), nil)
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), readerID).Return(nil, twofactor.ErrTwoFactorNotEnabled)
+		mocks.readerRepo.EXPECT().SaveRefreshToken(gomock.Any(), readerID, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, token string, _ time.Duration) error {
+				savedTokens = append(savedTokens, token)
+				return nil
+			}).AnyTimes()
+
+		handler = newSignInHandler(twoFactorService, mocks.readerRepo, readerID)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newSignInRequest())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Equal(http.StatusOK, recorder.Code)
+		t.Assert().JSONEq(`{"access_token":"access","refresh_token":"refresh"}`, recorder.Body.String())
+		t.Assert().Equal([]string{"refresh"}, savedTokens)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_VerifyChallenge_CreatesSession(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		twoFactor        *twofactor.TwoFactorModel
+		challengeToken   string
+		savedTokens      []string
+		signIn           *twofactor.SignInModel
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Verify Two-Factor Challenge Creates Session")
+	t.Description("Tokens are issued and the refresh token is saved only after the code is verified")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t)
+		secret, _ := totp.GenerateSecret()
+		twoFactor = &twofactor.TwoFactorModel{ReaderID: uuid.New(), Secret: secret, Enabled: true}
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), twoFactor.ReaderID).Return(twoFactor, nil).AnyTimes()
+		mocks.twoFactorRepo.EXPECT().UseStep(gomock.Any(), twoFactor.ReaderID, gomock.Any()).Return(true, nil)
+		mocks.tokenManager.EXPECT().NewJWT(twoFactor.ReaderID, "Librarian", time.Hour).Return("access", nil)
+		mocks.tokenManager.EXPECT().NewRefreshToken().Return("refresh", nil)
+		mocks.readerRepo.EXPECT().SaveRefreshToken(gomock.Any(), twoFactor.ReaderID, gomock.Any(), 24*time.Hour).DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, token string, _ time.Duration) error {
+				savedTokens = append(savedTokens, token)
+				return nil
+			})
+
+		reader := tdbmodels.NewReaderModelBuilder().WithID(twoFactor.ReaderID).WithRole("Librarian").Build()
+		if challengeToken, _, err = twoFactorService.StartChallenge(ctx, reader); err != nil {
+			t.Fatal(err)
+		}
+		t.Require().Empty(savedTokens)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		signIn, err = twoFactorService.VerifyChallenge(ctx, challengeToken, currentTOTPCode(t, twoFactor.Secret))
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Require().Nil(err)
+		t.Assert().Equal(twoFactor.ReaderID, signIn.ReaderID)
+		t.Assert().Equal("access", signIn.AccessToken)
+		t.Assert().Equal("refresh", signIn.RefreshToken)
+		t.Assert().Greater(signIn.ExpiredAt, time.Now().UnixMilli())
+		t.Assert().Equal([]string{"refresh"}, savedTokens)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_StartChallenge_EnforcedRole(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		mocks            *twoFactorServiceMocks
+		readerToken      string
+		librarianToken   string
+		challenge        *twofactor.ChallengeModel
+		err              error
+		ctx              = context.Background()
+	)
+
+	t.Title("Test Start Two-Factor Challenge Enforced Role")
+	t.Description("A librarian without 2FA must enrol during sign-in, a reader without 2FA signs in directly")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, mocks = newTwoFactorService(t, "Librarian")
+		mocks.twoFactorRepo.EXPECT().GetByReaderID(gomock.Any(), gomock.Any()).Return(nil, twofactor.ErrTwoFactorNotEnabled).Times(2)
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		readerToken, _, err = twoFactorService.StartChallenge(ctx, tdbmodels.NewReaderModelBuilder().Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		librarianToken, challenge, err = twoFactorService.StartChallenge(ctx,
+			tdbmodels.NewReaderModelBuilder().WithRole("Librarian").Build())
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().Nil(err)
+		t.Assert().Empty(readerToken)
+		t.Assert().NotEmpty(librarianToken)
+		t.Assert().True(challenge.EnrollmentRequired)
+	})
+}
+
+func (tfsts *TwoFactorServiceTestsSuite) Test_Disable_ErrorEnforcedRole(t provider.T) {
+	var (
+		twoFactorService twofactor.ITwoFactorService
+		err              error
+	)
+
+	t.Title("Test Disable Two-Factor Error Enforced Role")
+	t.Description("2FA cannot be disabled for a role that requires it")
+
+	t.WithNewStep("Arrange", func(sCtx provider.StepCtx) {
+		twoFactorService, _ = newTwoFactorService(t, "Librarian")
+	})
+
+	t.WithNewStep("Act", func(sCtx provider.StepCtx) {
+		err = twoFactorService.Disable(context.Background(), uuid.New(), "Librarian", "123456")
+	})
+
+	t.WithNewStep("Assert", func(sCtx provider.StepCtx) {
+		t.Assert().ErrorIs(err, twofactor.ErrTwoFactorEnforced)
+	})
+}
+
+func TestTwoFactorServiceTestsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TwoFactorServiceTestsSuite))
+}
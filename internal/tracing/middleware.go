@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"github.com/nikitalystsev/BookSmart/internal/session"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// Middleware открывает серверный спан на каждый запрос и кладет его в контекст
+// запроса, откуда его получают обработчики, сервисы и репозитории. Входящий
+// traceparent продолжается, а контекст трассировки возвращается клиенту в ответе
+func Middleware(provider *Provider, matcher *middleware.RouteMatcher) gin.HandlerFunc {
+	tracer := provider.tracer()
+
+	return func(c *gin.Context) {
+		request := c.Request
+		route := matcher.Match(request.Method, request.URL.Path)
+
+		ctx := Propagator.Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+		ctx, span := tracer.Start(ctx, request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(request.URL.Path),
+				semconv.ClientAddress(session.ClientIP(request)),
+				semconv.UserAgentOriginal(request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		Propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+		c.Request = request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"github.com/XSAM/otelsql"
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// OpenPostgres открывает соединение с Postgres через инструментированный
+// драйвер: каждый запрос получает спан с текстом SQL в db.statement
+func OpenPostgres(ctx context.Context, dsn string, provider *Provider) (*sqlx.DB, error) {
+	db, err := otelsql.Open("postgres", dsn,
+		otelsql.WithTracerProvider(provider),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := sqlx.NewDb(db, "postgres")
+	if err = client.PingContext(ctx); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
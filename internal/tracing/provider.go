@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"os"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	tracerName = "github.com/nikitalystsev/BookSmart"
+)
+
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Propagator переносит контекст трассировки между сервисами в заголовках
+// W3C traceparent и baggage
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+type Options struct {
+	Exporter    string
+	ServiceName string
+	SampleRatio float64
+
+	OTLPEndpoint string
+	OTLPInsecure bool
+	OTLPHeaders  map[string]string
+}
+
+// Provider - провайдер трассировки приложения. При выключенной трассировке
+// спаны не создаются, а Shutdown ничего не делает
+type Provider struct {
+	trace.TracerProvider
+	sdk *sdktrace.TracerProvider
+}
+
+func NewProvider(ctx context.Context, opts Options) (*Provider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return &Provider{TracerProvider: noop.NewTracerProvider()}, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx, otlpOptions(opts)...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, opts.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSDKProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)), nil
+}
+
+// NewSDKProvider оборачивает готовый провайдер SDK, например с
+// tracetest.SpanRecorder в тестах
+func NewSDKProvider(sdk *sdktrace.TracerProvider) *Provider {
+	return &Provider{TracerProvider: sdk, sdk: sdk}
+}
+
+// Enabled сообщает, записываются ли спаны
+func (p *Provider) Enabled() bool {
+	return p.sdk != nil
+}
+
+// Shutdown отправляет накопленные спаны и останавливает экспортер
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.sdk == nil {
+		return nil
+	}
+
+	return p.sdk.Shutdown(ctx)
+}
+
+// otlpOptions собирает настройки OTLP/HTTP; пустой endpoint оставляет
+// экспортеру стандартные переменные OTEL_EXPORTER_OTLP_*
+func otlpOptions(opts Options) []otlptracehttp.Option {
+	var options []otlptracehttp.Option
+	if opts.OTLPEndpoint != "" {
+		options = append(options, otlptracehttp.WithEndpoint(opts.OTLPEndpoint))
+	}
+	if opts.OTLPInsecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	if len(opts.OTLPHeaders) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(opts.OTLPHeaders))
+	}
+
+	return options
+}
+
+func (p *Provider) tracer() trace.Tracer {
+	return p.Tracer(tracerName)
+}
@@ -0,0 +1,410 @@
+package tracing
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/dto"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/account"
+	"github.com/nikitalystsev/BookSmart/internal/favorite"
+	"github.com/nikitalystsev/BookSmart/internal/readinglist"
+	"github.com/nikitalystsev/BookSmart/internal/twofactor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// Декораторы репозиториев открывают клиентский спан на каждый вызов. Текст
+// SQL-запросов добавляют дочерние спаны otelsql (см. OpenPostgres); клиент
+// Mongo создается внешним модулем без монитора команд, поэтому для Mongo
+// спан описывает коллекцию и операцию репозитория
+
+type repoTracer struct {
+	tracer     trace.Tracer
+	attributes []attribute.KeyValue
+	repo       string
+}
+
+func newRepoTracer(provider *Provider, backend, repo string) repoTracer {
+	var system attribute.KeyValue
+	switch backend {
+	case "postgres":
+		system = semconv.DBSystemPostgreSQL
+	case "mongo":
+		system = semconv.DBSystemMongoDB
+	default:
+		system = semconv.DBSystemKey.String(backend)
+	}
+
+	return repoTracer{
+		tracer:     provider.tracer(),
+		attributes: []attribute.KeyValue{system, semconv.DBCollectionName(repo)},
+		repo:       repo,
+	}
+}
+
+func (t *repoTracer) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, t.repo+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.attributes...),
+		trace.WithAttributes(semconv.DBOperationName(method)),
+	)
+}
+
+// finish вызывается через defer с указателем на возвращаемую ошибку,
+// значение которой известно только после return
+func finish(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+type BookRepo struct {
+	repoTracer
+	next intfRepo.IBookRepo
+}
+
+func NewBookRepo(next intfRepo.IBookRepo, provider *Provider, backend string) intfRepo.IBookRepo {
+	return &BookRepo{repoTracer: newRepoTracer(provider, backend, "book"), next: next}
+}
+
+func (r *BookRepo) Create(ctx context.Context, book *models.BookModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, book)
+}
+
+func (r *BookRepo) GetByID(ctx context.Context, ID uuid.UUID) (book *models.BookModel, err error) {
+	ctx, span := r.start(ctx, "GetByID")
+	defer finish(span, &err)
+	return r.next.GetByID(ctx, ID)
+}
+
+func (r *BookRepo) GetByTitle(ctx context.Context, title string) (book *models.BookModel, err error) {
+	ctx, span := r.start(ctx, "GetByTitle")
+	defer finish(span, &err)
+	return r.next.GetByTitle(ctx, title)
+}
+
+func (r *BookRepo) Delete(ctx context.Context, ID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "Delete")
+	defer finish(span, &err)
+	return r.next.Delete(ctx, ID)
+}
+
+func (r *BookRepo) Update(ctx context.Context, book *models.BookModel) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer finish(span, &err)
+	return r.next.Update(ctx, book)
+}
+
+func (r *BookRepo) GetByParams(ctx context.Context, params *dto.BookParamsDTO) (books []*models.BookModel, err error) {
+	ctx, span := r.start(ctx, "GetByParams")
+	defer finish(span, &err)
+	return r.next.GetByParams(ctx, params)
+}
+
+type LibCardRepo struct {
+	repoTracer
+	next intfRepo.ILibCardRepo
+}
+
+func NewLibCardRepo(next intfRepo.ILibCardRepo, provider *Provider, backend string) intfRepo.ILibCardRepo {
+	return &LibCardRepo{repoTracer: newRepoTracer(provider, backend, "lib_card"), next: next}
+}
+
+func (r *LibCardRepo) Create(ctx context.Context, libCard *models.LibCardModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, libCard)
+}
+
+func (r *LibCardRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (libCard *models.LibCardModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderID")
+	defer finish(span, &err)
+	return r.next.GetByReaderID(ctx, readerID)
+}
+
+func (r *LibCardRepo) GetByNum(ctx context.Context, libCardNum string) (libCard *models.LibCardModel, err error) {
+	ctx, span := r.start(ctx, "GetByNum")
+	defer finish(span, &err)
+	return r.next.GetByNum(ctx, libCardNum)
+}
+
+func (r *LibCardRepo) Update(ctx context.Context, libCard *models.LibCardModel) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer finish(span, &err)
+	return r.next.Update(ctx, libCard)
+}
+
+type RatingRepo struct {
+	repoTracer
+	next intfRepo.IRatingRepo
+}
+
+func NewRatingRepo(next intfRepo.IRatingRepo, provider *Provider, backend string) intfRepo.IRatingRepo {
+	return &RatingRepo{repoTracer: newRepoTracer(provider, backend, "rating"), next: next}
+}
+
+func (r *RatingRepo) Create(ctx context.Context, rating *models.RatingModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, rating)
+}
+
+func (r *RatingRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (rating *models.RatingModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderAndBook")
+	defer finish(span, &err)
+	return r.next.GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *RatingRepo) GetByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int) (ratings []*models.RatingModel, err error) {
+	ctx, span := r.start(ctx, "GetByBookID")
+	defer finish(span, &err)
+	return r.next.GetByBookID(ctx, bookID, limit, offset)
+}
+
+type ReaderRepo struct {
+	repoTracer
+	next intfRepo.IReaderRepo
+}
+
+func NewReaderRepo(next intfRepo.IReaderRepo, provider *Provider, backend string) intfRepo.IReaderRepo {
+	return &ReaderRepo{repoTracer: newRepoTracer(provider, backend, "reader"), next: next}
+}
+
+func (r *ReaderRepo) Create(ctx context.Context, reader *models.ReaderModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, reader)
+}
+
+func (r *ReaderRepo) GetByPhoneNumber(ctx context.Context, phoneNumber string) (reader *models.ReaderModel, err error) {
+	ctx, span := r.start(ctx, "GetByPhoneNumber")
+	defer finish(span, &err)
+	return r.next.GetByPhoneNumber(ctx, phoneNumber)
+}
+
+func (r *ReaderRepo) GetByID(ctx context.Context, id uuid.UUID) (reader *models.ReaderModel, err error) {
+	ctx, span := r.start(ctx, "GetByID")
+	defer finish(span, &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *ReaderRepo) IsFavorite(ctx context.Context, readerID, bookID uuid.UUID) (isFavorite bool, err error) {
+	ctx, span := r.start(ctx, "IsFavorite")
+	defer finish(span, &err)
+	return r.next.IsFavorite(ctx, readerID, bookID)
+}
+
+func (r *ReaderRepo) AddToFavorites(ctx context.Context, readerID, bookID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "AddToFavorites")
+	defer finish(span, &err)
+	return r.next.AddToFavorites(ctx, readerID, bookID)
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, id uuid.UUID, token string, ttl time.Duration) (err error) {
+	ctx, span := r.start(ctx, "SaveRefreshToken")
+	defer finish(span, &err)
+	return r.next.SaveRefreshToken(ctx, id, token, ttl)
+}
+
+func (r *ReaderRepo) GetByRefreshToken(ctx context.Context, token string) (reader *models.ReaderModel, err error) {
+	ctx, span := r.start(ctx, "GetByRefreshToken")
+	defer finish(span, &err)
+	return r.next.GetByRefreshToken(ctx, token)
+}
+
+type ReservationRepo struct {
+	repoTracer
+	next intfRepo.IReservationRepo
+}
+
+func NewReservationRepo(next intfRepo.IReservationRepo, provider *Provider, backend string) intfRepo.IReservationRepo {
+	return &ReservationRepo{repoTracer: newRepoTracer(provider, backend, "reservation"), next: next}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, reservation *models.ReservationModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, reservation)
+}
+
+func (r *ReservationRepo) GetByReaderAndBook(ctx context.Context, readerID, bookID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderAndBook")
+	defer finish(span, &err)
+	return r.next.GetByReaderAndBook(ctx, readerID, bookID)
+}
+
+func (r *ReservationRepo) GetByID(ctx context.Context, ID uuid.UUID) (reservation *models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetByID")
+	defer finish(span, &err)
+	return r.next.GetByID(ctx, ID)
+}
+
+func (r *ReservationRepo) GetByBookID(ctx context.Context, bookID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetByBookID")
+	defer finish(span, &err)
+	return r.next.GetByBookID(ctx, bookID)
+}
+
+func (r *ReservationRepo) Update(ctx context.Context, reservation *models.ReservationModel) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer finish(span, &err)
+	return r.next.Update(ctx, reservation)
+}
+
+func (r *ReservationRepo) GetExpiredByReaderID(ctx context.Context, readerID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetExpiredByReaderID")
+	defer finish(span, &err)
+	return r.next.GetExpiredByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetActiveByReaderID(ctx context.Context, readerID uuid.UUID) (reservations []*models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetActiveByReaderID")
+	defer finish(span, &err)
+	return r.next.GetActiveByReaderID(ctx, readerID)
+}
+
+func (r *ReservationRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) (reservations []*models.ReservationModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderID")
+	defer finish(span, &err)
+	return r.next.GetByReaderID(ctx, readerID, limit, offset)
+}
+
+type ReadingListRepo struct {
+	repoTracer
+	next readinglist.IReadingListRepo
+}
+
+func NewReadingListRepo(next readinglist.IReadingListRepo, provider *Provider, backend string) readinglist.IReadingListRepo {
+	return &ReadingListRepo{repoTracer: newRepoTracer(provider, backend, "reading_list"), next: next}
+}
+
+func (r *ReadingListRepo) Create(ctx context.Context, readingList *readinglist.ReadingListModel) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer finish(span, &err)
+	return r.next.Create(ctx, readingList)
+}
+
+func (r *ReadingListRepo) GetByID(ctx context.Context, ID uuid.UUID) (readingList *readinglist.ReadingListModel, err error) {
+	ctx, span := r.start(ctx, "GetByID")
+	defer finish(span, &err)
+	return r.next.GetByID(ctx, ID)
+}
+
+func (r *ReadingListRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (readingLists []*readinglist.ReadingListModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderID")
+	defer finish(span, &err)
+	return r.next.GetByReaderID(ctx, readerID)
+}
+
+func (r *ReadingListRepo) GetByShareToken(ctx context.Context, shareToken string) (readingList *readinglist.ReadingListModel, err error) {
+	ctx, span := r.start(ctx, "GetByShareToken")
+	defer finish(span, &err)
+	return r.next.GetByShareToken(ctx, shareToken)
+}
+
+func (r *ReadingListRepo) Update(ctx context.Context, readingList *readinglist.ReadingListModel) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer finish(span, &err)
+	return r.next.Update(ctx, readingList)
+}
+
+func (r *ReadingListRepo) Delete(ctx context.Context, ID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "Delete")
+	defer finish(span, &err)
+	return r.next.Delete(ctx, ID)
+}
+
+type FavoriteBookRepo struct {
+	repoTracer
+	next favorite.IFavoriteBookRepo
+}
+
+func NewFavoriteBookRepo(next favorite.IFavoriteBookRepo, provider *Provider, backend string) favorite.IFavoriteBookRepo {
+	return &FavoriteBookRepo{repoTracer: newRepoTracer(provider, backend, "favorite_books"), next: next}
+}
+
+func (r *FavoriteBookRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID, limit, offset int) (books []*models.BookModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderID")
+	defer finish(span, &err)
+	return r.next.GetByReaderID(ctx, readerID, limit, offset)
+}
+
+func (r *FavoriteBookRepo) Delete(ctx context.Context, readerID, bookID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "Delete")
+	defer finish(span, &err)
+	return r.next.Delete(ctx, readerID, bookID)
+}
+
+type AccountRepo struct {
+	repoTracer
+	next account.IAccountRepo
+}
+
+func NewAccountRepo(next account.IAccountRepo, provider *Provider, backend string) account.IAccountRepo {
+	return &AccountRepo{repoTracer: newRepoTracer(provider, backend, "account"), next: next}
+}
+
+func (r *AccountRepo) Update(ctx context.Context, reader *models.ReaderModel) (err error) {
+	ctx, span := r.start(ctx, "Update")
+	defer finish(span, &err)
+	return r.next.Update(ctx, reader)
+}
+
+func (r *AccountRepo) ReplacePasswordHash(ctx context.Context, readerID uuid.UUID, oldHash, newHash string) (err error) {
+	ctx, span := r.start(ctx, "ReplacePasswordHash")
+	defer finish(span, &err)
+	return r.next.ReplacePasswordHash(ctx, readerID, oldHash, newHash)
+}
+
+func (r *AccountRepo) Delete(ctx context.Context, readerID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "Delete")
+	defer finish(span, &err)
+	return r.next.Delete(ctx, readerID)
+}
+
+type TwoFactorRepo struct {
+	repoTracer
+	next twofactor.ITwoFactorRepo
+}
+
+func NewTwoFactorRepo(next twofactor.ITwoFactorRepo, provider *Provider, backend string) twofactor.ITwoFactorRepo {
+	return &TwoFactorRepo{repoTracer: newRepoTracer(provider, backend, "two_factor"), next: next}
+}
+
+func (r *TwoFactorRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (twoFactor *twofactor.TwoFactorModel, err error) {
+	ctx, span := r.start(ctx, "GetByReaderID")
+	defer finish(span, &err)
+	return r.next.GetByReaderID(ctx, readerID)
+}
+
+func (r *TwoFactorRepo) Save(ctx context.Context, twoFactor *twofactor.TwoFactorModel) (err error) {
+	ctx, span := r.start(ctx, "Save")
+	defer finish(span, &err)
+	return r.next.Save(ctx, twoFactor)
+}
+
+func (r *TwoFactorRepo) Delete(ctx context.Context, readerID uuid.UUID) (err error) {
+	ctx, span := r.start(ctx, "Delete")
+	defer finish(span, &err)
+	return r.next.Delete(ctx, readerID)
+}
+
+func (r *TwoFactorRepo) UseStep(ctx context.Context, readerID uuid.UUID, step int64) (used bool, err error) {
+	ctx, span := r.start(ctx, "UseStep")
+	defer finish(span, &err)
+	return r.next.UseStep(ctx, readerID, step)
+}
+
+func (r *TwoFactorRepo) UseRecoveryCode(ctx context.Context, readerID uuid.UUID, codeHash string) (used bool, err error) {
+	ctx, span := r.start(ctx, "UseRecoveryCode")
+	defer finish(span, &err)
+	return r.next.UseRecoveryCode(ctx, readerID, codeHash)
+}
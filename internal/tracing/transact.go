@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+	"github.com/nikitalystsev/BookSmart-services/pkg/transact"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TransactionManager открывает спан на транзакцию; вызовы репозиториев внутри
+// fn становятся его дочерними спанами
+type TransactionManager struct {
+	tracer trace.Tracer
+	next   transact.ITransactionManager
+}
+
+func NewTransactionManager(next transact.ITransactionManager, provider *Provider) transact.ITransactionManager {
+	return &TransactionManager{tracer: provider.tracer(), next: next}
+}
+
+func (tm *TransactionManager) Do(ctx context.Context, fn func(context.Context) error) (err error) {
+	ctx, span := tm.tracer.Start(ctx, "transaction", trace.WithSpanKind(trace.SpanKindInternal))
+	defer finish(span, &err)
+	return tm.next.Do(ctx, fn)
+}
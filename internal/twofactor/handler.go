@@ -0,0 +1,257 @@
+package twofactor
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/nikitalystsev/BookSmart-services/errs"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/internal/middleware"
+	"net/http"
+)
+
+type Handler struct {
+	twoFactorService ITwoFactorService
+	tokenManager     auth.ITokenManager
+}
+
+func NewHandler(twoFactorService ITwoFactorService, tokenManager auth.ITokenManager) *Handler {
+	return &Handler{twoFactorService: twoFactorService, tokenManager: tokenManager}
+}
+
+func (h *Handler) InitRoutes(router *gin.Engine) {
+	api := router.Group("/api/v1")
+	{
+		auth := api.Group("/auth/sign-in/2fa")
+		{
+			auth.POST("", h.verifyChallenge)
+			auth.POST("/enroll", h.enrollChallenge)
+		}
+
+		twoFactor := api.Group("/readers/:id/2fa",
+			middleware.Authenticate(h.tokenManager), middleware.RequireSameReader())
+		{
+			twoFactor.POST("", h.enroll)
+			twoFactor.POST("/confirm", h.confirm)
+			twoFactor.DELETE("", h.disable)
+			twoFactor.POST("/recovery_codes", h.regenerateRecoveryCodes)
+		}
+	}
+}
+
+// @Summary Метод подтверждения входа кодом второго фактора
+// @Tags auth
+// @ID verifyTwoFactorChallenge
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorChallengeInputDTO true "Challenge-токен и код из приложения или код восстановления"
+// @Success 200 {object} dto.TwoFactorSignInOutputDTO "Успешный вход; при первой настройке 2FA ответ содержит recovery_codes"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Неверный код или истекший challenge-токен"
+// @Failure 429 {object} dto.ErrorResponse "Превышено число попыток или читатель временно заблокирован"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/sign-in/2fa [post]
+func (h *Handler) verifyChallenge(c *gin.Context) {
+	var input dto.TwoFactorChallengeInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	signIn, err := h.twoFactorService.VerifyChallenge(c.Request.Context(), input.ChallengeToken, input.Code)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.TwoFactorSignInOutputDTO{
+		AccessToken:   signIn.AccessToken,
+		RefreshToken:  signIn.RefreshToken,
+		ExpiredAt:     signIn.ExpiredAt,
+		ReaderID:      signIn.ReaderID,
+		RecoveryCodes: signIn.RecoveryCodes,
+	})
+}
+
+// @Summary Метод настройки 2FA при входе, если она обязательна для роли
+// @Tags auth
+// @ID enrollTwoFactorChallenge
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorChallengeEnrollInputDTO true "Challenge-токен"
+// @Success 200 {object} dto.TwoFactorEnrollmentOutputDTO "Секрет и otpauth-ссылка для приложения"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 401 {object} dto.ErrorResponse "Истекший challenge-токен"
+// @Failure 409 {object} dto.ErrorResponse "2FA уже настроена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/auth/sign-in/2fa/enroll [post]
+func (h *Handler) enrollChallenge(c *gin.Context) {
+	var input dto.TwoFactorChallengeEnrollInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	enrollment, err := h.twoFactorService.EnrollChallenge(c.Request.Context(), input.ChallengeToken)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEnrollmentDTO(enrollment))
+}
+
+// @Summary Метод начала настройки 2FA
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID enrollTwoFactor
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Success 200 {object} dto.TwoFactorEnrollmentOutputDTO "Секрет и otpauth-ссылка для приложения"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 409 {object} dto.ErrorResponse "2FA уже включена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/2fa [post]
+func (h *Handler) enroll(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	enrollment, err := h.twoFactorService.Enroll(c.Request.Context(), readerID)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEnrollmentDTO(enrollment))
+}
+
+// @Summary Метод включения 2FA кодом из приложения
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID confirmTwoFactor
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.TwoFactorCodeInputDTO true "Код из приложения"
+// @Success 200 {object} dto.RecoveryCodesOutputDTO "Коды восстановления"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или настройка не начата"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь или неверный код"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 409 {object} dto.ErrorResponse "2FA уже включена"
+// @Failure 429 {object} dto.ErrorResponse "Читатель временно заблокирован из-за неверных кодов"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/2fa/confirm [post]
+func (h *Handler) confirm(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.TwoFactorCodeInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.Confirm(c.Request.Context(), readerID, input.Code)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.RecoveryCodesOutputDTO{RecoveryCodes: recoveryCodes})
+}
+
+// @Summary Метод отключения 2FA
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID disableTwoFactor
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.TwoFactorCodeInputDTO true "Код из приложения или код восстановления"
+// @Success 204 "Успешное отключение 2FA"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или 2FA не включена"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь или неверный код"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен или 2FA обязательна для роли"
+// @Failure 429 {object} dto.ErrorResponse "Читатель временно заблокирован из-за неверных кодов"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/2fa [delete]
+func (h *Handler) disable(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+	role, _ := middleware.GetRole(c)
+
+	var input dto.TwoFactorCodeInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), readerID, role, input.Code); err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Метод выпуска новых кодов восстановления
+// @Security ApiKeyAuth
+// @Tags reader_account
+// @ID regenerateRecoveryCodes
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор читателя"
+// @Param input body dto.TwoFactorCodeInputDTO true "Код из приложения или код восстановления"
+// @Success 200 {object} dto.RecoveryCodesOutputDTO "Новые коды восстановления, старые больше не действуют"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или 2FA не включена"
+// @Failure 401 {object} dto.ErrorResponse "Неавторизованный пользователь или неверный код"
+// @Failure 403 {object} dto.ErrorResponse "Доступ запрещен"
+// @Failure 429 {object} dto.ErrorResponse "Читатель временно заблокирован из-за неверных кодов"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /api/v1/readers/{id}/2fa/recovery_codes [post]
+func (h *Handler) regenerateRecoveryCodes(c *gin.Context) {
+	readerID, _ := middleware.GetReaderID(c)
+
+	var input dto.TwoFactorCodeInputDTO
+	if err := c.BindJSON(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{ErrorMsg: err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), readerID, input.Code)
+	if err != nil {
+		h.errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.RecoveryCodesOutputDTO{RecoveryCodes: recoveryCodes})
+}
+
+func (h *Handler) errorResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, ErrTwoFactorNotEnabled),
+		errors.Is(err, ErrTwoFactorNotEnrolled):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrInvalidTwoFactorCode),
+		errors.Is(err, ErrInvalidChallenge):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrTwoFactorEnforced):
+		status = http.StatusForbidden
+	case errors.Is(err, errs.ErrReaderDoesNotExists):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrTwoFactorAlreadyEnabled):
+		status = http.StatusConflict
+	case errors.Is(err, ErrChallengeAttemptsExceeded),
+		errors.Is(err, ErrTwoFactorLocked):
+		status = http.StatusTooManyRequests
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{ErrorMsg: err.Error()})
+}
+
+func toEnrollmentDTO(enrollment *EnrollmentModel) *dto.TwoFactorEnrollmentOutputDTO {
+	return &dto.TwoFactorEnrollmentOutputDTO{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+	}
+}
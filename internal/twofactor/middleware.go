@@ -0,0 +1,85 @@
+package twofactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"net/http"
+)
+
+const signInPath = "/api/v1/auth/sign-in"
+
+type challengeCtxKey struct{}
+
+// challengeFlag отмечает запрос входа, на который вместо токенов выдан challenge
+type challengeFlag struct {
+	challenge *dto.TwoFactorChallengeOutputDTO
+	issued    bool
+}
+
+// WithChallengeFlag готовит контекст запроса к отметке о выданном challenge.
+// Ее читают обертки снаружи SignInGuard: пока код не проверен, вход не завершен
+func WithChallengeFlag(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(challengeCtxKey{}).(*challengeFlag); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, challengeCtxKey{}, &challengeFlag{})
+}
+
+// ChallengeIssued сообщает, что SignInGuard ответил на вход challenge-токеном
+func ChallengeIssued(ctx context.Context) bool {
+	flag, ok := ctx.Value(challengeCtxKey{}).(*challengeFlag)
+
+	return ok && flag.issued
+}
+
+// bufferedWriter задерживает ответ обработчика входа, чтобы заменить его на challenge
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// SignInGuard делает вход двухшаговым. Обработчик входа находится во внешнем модуле:
+// если читателю нужен второй фактор, ReaderRepo не сохраняет его refresh-токен
+// и не создает сессию, а открывает challenge. Тогда ответ обработчика с
+// токенами отбрасывается, и клиент получает challenge-токен
+func SignInGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != signInPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.WithContext(WithChallengeFlag(r.Context()))
+		flag := r.Context().Value(challengeCtxKey{}).(*challengeFlag)
+
+		buffered := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		if buffered.status == http.StatusOK && flag.challenge != nil {
+			flag.issued = true
+			writeJSON(w, http.StatusOK, flag.challenge)
+			return
+		}
+
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
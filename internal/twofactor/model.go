@@ -0,0 +1,68 @@
+package twofactor
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	Issuer             = "BookSmart"
+	RecoveryCodesCount = 10
+
+	DefaultChallengeTTL         = 5 * time.Minute
+	DefaultMaxChallengeAttempts = 5
+	DefaultMaxFailures          = 10
+	DefaultLockoutDuration      = 15 * time.Minute
+
+	// допуск в один 30-секундный интервал на расхождение часов
+	codeSkew = 1
+)
+
+var (
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two-factor enrolment was not started")
+	ErrTwoFactorEnforced         = errors.New("two-factor authentication is required for this role")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrInvalidChallenge          = errors.New("invalid or expired two-factor challenge")
+	ErrChallengeAttemptsExceeded = errors.New("two-factor challenge attempts exceeded")
+	ErrTwoFactorLocked           = errors.New("too many invalid two-factor codes, try again later")
+)
+
+// TwoFactorModel - настройки TOTP читателя. Пока Enabled = false, секрет
+// выдан, но владение им еще не подтверждено кодом. Хранятся только хэши
+// кодов восстановления, использованный код удаляется из списка
+type TwoFactorModel struct {
+	ReaderID           uuid.UUID
+	Secret             string
+	Enabled            bool
+	RecoveryCodeHashes []string
+	LastUsedStep       int64
+}
+
+type EnrollmentModel struct {
+	Secret string
+	URI    string
+}
+
+// ChallengeModel - вход, ожидающий второго фактора. Токены и сессия
+// создаются только после проверки кода, поэтому в challenge нет секретов.
+// По PhoneNumber после проверки сбрасываются ограничения попыток входа
+type ChallengeModel struct {
+	ReaderID           uuid.UUID
+	PhoneNumber        string
+	Role               string
+	EnrollmentRequired bool
+	Attempts           int
+}
+
+// SignInModel - вход, завершенный проверкой второго фактора. RecoveryCodes
+// заполняются, если 2FA включалась в рамках этого входа
+type SignInModel struct {
+	ReaderID      uuid.UUID
+	AccessToken   string
+	RefreshToken  string
+	ExpiredAt     int64
+	RecoveryCodes []string
+}
@@ -0,0 +1,125 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const twoFactorCollection = "reader_two_factor"
+
+type MongoRepo struct {
+	collection *mongo.Collection
+	logger     *logrus.Entry
+}
+
+func NewMongoRepo(db *mongo.Database, logger *logrus.Entry) ITwoFactorRepo {
+	return &MongoRepo{collection: db.Collection(twoFactorCollection), logger: logger}
+}
+
+type twoFactorDocument struct {
+	ReaderID      string   `bson:"_id"`
+	Secret        string   `bson:"secret"`
+	Enabled       bool     `bson:"enabled"`
+	RecoveryCodes []string `bson:"recovery_codes"`
+	LastUsedStep  int64    `bson:"last_used_step"`
+}
+
+func (r *MongoRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*TwoFactorModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader two-factor settings")
+
+	var document twoFactorDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": readerID.String()}).Decode(&document)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Errorf("error selecting reader two-factor settings: %v", err)
+		return nil, err
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Warn("reader two-factor settings not found")
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	return &TwoFactorModel{
+		ReaderID:           readerID,
+		Secret:             document.Secret,
+		Enabled:            document.Enabled,
+		RecoveryCodeHashes: document.RecoveryCodes,
+		LastUsedStep:       document.LastUsedStep,
+	}, nil
+}
+
+func (r *MongoRepo) Save(ctx context.Context, twoFactor *TwoFactorModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("saving reader two-factor settings")
+
+	document := &twoFactorDocument{
+		ReaderID:      twoFactor.ReaderID.String(),
+		Secret:        twoFactor.Secret,
+		Enabled:       twoFactor.Enabled,
+		RecoveryCodes: twoFactor.RecoveryCodeHashes,
+		LastUsedStep:  twoFactor.LastUsedStep,
+	}
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": document.ReaderID}, document,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		logger.Errorf("error saving reader two-factor settings: %v", err)
+		return err
+	}
+
+	logger.Info("successfully saved reader two-factor settings")
+
+	return nil
+}
+
+func (r *MongoRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader two-factor settings")
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": readerID.String()}); err != nil {
+		logger.Errorf("error deleting reader two-factor settings: %v", err)
+		return err
+	}
+
+	logger.Info("successfully deleted reader two-factor settings")
+
+	return nil
+}
+
+func (r *MongoRepo) UseStep(ctx context.Context, readerID uuid.UUID, step int64) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader two-factor last used step")
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": readerID.String(), "last_used_step": bson.M{"$lt": step}},
+		bson.M{"$set": bson.M{"last_used_step": step}},
+	)
+	if err != nil {
+		logger.Errorf("error updating reader two-factor last used step: %v", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *MongoRepo) UseRecoveryCode(ctx context.Context, readerID uuid.UUID, codeHash string) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("removing reader recovery code")
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": readerID.String(), "recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"recovery_codes": codeHash}},
+	)
+	if err != nil {
+		logger.Errorf("error removing reader recovery code: %v", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,154 @@
+package twofactor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepo struct {
+	db     *sqlx.DB
+	getter *trmsqlx.CtxGetter
+	logger *logrus.Entry
+}
+
+func NewPostgresRepo(db *sqlx.DB, logger *logrus.Entry) ITwoFactorRepo {
+	return &PostgresRepo{db: db, getter: trmsqlx.DefaultCtxGetter, logger: logger}
+}
+
+type twoFactorRow struct {
+	ReaderID      uuid.UUID      `db:"reader_id"`
+	Secret        string         `db:"secret"`
+	Enabled       bool           `db:"enabled"`
+	RecoveryCodes pq.StringArray `db:"recovery_codes"`
+	LastUsedStep  int64          `db:"last_used_step"`
+}
+
+func (r *PostgresRepo) GetByReaderID(ctx context.Context, readerID uuid.UUID) (*TwoFactorModel, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("selecting reader two-factor settings")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `select reader_id, secret, enabled, recovery_codes, last_used_step 
+			  from bs.reader_two_factor 
+			  where reader_id = $1`
+
+	var row twoFactorRow
+	err := tr.GetContext(ctx, &row, query, readerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Errorf("error selecting reader two-factor settings: %v", err)
+		return nil, err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Warn("reader two-factor settings not found")
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	return &TwoFactorModel{
+		ReaderID:           row.ReaderID,
+		Secret:             row.Secret,
+		Enabled:            row.Enabled,
+		RecoveryCodeHashes: row.RecoveryCodes,
+		LastUsedStep:       row.LastUsedStep,
+	}, nil
+}
+
+func (r *PostgresRepo) Save(ctx context.Context, twoFactor *TwoFactorModel) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("saving reader two-factor settings")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `insert into bs.reader_two_factor values ($1, $2, $3, $4, $5)
+			  on conflict (reader_id) do update 
+			  set secret = excluded.secret, 
+			      enabled = excluded.enabled, 
+			      recovery_codes = excluded.recovery_codes, 
+			      last_used_step = excluded.last_used_step`
+
+	_, err := tr.ExecContext(ctx, query, twoFactor.ReaderID, twoFactor.Secret, twoFactor.Enabled,
+		pq.StringArray(twoFactor.RecoveryCodeHashes), twoFactor.LastUsedStep)
+	if err != nil {
+		logger.Errorf("error saving reader two-factor settings: %v", err)
+		return err
+	}
+
+	logger.Info("successfully saved reader two-factor settings")
+
+	return nil
+}
+
+func (r *PostgresRepo) Delete(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("deleting reader two-factor settings")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `delete from bs.reader_two_factor where reader_id = $1`
+
+	if _, err := tr.ExecContext(ctx, query, readerID); err != nil {
+		logger.Errorf("error deleting reader two-factor settings: %v", err)
+		return err
+	}
+
+	logger.Info("successfully deleted reader two-factor settings")
+
+	return nil
+}
+
+func (r *PostgresRepo) UseStep(ctx context.Context, readerID uuid.UUID, step int64) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("updating reader two-factor last used step")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader_two_factor 
+			  set last_used_step = $2 
+			  where reader_id = $1 and last_used_step < $2`
+
+	result, err := tr.ExecContext(ctx, query, readerID, step)
+	if err != nil {
+		logger.Errorf("error updating reader two-factor last used step: %v", err)
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("error getting affected rows: %v", err)
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *PostgresRepo) UseRecoveryCode(ctx context.Context, readerID uuid.UUID, codeHash string) (bool, error) {
+	logger := logging.FromContext(ctx, r.logger)
+	logger.Info("removing reader recovery code")
+
+	tr := r.getter.DefaultTrOrDB(ctx, r.db)
+
+	query := `update bs.reader_two_factor 
+			  set recovery_codes = array_remove(recovery_codes, $2) 
+			  where reader_id = $1 and $2 = any(recovery_codes)`
+
+	result, err := tr.ExecContext(ctx, query, readerID, codeHash)
+	if err != nil {
+		logger.Errorf("error removing reader recovery code: %v", err)
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("error getting affected rows: %v", err)
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,56 @@
+package twofactor
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart/internal/dto"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// ReaderRepo откладывает вход читателя со вторым фактором: при входе через
+// SignInGuard вместо сохранения refresh-токена, которое создает сессию,
+// открывается challenge. Токены, выданные обработчиком входа, в этом случае
+// никуда не сохраняются и не отдаются клиенту
+type ReaderRepo struct {
+	intfRepo.IReaderRepo
+	twoFactorService ITwoFactorService
+	logger           *logrus.Entry
+}
+
+func NewReaderRepo(readerRepo intfRepo.IReaderRepo, twoFactorService ITwoFactorService, logger *logrus.Entry) intfRepo.IReaderRepo {
+	return &ReaderRepo{IReaderRepo: readerRepo, twoFactorService: twoFactorService, logger: logger}
+}
+
+func (r *ReaderRepo) SaveRefreshToken(ctx context.Context, ID uuid.UUID, token string, ttl time.Duration) error {
+	flag, ok := ctx.Value(challengeCtxKey{}).(*challengeFlag)
+	if !ok {
+		return r.IReaderRepo.SaveRefreshToken(ctx, ID, token, ttl)
+	}
+
+	logger := logging.FromContext(ctx, r.logger)
+
+	reader, err := r.IReaderRepo.GetByID(ctx, ID)
+	if err != nil {
+		logger.Errorf("error getting reader: %v", err)
+		return err
+	}
+
+	challengeToken, challenge, err := r.twoFactorService.StartChallenge(ctx, reader)
+	if err != nil {
+		return err
+	}
+	if challengeToken == "" {
+		return r.IReaderRepo.SaveRefreshToken(ctx, ID, token, ttl)
+	}
+
+	flag.challenge = &dto.TwoFactorChallengeOutputDTO{
+		TwoFactorRequired:  true,
+		EnrollmentRequired: challenge.EnrollmentRequired,
+		ChallengeToken:     challengeToken,
+	}
+
+	return nil
+}
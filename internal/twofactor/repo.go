@@ -0,0 +1,19 @@
+package twofactor
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+type ITwoFactorRepo interface {
+	GetByReaderID(ctx context.Context, readerID uuid.UUID) (*TwoFactorModel, error)
+	// Save создает или заменяет настройки TOTP читателя
+	Save(ctx context.Context, twoFactor *TwoFactorModel) error
+	Delete(ctx context.Context, readerID uuid.UUID) error
+	// UseStep запоминает интервал использованного кода, если он новее
+	// последнего. false означает, что код уже был принят другим запросом
+	UseStep(ctx context.Context, readerID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode удаляет код восстановления, если он еще не удален.
+	// false означает, что код уже был использован другим запросом
+	UseRecoveryCode(ctx context.Context, readerID uuid.UUID, codeHash string) (bool, error)
+}
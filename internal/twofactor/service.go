@@ -0,0 +1,539 @@
+package twofactor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/core/models"
+	"github.com/nikitalystsev/BookSmart-services/intfRepo"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/nikitalystsev/BookSmart/pkg/totp"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+	"time"
+)
+
+type ITwoFactorService interface {
+	// Enroll выдает новый секрет; 2FA включается только после Confirm
+	Enroll(ctx context.Context, readerID uuid.UUID) (*EnrollmentModel, error)
+	// Confirm проверяет код из приложения, включает 2FA и возвращает коды восстановления
+	Confirm(ctx context.Context, readerID uuid.UUID, code string) ([]string, error)
+	Disable(ctx context.Context, readerID uuid.UUID, role, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, readerID uuid.UUID, code string) ([]string, error)
+
+	// StartChallenge возвращает пустой токен, если второй фактор для читателя не нужен
+	StartChallenge(ctx context.Context, reader *models.ReaderModel) (string, *ChallengeModel, error)
+	// EnrollChallenge выдает секрет при входе, если роль требует 2FA, а читатель ее еще не настроил
+	EnrollChallenge(ctx context.Context, challengeToken string) (*EnrollmentModel, error)
+	// VerifyChallenge проверяет код, создает сессию и выдает токены. Если 2FA
+	// включалась в рамках входа, возвращаются и коды восстановления
+	VerifyChallenge(ctx context.Context, challengeToken, code string) (*SignInModel, error)
+}
+
+// ISignInRecorder завершает вход: сбрасывает ограничения попыток входа по
+// номеру телефона. Вход со вторым фактором завершается только после проверки кода
+type ISignInRecorder interface {
+	Success(ctx context.Context, phoneNumber string) error
+}
+
+type TwoFactorService struct {
+	twoFactorRepo   ITwoFactorRepo
+	challengeStore  IChallengeStore
+	readerRepo      intfRepo.IReaderRepo
+	signInRecorder  ISignInRecorder
+	tokenManager    auth.ITokenManager
+	logger          *logrus.Entry
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	enforcedRoles   []string
+	challengeTTL    time.Duration
+	maxAttempts     int
+	maxFailures     int
+	lockoutDuration time.Duration
+}
+
+func NewTwoFactorService(
+	twoFactorRepo ITwoFactorRepo,
+	challengeStore IChallengeStore,
+	readerRepo intfRepo.IReaderRepo,
+	signInRecorder ISignInRecorder,
+	tokenManager auth.ITokenManager,
+	logger *logrus.Entry,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	enforcedRoles []string,
+	challengeTTL time.Duration,
+	maxAttempts int,
+	maxFailures int,
+	lockoutDuration time.Duration,
+) ITwoFactorService {
+	if challengeTTL <= 0 {
+		challengeTTL = DefaultChallengeTTL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxChallengeAttempts
+	}
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	if lockoutDuration <= 0 {
+		lockoutDuration = DefaultLockoutDuration
+	}
+
+	return &TwoFactorService{
+		twoFactorRepo:   twoFactorRepo,
+		challengeStore:  challengeStore,
+		readerRepo:      readerRepo,
+		signInRecorder:  signInRecorder,
+		tokenManager:    tokenManager,
+		logger:          logger,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+		enforcedRoles:   enforcedRoles,
+		challengeTTL:    challengeTTL,
+		maxAttempts:     maxAttempts,
+		maxFailures:     maxFailures,
+		lockoutDuration: lockoutDuration,
+	}
+}
+
+func (tfs *TwoFactorService) Enroll(ctx context.Context, readerID uuid.UUID) (*EnrollmentModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to enroll reader in two-factor authentication")
+
+	twoFactor, err := tfs.twoFactorRepo.GetByReaderID(ctx, readerID)
+	if err != nil && !errors.Is(err, ErrTwoFactorNotEnabled) {
+		logger.Errorf("error getting reader two-factor settings: %v", err)
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		logger.Warn("two-factor authentication is already enabled")
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return tfs.enroll(ctx, readerID)
+}
+
+func (tfs *TwoFactorService) Confirm(ctx context.Context, readerID uuid.UUID, code string) ([]string, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to confirm two-factor enrolment")
+
+	twoFactor, err := tfs.twoFactorRepo.GetByReaderID(ctx, readerID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) {
+		logger.Warn("two-factor enrolment was not started")
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		logger.Errorf("error getting reader two-factor settings: %v", err)
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		logger.Warn("two-factor authentication is already enabled")
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	recoveryCodes, err := tfs.enable(ctx, twoFactor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("two-factor authentication successfully enabled")
+
+	return recoveryCodes, nil
+}
+
+func (tfs *TwoFactorService) Disable(ctx context.Context, readerID uuid.UUID, role, code string) error {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to disable two-factor authentication")
+
+	if tfs.isEnforced(role) {
+		logger.Warn("two-factor authentication is enforced for role")
+		return ErrTwoFactorEnforced
+	}
+
+	twoFactor, err := tfs.getEnabled(ctx, readerID)
+	if err != nil {
+		return err
+	}
+
+	if err = tfs.verifyCode(ctx, twoFactor, code); err != nil {
+		return err
+	}
+
+	if err = tfs.twoFactorRepo.Delete(ctx, readerID); err != nil {
+		logger.Errorf("error deleting reader two-factor settings: %v", err)
+		return err
+	}
+
+	logger.Info("two-factor authentication successfully disabled")
+
+	return nil
+}
+
+func (tfs *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, readerID uuid.UUID, code string) ([]string, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to regenerate recovery codes")
+
+	twoFactor, err := tfs.getEnabled(ctx, readerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tfs.verifyCode(ctx, twoFactor, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := tfs.setRecoveryCodes(ctx, twoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("recovery codes successfully regenerated")
+
+	return recoveryCodes, nil
+}
+
+func (tfs *TwoFactorService) StartChallenge(ctx context.Context, reader *models.ReaderModel) (string, *ChallengeModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	twoFactor, err := tfs.twoFactorRepo.GetByReaderID(ctx, reader.ID)
+	if err != nil && !errors.Is(err, ErrTwoFactorNotEnabled) {
+		logger.Errorf("error getting reader two-factor settings: %v", err)
+		return "", nil, err
+	}
+
+	enabled := twoFactor != nil && twoFactor.Enabled
+	if !enabled && !tfs.isEnforced(reader.Role) {
+		return "", nil, nil
+	}
+
+	challenge := &ChallengeModel{
+		ReaderID:           reader.ID,
+		PhoneNumber:        reader.PhoneNumber,
+		Role:               reader.Role,
+		EnrollmentRequired: !enabled,
+	}
+
+	challengeToken, err := generateToken()
+	if err != nil {
+		logger.Errorf("error generating two-factor challenge token: %v", err)
+		return "", nil, err
+	}
+
+	if err = tfs.challengeStore.Save(ctx, challengeToken, challenge, tfs.challengeTTL); err != nil {
+		logger.Errorf("error saving two-factor challenge: %v", err)
+		return "", nil, err
+	}
+
+	logger.Info("two-factor challenge created")
+
+	return challengeToken, challenge, nil
+}
+
+func (tfs *TwoFactorService) EnrollChallenge(ctx context.Context, challengeToken string) (*EnrollmentModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to enroll reader during sign-in")
+
+	challenge, err := tfs.challengeStore.Get(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.EnrollmentRequired {
+		logger.Warn("two-factor authentication is already enabled")
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return tfs.enroll(ctx, challenge.ReaderID)
+}
+
+func (tfs *TwoFactorService) VerifyChallenge(ctx context.Context, challengeToken, code string) (*SignInModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+	logger.Info("attempting to verify two-factor challenge")
+
+	challenge, err := tfs.challengeStore.Get(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := tfs.challengeStore.IncrAttempts(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > tfs.maxAttempts {
+		logger.Warn("two-factor challenge attempts exceeded")
+		_ = tfs.challengeStore.Delete(ctx, challengeToken)
+		return nil, ErrChallengeAttemptsExceeded
+	}
+
+	twoFactor, err := tfs.twoFactorRepo.GetByReaderID(ctx, challenge.ReaderID)
+	if errors.Is(err, ErrTwoFactorNotEnabled) && challenge.EnrollmentRequired {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		logger.Errorf("error getting reader two-factor settings: %v", err)
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.EnrollmentRequired && !twoFactor.Enabled {
+		recoveryCodes, err = tfs.enable(ctx, twoFactor, code)
+	} else {
+		err = tfs.verifyCode(ctx, twoFactor, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tfs.challengeStore.Delete(ctx, challengeToken); err != nil {
+		return nil, err
+	}
+
+	signIn, err := tfs.signIn(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	signIn.RecoveryCodes = recoveryCodes
+
+	// при недоступности Redis вход не блокируется
+	if err = tfs.signInRecorder.Success(ctx, challenge.PhoneNumber); err != nil {
+		logger.Errorf("error resetting sign-in throttle: %v", err)
+	}
+
+	logger.Info("two-factor challenge successfully verified")
+
+	return signIn, nil
+}
+
+// signIn выдает токены так же, как обработчик входа: refresh-токен
+// сохраняется в репозитории читателей, который создает для него сессию
+func (tfs *TwoFactorService) signIn(ctx context.Context, challenge *ChallengeModel) (*SignInModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	accessToken, err := tfs.tokenManager.NewJWT(challenge.ReaderID, challenge.Role, tfs.accessTokenTTL)
+	if err != nil {
+		logger.Errorf("error generating access token: %v", err)
+		return nil, err
+	}
+
+	refreshToken, err := tfs.tokenManager.NewRefreshToken()
+	if err != nil {
+		logger.Errorf("error generating refresh token: %v", err)
+		return nil, err
+	}
+
+	if err = tfs.readerRepo.SaveRefreshToken(ctx, challenge.ReaderID, refreshToken, tfs.refreshTokenTTL); err != nil {
+		logger.Errorf("error saving refresh token: %v", err)
+		return nil, err
+	}
+
+	return &SignInModel{
+		ReaderID:     challenge.ReaderID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiredAt:    time.Now().Add(tfs.accessTokenTTL).UnixMilli(),
+	}, nil
+}
+
+func (tfs *TwoFactorService) enroll(ctx context.Context, readerID uuid.UUID) (*EnrollmentModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	reader, err := tfs.readerRepo.GetByID(ctx, readerID)
+	if err != nil {
+		logger.Errorf("error getting reader: %v", err)
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Errorf("error generating totp secret: %v", err)
+		return nil, err
+	}
+
+	twoFactor := &TwoFactorModel{ReaderID: readerID, Secret: secret}
+	if err = tfs.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		logger.Errorf("error saving reader two-factor settings: %v", err)
+		return nil, err
+	}
+
+	logger.Info("two-factor enrolment started")
+
+	return &EnrollmentModel{Secret: secret, URI: totp.URI(Issuer, reader.PhoneNumber, secret)}, nil
+}
+
+func (tfs *TwoFactorService) enable(ctx context.Context, twoFactor *TwoFactorModel, code string) ([]string, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	if err := tfs.countFailure(ctx, twoFactor.ReaderID); err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), codeSkew)
+	if !ok {
+		logger.Warn("invalid two-factor code")
+		return nil, ErrInvalidTwoFactorCode
+	}
+	tfs.resetFailures(ctx, twoFactor.ReaderID)
+
+	twoFactor.Enabled = true
+	twoFactor.LastUsedStep = step
+
+	return tfs.setRecoveryCodes(ctx, twoFactor)
+}
+
+func (tfs *TwoFactorService) setRecoveryCodes(ctx context.Context, twoFactor *TwoFactorModel) ([]string, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	recoveryCodes := make([]string, RecoveryCodesCount)
+	twoFactor.RecoveryCodeHashes = make([]string, RecoveryCodesCount)
+	for i := range recoveryCodes {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			logger.Errorf("error generating recovery code: %v", err)
+			return nil, err
+		}
+		recoveryCodes[i] = recoveryCode
+		twoFactor.RecoveryCodeHashes[i] = hashRecoveryCode(recoveryCode)
+	}
+
+	if err := tfs.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		logger.Errorf("error saving reader two-factor settings: %v", err)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// verifyCode принимает код из приложения или код восстановления. Код из
+// приложения нельзя использовать повторно в том же интервале, а код
+// восстановления удаляется после использования. Использование кода
+// фиксируется условным обновлением, поэтому из параллельных запросов с одним
+// кодом проходит только один
+func (tfs *TwoFactorService) verifyCode(ctx context.Context, twoFactor *TwoFactorModel, code string) error {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	if err := tfs.countFailure(ctx, twoFactor.ReaderID); err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), codeSkew); ok {
+		used, err := tfs.twoFactorRepo.UseStep(ctx, twoFactor.ReaderID, step)
+		if err != nil {
+			logger.Errorf("error updating reader two-factor last used step: %v", err)
+			return err
+		}
+		if !used {
+			logger.Warn("two-factor code was already used")
+			return ErrInvalidTwoFactorCode
+		}
+		twoFactor.LastUsedStep = step
+	} else {
+		codeHash := hashRecoveryCode(code)
+		used, err := tfs.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.ReaderID, codeHash)
+		if err != nil {
+			logger.Errorf("error removing reader recovery code: %v", err)
+			return err
+		}
+		if !used {
+			logger.Warn("invalid two-factor code")
+			return ErrInvalidTwoFactorCode
+		}
+		twoFactor.RecoveryCodeHashes = slices.DeleteFunc(twoFactor.RecoveryCodeHashes, func(hash string) bool {
+			return hash == codeHash
+		})
+		logger.Info("recovery code used")
+	}
+
+	tfs.resetFailures(ctx, twoFactor.ReaderID)
+
+	return nil
+}
+
+// countFailure засчитывает проверку кода читателя до сравнения, чтобы
+// параллельные запросы не обходили лимит. Успешная проверка сбрасывает счетчик,
+// поэтому после maxFailures неверных кодов подряд читатель блокируется на
+// lockoutDuration, сколько бы challenge он ни открыл
+func (tfs *TwoFactorService) countFailure(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	failures, err := tfs.challengeStore.IncrFailures(ctx, readerID, tfs.lockoutDuration)
+	if err != nil {
+		return err
+	}
+	if failures > tfs.maxFailures {
+		logger.Warn("two-factor failures exceeded, reader is locked out")
+		return ErrTwoFactorLocked
+	}
+
+	return nil
+}
+
+func (tfs *TwoFactorService) resetFailures(ctx context.Context, readerID uuid.UUID) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	if err := tfs.challengeStore.ResetFailures(ctx, readerID); err != nil {
+		logger.Errorf("error resetting two-factor failures: %v", err)
+	}
+}
+
+func (tfs *TwoFactorService) getEnabled(ctx context.Context, readerID uuid.UUID) (*TwoFactorModel, error) {
+	logger := logging.FromContext(ctx, tfs.logger)
+
+	twoFactor, err := tfs.twoFactorRepo.GetByReaderID(ctx, readerID)
+	if err != nil {
+		logger.Errorf("error getting reader two-factor settings: %v", err)
+		return nil, err
+	}
+	if !twoFactor.Enabled {
+		logger.Warn("two-factor authentication is not enabled")
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	return twoFactor, nil
+}
+
+func (tfs *TwoFactorService) isEnforced(role string) bool {
+	return slices.Contains(tfs.enforcedRoles, role)
+}
+
+func generateToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// generateRecoveryCode возвращает код вида xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, 11)
+	for i, b := range raw {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		code = append(code, alphabet[int(b)%len(alphabet)])
+	}
+
+	return string(code), nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,160 @@
+package twofactor
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+const (
+	challengeKeyPrefix = "two_factor:challenge:"
+	failuresKeyPrefix  = "two_factor:failures:"
+
+	readerIDField           = "reader_id"
+	phoneNumberField        = "phone_number"
+	roleField               = "role"
+	enrollmentRequiredField = "enrollment_required"
+	attemptsField           = "attempts"
+)
+
+// failureScript увеличивает счетчик неверных кодов читателя и задает его TTL
+// только при первой ошибке, поэтому блокировка истекает через window после
+// первой неудачной попытки, а не продлевается каждой следующей
+var failureScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+
+return failures
+`)
+
+type IChallengeStore interface {
+	Save(ctx context.Context, token string, challenge *ChallengeModel, ttl time.Duration) error
+	Get(ctx context.Context, token string) (*ChallengeModel, error)
+	IncrAttempts(ctx context.Context, token string) (int, error)
+	Delete(ctx context.Context, token string) error
+	// IncrFailures засчитывает проверку кода читателя и возвращает число
+	// проверок за окно window, включая эту. Счетчик общий для всех challenge
+	// и операций читателя
+	IncrFailures(ctx context.Context, readerID uuid.UUID, window time.Duration) (int, error)
+	ResetFailures(ctx context.Context, readerID uuid.UUID) error
+}
+
+type RedisChallengeStore struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisChallengeStore(client *redis.Client, logger *logrus.Entry) IChallengeStore {
+	return &RedisChallengeStore{client: client, logger: logger}
+}
+
+func (s *RedisChallengeStore) Save(ctx context.Context, token string, challenge *ChallengeModel, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	key := challengeKeyPrefix + token
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			readerIDField, challenge.ReaderID.String(),
+			phoneNumberField, challenge.PhoneNumber,
+			roleField, challenge.Role,
+			enrollmentRequiredField, strconv.FormatBool(challenge.EnrollmentRequired),
+			attemptsField, challenge.Attempts,
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("error saving two-factor challenge: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisChallengeStore) Get(ctx context.Context, token string) (*ChallengeModel, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	values, err := s.client.HGetAll(ctx, challengeKeyPrefix+token).Result()
+	if err != nil {
+		logger.Errorf("error getting two-factor challenge: %v", err)
+		return nil, err
+	}
+
+	if values[readerIDField] == "" {
+		return nil, ErrInvalidChallenge
+	}
+
+	readerID, err := uuid.Parse(values[readerIDField])
+	if err != nil {
+		logger.Errorf("error parsing two-factor challenge reader id: %v", err)
+		return nil, err
+	}
+
+	attempts, err := strconv.Atoi(values[attemptsField])
+	if err != nil {
+		logger.Errorf("error parsing two-factor challenge attempts: %v", err)
+		return nil, err
+	}
+
+	return &ChallengeModel{
+		ReaderID:           readerID,
+		PhoneNumber:        values[phoneNumberField],
+		Role:               values[roleField],
+		EnrollmentRequired: values[enrollmentRequiredField] == "true",
+		Attempts:           attempts,
+	}, nil
+}
+
+func (s *RedisChallengeStore) IncrAttempts(ctx context.Context, token string) (int, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	attempts, err := s.client.HIncrBy(ctx, challengeKeyPrefix+token, attemptsField, 1).Result()
+	if err != nil {
+		logger.Errorf("error incrementing two-factor challenge attempts: %v", err)
+		return 0, err
+	}
+
+	return int(attempts), nil
+}
+
+func (s *RedisChallengeStore) Delete(ctx context.Context, token string) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, challengeKeyPrefix+token).Err(); err != nil {
+		logger.Errorf("error deleting two-factor challenge: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *RedisChallengeStore) IncrFailures(ctx context.Context, readerID uuid.UUID, window time.Duration) (int, error) {
+	logger := logging.FromContext(ctx, s.logger)
+
+	failures, err := failureScript.Run(ctx, s.client, []string{failuresKeyPrefix + readerID.String()},
+		window.Milliseconds()).Int()
+	if err != nil {
+		logger.Errorf("error incrementing two-factor failures: %v", err)
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+func (s *RedisChallengeStore) ResetFailures(ctx context.Context, readerID uuid.UUID) error {
+	logger := logging.FromContext(ctx, s.logger)
+
+	if err := s.client.Del(ctx, failuresKeyPrefix+readerID.String()).Err(); err != nil {
+		logger.Errorf("error resetting two-factor failures: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -6,9 +6,11 @@ migrate_up() {
     migrate -database "$POSTGRES_CREATE_DB_URL"  -path "$POSTGRES_CREATE_DB_MIGRATION_PATH" up
     migrate -database "$POSTGRES_CREATE_SCHEMA_URL" -path "$POSTGRES_CREATE_SCHEMA_MIGRATION_PATH" up
     migrate -database "$POSTGRES_FILL_DB_URL" -path "$POSTGRES_FILL_DB_MIGRATION_PATH" up
+    go run ./cmd/app migrate up
 }
 
 migrate_down() {
+    go run ./cmd/app migrate to 0
     migrate -database "$POSTGRES_FILL_DB_URL" -path "$POSTGRES_FILL_DB_MIGRATION_PATH" down
     migrate -database "$POSTGRES_CREATE_SCHEMA_URL" -path "$POSTGRES_CREATE_SCHEMA_MIGRATION_PATH" down
     migrate -database "$POSTGRES_CREATE_DB_URL"  -path "$POSTGRES_CREATE_DB_MIGRATION_PATH" down
@@ -0,0 +1,13 @@
+// Package migrations содержит версионированные миграции схемы приложения,
+// встроенные в бинарный файл. Номера миграций Postgres и Mongo совпадают:
+// если изменение не затрагивает Mongo (коллекции создаются при первой
+// записи), миграция Mongo пустая, чтобы версии схем не расходились
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql
+var Postgres embed.FS
+
+//go:embed mongo/*.json
+var Mongo embed.FS
@@ -0,0 +1,2 @@
+drop table if exists bs.reading_list_book;
+drop table if exists bs.reading_list;
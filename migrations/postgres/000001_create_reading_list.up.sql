@@ -0,0 +1,18 @@
+create table if not exists bs.reading_list
+(
+    id          uuid primary key,
+    reader_id   uuid         not null references bs.reader (id) on delete cascade,
+    name        varchar(100) not null,
+    share_token varchar(64) unique,
+    created_at  timestamp    not null
+);
+
+create unique index if not exists reading_list_reader_name_idx on bs.reading_list (reader_id, lower(name));
+
+create table if not exists bs.reading_list_book
+(
+    reading_list_id uuid    not null references bs.reading_list (id) on delete cascade,
+    book_id         uuid    not null references bs.book (id) on delete cascade,
+    position        integer not null,
+    primary key (reading_list_id, book_id)
+);
@@ -0,0 +1 @@
+delete from bs.reader where id = '00000000-0000-0000-0000-000000000000';
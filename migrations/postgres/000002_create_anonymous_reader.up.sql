@@ -0,0 +1,4 @@
+-- служебный читатель, на которого переносятся отзывы и бронирования удаленных аккаунтов
+insert into bs.reader
+values ('00000000-0000-0000-0000-000000000000', 'Удаленный читатель', '00000000000', 0, '', 'Reader')
+on conflict (id) do nothing;
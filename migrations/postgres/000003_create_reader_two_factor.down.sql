@@ -0,0 +1 @@
+drop table if exists bs.reader_two_factor;
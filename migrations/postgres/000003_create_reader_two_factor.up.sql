@@ -0,0 +1,8 @@
+create table if not exists bs.reader_two_factor
+(
+    reader_id      uuid primary key references bs.reader (id) on delete cascade,
+    secret         varchar(64) not null,
+    enabled        boolean     not null default false,
+    recovery_codes text[]      not null default '{}',
+    last_used_step bigint      not null default 0
+);
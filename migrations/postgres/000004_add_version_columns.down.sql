@@ -0,0 +1,3 @@
+alter table bs.reservation drop column if exists version;
+alter table bs.lib_card drop column if exists version;
+alter table bs.book drop column if exists version;
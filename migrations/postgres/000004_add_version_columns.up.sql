@@ -0,0 +1,4 @@
+-- версия строки для оптимистичной блокировки: увеличивается при каждом изменении
+alter table bs.book add column if not exists version bigint not null default 0;
+alter table bs.lib_card add column if not exists version bigint not null default 0;
+alter table bs.reservation add column if not exists version bigint not null default 0;
@@ -0,0 +1,3 @@
+drop index if exists bs.book_deleted_at_idx;
+
+alter table bs.book drop column if exists deleted_at;
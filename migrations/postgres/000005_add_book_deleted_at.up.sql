@@ -0,0 +1,4 @@
+-- время мягкого удаления книги; null - книга не удалена
+alter table bs.book add column if not exists deleted_at timestamptz;
+
+create index if not exists book_deleted_at_idx on bs.book (deleted_at) where deleted_at is not null;
@@ -0,0 +1,4 @@
+drop index if exists bs.book_genres_idx;
+drop index if exists bs.book_author_idx;
+
+drop table if exists bs.book_co_reservation;
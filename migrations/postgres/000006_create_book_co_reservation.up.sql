@@ -0,0 +1,16 @@
+-- таблица совместных бронирований: сколько читателей брали и book_id, и
+-- co_book_id; пересчитывается периодически, для книги хранятся лучшие пары
+create table if not exists bs.book_co_reservation
+(
+    book_id       uuid    not null references bs.book (id) on delete cascade,
+    co_book_id    uuid    not null references bs.book (id) on delete cascade,
+    readers_count integer not null,
+    primary key (book_id, co_book_id)
+);
+
+create index if not exists book_co_reservation_rank_idx on bs.book_co_reservation (book_id, readers_count desc);
+
+-- индексы поиска похожих книг по автору и общим жанрам
+create index if not exists book_author_idx on bs.book (lower(trim(author)));
+create index if not exists book_genres_idx on bs.book
+    using gin (array_remove(regexp_split_to_array(lower(trim(genre)), '\s*,\s*'), ''));
@@ -0,0 +1,3 @@
+drop index if exists bs.reader_deleted_at_idx;
+
+alter table bs.reader drop column if exists deleted_at;
@@ -0,0 +1,4 @@
+-- время мягкого удаления читателя; null - читатель не удален
+alter table bs.reader add column if not exists deleted_at timestamptz;
+
+create index if not exists reader_deleted_at_idx on bs.reader (deleted_at) where deleted_at is not null;
@@ -0,0 +1,122 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type closer struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager управляет жизненным циклом приложения: запускает HTTP-сервер и
+// фоновые задачи и останавливает их по SIGINT/SIGTERM в порядке, обратном
+// запуску: сначала сервер дожидается текущих запросов, затем отменяются
+// фоновые задачи, и только потом закрываются клиенты БД, Redis и экспортеры
+type Manager struct {
+	logger          *logrus.Entry
+	shutdownTimeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	workers sync.WaitGroup
+	closers []closer
+}
+
+func New(logger *logrus.Entry, shutdownTimeout time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{logger: logger, shutdownTimeout: shutdownTimeout, ctx: ctx, cancel: cancel}
+}
+
+// Go запускает фоновую задачу; ее контекст отменяется при остановке
+func (m *Manager) Go(name string, worker func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		worker(m.ctx)
+		m.logger.Infof("background worker %q stopped", name)
+	}()
+}
+
+// OnStop регистрирует освобождение ресурса. Ресурсы освобождаются в порядке,
+// обратном регистрации, поэтому клиент нужно регистрировать сразу после создания
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.closers = append(m.closers, closer{name: name, stop: stop})
+}
+
+// Serve обслуживает запросы до сигнала остановки или ошибки сервера, после
+// чего останавливает приложение
+func (m *Manager) Serve(server *http.Server) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return m.ServeContext(ctx, server)
+}
+
+// ServeContext обслуживает запросы до отмены ctx или ошибки сервера
+func (m *Manager) ServeContext(ctx context.Context, server *http.Server) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		m.logger.Infof("http server listening on %s", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serverErr:
+		m.logger.Errorf("error running server: %v", err)
+	case <-ctx.Done():
+		m.logger.Info("shutdown signal received")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+		m.logger.Errorf("error draining http server: %v", shutdownErr)
+		err = errors.Join(err, shutdownErr)
+	}
+
+	return errors.Join(err, m.Stop(shutdownCtx))
+}
+
+// Stop отменяет фоновые задачи, ждет их завершения и освобождает ресурсы
+func (m *Manager) Stop(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.logger.Warn("background workers did not stop in time")
+	}
+
+	var errs []error
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.stop(ctx); err != nil {
+			m.logger.Errorf("error stopping %s: %v", c.name, err)
+			errs = append(errs, err)
+			continue
+		}
+		m.logger.Infof("%s stopped", c.name)
+	}
+	m.closers = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+package lifecycle
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// RetryPolicy - экспоненциальная задержка между попытками подключения к
+// зависимостям при старте: контейнеры БД и Redis поднимаются дольше приложения
+type RetryPolicy struct {
+	Attempts     int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// Retry вызывает fn, пока она не завершится без ошибки или не кончатся попытки
+func Retry(ctx context.Context, policy RetryPolicy, logger *logrus.Entry, name string, fn func(ctx context.Context) error) error {
+	attempts := max(policy.Attempts, 1)
+	delay := policy.InitialDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			return err
+		}
+
+		logger.Warnf("%s is not available (attempt %d/%d), retrying in %s: %v", name, attempt, attempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 {
+			delay = min(delay, policy.MaxDelay)
+		}
+	}
+}
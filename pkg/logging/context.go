@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+type fieldsCtxKey struct{}
+
+// WithFields добавляет поля к логам всех, кто получит этот контекст:
+// идентификатор запроса, читателя и маршрут кладутся в контекст один раз,
+// а сервисы и репозитории берут их через FromContext
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := make(logrus.Fields, len(fields))
+	if parent, ok := ctx.Value(fieldsCtxKey{}).(logrus.Fields); ok {
+		for key, value := range parent {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, fieldsCtxKey{}, merged)
+}
+
+// FromContext возвращает логгер с полями запроса из контекста
+func FromContext(ctx context.Context, logger *logrus.Entry) *logrus.Entry {
+	fields, ok := ctx.Value(fieldsCtxKey{}).(logrus.Fields)
+	if !ok {
+		return logger
+	}
+
+	return logger.WithFields(fields)
+}
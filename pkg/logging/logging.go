@@ -2,13 +2,13 @@ package logging
 
 import (
 	"fmt"
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"io"
-	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
+	"time"
 )
 
 type writerHook struct {
@@ -36,35 +36,32 @@ func (hook *writerHook) Levels() []logrus.Level {
 	return hook.LogLevels
 }
 
-func setLogLevel(l *logrus.Logger) {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal(err)
+// SetLevel меняет уровень логирования; logger может быть любым производным
+// от созданного NewLogger, так как все они пишут через один *logrus.Logger
+func SetLevel(logger *logrus.Entry, level string) error {
+	logLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
 	}
 
-	logLevel := os.Getenv("LOG_LEVEL")
-
-	switch logLevel {
-	case "panic":
-		l.SetLevel(logrus.PanicLevel)
-	case "fatal":
-		l.SetLevel(logrus.FatalLevel)
-	case "error":
-		l.SetLevel(logrus.ErrorLevel)
-	case "warn":
-		l.SetLevel(logrus.WarnLevel)
-	case "info":
-		l.SetLevel(logrus.InfoLevel)
-	case "debug":
-		l.SetLevel(logrus.DebugLevel)
-	case "trace":
-		l.SetLevel(logrus.TraceLevel)
-	default:
-		l.Warn("Invalid LOG_LEVEL, defaulting to Info level")
-		l.SetLevel(logrus.InfoLevel)
-	}
+	logger.Logger.SetLevel(logLevel)
+
+	return nil
+}
+
+// Options задает уровень логирования (по умолчанию info) и куда пишутся
+// логи: в файл Dir/all.log с ротацией по размеру (MaxSize, байты) и времени
+// (RotationInterval) и, если включен Stdout, в стандартный вывод для docker logs
+type Options struct {
+	Level            string
+	Dir              string
+	Stdout           bool
+	MaxSize          int64
+	RotationInterval time.Duration
+	MaxBackups       int
 }
 
-func NewLogger() (*logrus.Entry, error) {
+func NewLogger(opts Options) (*logrus.Entry, error) {
 	l := logrus.New()
 	l.SetReportCaller(true)
 	l.SetFormatter(&logrus.JSONFormatter{
@@ -76,12 +73,12 @@ func NewLogger() (*logrus.Entry, error) {
 		PrettyPrint: false,
 	})
 
-	err := os.MkdirAll("logs", 0755)
-	if err != nil {
-		return nil, err
+	dir := opts.Dir
+	if dir == "" {
+		dir = "logs"
 	}
 
-	allFile, err := os.OpenFile("logs/all.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
+	allFile, err := NewRotatingFile(filepath.Join(dir, "all.log"), opts.MaxSize, opts.RotationInterval, opts.MaxBackups)
 	if err != nil {
 		return nil, err
 	}
@@ -89,15 +86,23 @@ func NewLogger() (*logrus.Entry, error) {
 	l.SetOutput(io.Discard)
 
 	writer := []io.Writer{allFile}
+	if opts.Stdout {
+		writer = append(writer, os.Stdout)
+	}
 
 	l.AddHook(&writerHook{
 		Writer:    writer,
 		LogLevels: logrus.AllLevels,
 	})
 
-	setLogLevel(l)
+	logger := logrus.NewEntry(l)
+	if opts.Level != "" {
+		if err = SetLevel(logger, opts.Level); err != nil {
+			return nil, err
+		}
+	}
 
-	return logrus.NewEntry(l), nil
+	return logger, nil
 }
 
 func GetLoggerForTests() (logger *logrus.Entry) {
@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405"
+
+var ErrLogTooLarge = errors.New("log entry is larger than max file size")
+
+// RotatingFile - файл лога, который переименовывается в резервную копию
+// all-<время>.log при превышении размера или по истечении интервала;
+// лишние резервные копии удаляются
+type RotatingFile struct {
+	mu sync.Mutex
+
+	filename   string
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewRotatingFile открывает файл лога. Нулевые maxSize и interval отключают
+// ротацию по размеру и по времени, нулевой maxBackups хранит все копии
+func NewRotatingFile(filename string, maxSize int64, interval time.Duration, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		filename:   filename,
+		maxSize:    maxSize,
+		interval:   interval,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && int64(len(p)) > rf.maxSize {
+		return 0, fmt.Errorf("%w: %d > %d", ErrLogTooLarge, len(p), rf.maxSize)
+	}
+
+	if rf.needsRotation(int64(len(p))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+
+	return n, err
+}
+
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	return rf.file.Close()
+}
+
+func (rf *RotatingFile) needsRotation(next int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.maxSize > 0 && rf.size+next > rf.maxSize {
+		return true
+	}
+
+	return rf.interval > 0 && rf.now().Sub(rf.openedAt) >= rf.interval
+}
+
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.filename), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(rf.filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+	// после перезапуска интервал отсчитывается от последнего изменения файла
+	if rf.size > 0 && info.ModTime().Before(rf.openedAt) {
+		rf.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(rf.filename, rf.backupName()); err != nil {
+		return err
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	return rf.removeOldBackups()
+}
+
+func (rf *RotatingFile) backupName() string {
+	ext := filepath.Ext(rf.filename)
+	prefix := strings.TrimSuffix(rf.filename, ext)
+	name := fmt.Sprintf("%s-%s%s", prefix, rf.now().Format(backupTimeFormat), ext)
+
+	// несколько ротаций в одну секунду не должны затирать друг друга
+	for i := 1; fileExists(name); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", prefix, rf.now().Format(backupTimeFormat), i, ext)
+	}
+
+	return name
+}
+
+func (rf *RotatingFile) removeOldBackups() error {
+	if rf.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := rf.Backups()
+	if err != nil {
+		return err
+	}
+
+	for len(backups) > rf.maxBackups {
+		if err = os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// Backups возвращает резервные копии от старых к новым
+func (rf *RotatingFile) Backups() ([]string, error) {
+	ext := filepath.Ext(rf.filename)
+	backups, err := filepath.Glob(strings.TrimSuffix(rf.filename, ext) + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return modTime(backups[i]).Before(modTime(backups[j])) ||
+			modTime(backups[i]).Equal(modTime(backups[j])) && backups[i] < backups[j]
+	})
+
+	return backups, nil
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+func modTime(name string) time.Time {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
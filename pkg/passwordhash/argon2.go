@@ -0,0 +1,106 @@
+package passwordhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const (
+	DefaultArgon2Memory      = 64 * 1024
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+func (p Argon2Params) withDefaults() Argon2Params {
+	if p.Memory == 0 {
+		p.Memory = DefaultArgon2Memory
+	}
+	if p.Iterations == 0 {
+		p.Iterations = DefaultArgon2Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = DefaultArgon2Parallelism
+	}
+
+	return p
+}
+
+// hashArgon2id возвращает хэш в формате PHC: $argon2id$v=19$m=...,t=...,p=...$соль$хэш
+func hashArgon2id(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, argon2KeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func compareArgon2id(hashedPassword, password string) error {
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+func parseArgon2idParams(hashedPassword string) (Argon2Params, error) {
+	params, _, _, err := decodeArgon2id(hashedPassword)
+
+	return params, err
+}
+
+func decodeArgon2id(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	return params, salt, key, nil
+}
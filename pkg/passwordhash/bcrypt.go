@@ -0,0 +1,30 @@
+package passwordhash
+
+import (
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const DefaultBcryptCost = 12
+
+func hashBcrypt(password string, cost int) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+func compareBcrypt(hashedPassword, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatchedPassword
+	}
+
+	return err
+}
+
+func bcryptCost(hashedPassword string) (int, error) {
+	return bcrypt.Cost([]byte(hashedPassword))
+}
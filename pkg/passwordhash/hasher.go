@@ -0,0 +1,99 @@
+package passwordhash
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikitalystsev/BookSmart-services/pkg/hash"
+	"strings"
+)
+
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+var (
+	ErrMismatchedPassword = errors.New("password does not match")
+	ErrUnknownAlgorithm   = errors.New("unknown password hashing algorithm")
+	ErrInvalidHash        = errors.New("invalid password hash")
+)
+
+type Params struct {
+	Algorithm  string
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+// Hasher хэширует пароли в самоописываемом формате с солью на каждого
+// пользователя (argon2id в формате PHC или bcrypt). Хэши старого формата с
+// общей солью проверяет прежний хэшер. Нужно ли пересчитать хэш по текущей
+// схеме, сообщает NeedsRehash
+type Hasher struct {
+	params Params
+	legacy hash.IPasswordHasher
+}
+
+func NewHasher(params Params, legacy hash.IPasswordHasher) (*Hasher, error) {
+	switch params.Algorithm {
+	case Argon2id, "":
+		params.Algorithm = Argon2id
+		params.Argon2 = params.Argon2.withDefaults()
+	case Bcrypt:
+		if params.BcryptCost == 0 {
+			params.BcryptCost = DefaultBcryptCost
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, params.Algorithm)
+	}
+
+	return &Hasher{params: params, legacy: legacy}, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == Bcrypt {
+		return hashBcrypt(password, h.params.BcryptCost)
+	}
+
+	return hashArgon2id(password, h.params.Argon2)
+}
+
+func (h *Hasher) Compare(hashedPassword, password string) error {
+	switch {
+	case isArgon2id(hashedPassword):
+		return compareArgon2id(hashedPassword, password)
+	case isBcrypt(hashedPassword):
+		return compareBcrypt(hashedPassword, password)
+	default:
+		return h.legacy.Compare(hashedPassword, password)
+	}
+}
+
+// NeedsRehash сообщает, что хэш получен не по текущей схеме или с другими параметрами
+func (h *Hasher) NeedsRehash(hashedPassword string) bool {
+	switch {
+	case isArgon2id(hashedPassword):
+		if h.params.Algorithm != Argon2id {
+			return true
+		}
+		params, err := parseArgon2idParams(hashedPassword)
+		return err != nil || params != h.params.Argon2
+	case isBcrypt(hashedPassword):
+		if h.params.Algorithm != Bcrypt {
+			return true
+		}
+		cost, err := bcryptCost(hashedPassword)
+		return err != nil || cost != h.params.BcryptCost
+	default:
+		return true
+	}
+}
+
+func isArgon2id(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$argon2id$")
+}
+
+func isBcrypt(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
+}
@@ -0,0 +1,80 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	ConsoleSenderType = "console"
+	FileSenderType    = "file"
+)
+
+var ErrUnknownSenderType = errors.New("unknown sms sender type")
+
+// ISender отправляет SMS-сообщения. Реализация для реального SMS-шлюза
+// подключается через этот интерфейс
+type ISender interface {
+	Send(ctx context.Context, phoneNumber, message string) error
+}
+
+func NewSender(senderType, filePath string, logger *logrus.Entry) (ISender, error) {
+	switch senderType {
+	case ConsoleSenderType, "":
+		return NewConsoleSender(logger), nil
+	case FileSenderType:
+		return NewFileSender(filePath)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSenderType, senderType)
+	}
+}
+
+// ConsoleSender пишет сообщения в лог, используется при локальной разработке
+type ConsoleSender struct {
+	logger *logrus.Entry
+}
+
+func NewConsoleSender(logger *logrus.Entry) *ConsoleSender {
+	return &ConsoleSender{logger: logger}
+}
+
+func (s *ConsoleSender) Send(_ context.Context, phoneNumber, message string) error {
+	s.logger.Infof("sms to %s: %s", phoneNumber, message)
+
+	return nil
+}
+
+// FileSender дописывает сообщения в файл, используется при локальной разработке и в тестах
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSender(path string) (*FileSender, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileSender{path: path}, nil
+}
+
+func (s *FileSender) Send(_ context.Context, phoneNumber, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phoneNumber, message)
+
+	return err
+}
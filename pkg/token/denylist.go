@@ -0,0 +1,106 @@
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+const (
+	tokenDenylistKeyPrefix  = "access_denylist:token:"
+	readerDenylistKeyPrefix = "access_denylist:reader:"
+)
+
+type IDenylist interface {
+	// Add запрещает токен с идентификатором tokenID до истечения его срока действия
+	Add(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// AddReader запрещает все токены читателя, выданные до текущего момента.
+	// Момент отзыва хранится в миллисекундах, токен, выпущенный позже, принимается
+	AddReader(ctx context.Context, readerID uuid.UUID, ttl time.Duration) error
+	IsDenied(ctx context.Context, tokenID string, readerID string, issuedAt time.Time) (bool, error)
+}
+
+type RedisDenylist struct {
+	client *redis.Client
+	logger *logrus.Entry
+}
+
+func NewRedisDenylist(client *redis.Client, logger *logrus.Entry) *RedisDenylist {
+	return &RedisDenylist{client: client, logger: logger}
+}
+
+func (d *RedisDenylist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	logger := logging.FromContext(ctx, d.logger)
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := d.client.Set(ctx, tokenDenylistKeyPrefix+tokenID, 1, ttl).Err(); err != nil {
+		logger.Errorf("error adding access token to denylist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddPipelined добавляет запрет токена в pipeline вызывающего, чтобы запрет
+// применился в одной транзакции с его изменениями
+func AddPipelined(ctx context.Context, pipe redis.Pipeliner, tokenID string, expiresAt time.Time) {
+	if ttl := time.Until(expiresAt); ttl > 0 {
+		pipe.Set(ctx, tokenDenylistKeyPrefix+tokenID, 1, ttl)
+	}
+}
+
+func (d *RedisDenylist) AddReader(ctx context.Context, readerID uuid.UUID, ttl time.Duration) error {
+	logger := logging.FromContext(ctx, d.logger)
+
+	err := d.client.Set(ctx, readerDenylistKeyPrefix+readerID.String(), time.Now().UnixMilli(), ttl).Err()
+	if err != nil {
+		logger.Errorf("error adding reader to access token denylist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (d *RedisDenylist) IsDenied(ctx context.Context, tokenID string, readerID string, issuedAt time.Time) (bool, error) {
+	logger := logging.FromContext(ctx, d.logger)
+
+	values, err := d.client.MGet(ctx, tokenDenylistKeyPrefix+tokenID, readerDenylistKeyPrefix+readerID).Result()
+	if err != nil {
+		logger.Errorf("error checking access token denylist: %v", err)
+		return false, err
+	}
+
+	if values[0] != nil {
+		return true, nil
+	}
+
+	revokedAt, ok := values[1].(string)
+	if !ok {
+		return false, nil
+	}
+
+	milliseconds, err := strconv.ParseInt(revokedAt, 10, 64)
+	if err != nil {
+		return false, errors.New("invalid reader denylist value")
+	}
+
+	return issuedAt.Before(time.UnixMilli(milliseconds)), nil
+}
+
+// legacyTokenID - идентификатор для токенов старого формата без jti
+func legacyTokenID(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+
+	return hex.EncodeToString(sum[:])
+}
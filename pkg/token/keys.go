@@ -0,0 +1,114 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"math/big"
+	"os"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown signing algorithm")
+	ErrEmptySigningKey  = errors.New("empty signing key")
+)
+
+// KeyConfig описывает один ключ подписи. Для HS256 секрет берется из
+// переменной окружения SecretEnv, для RS256 и EdDSA - закрытый ключ в PEM из PrivateKeyFile
+type KeyConfig struct {
+	ID             string
+	Algorithm      string
+	SecretEnv      string
+	PrivateKeyFile string
+}
+
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+func NewHMACKey(ID string, secret []byte) (*Key, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySigningKey
+	}
+
+	return &Key{ID: ID, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+func LoadKey(cfg KeyConfig) (*Key, error) {
+	switch cfg.Algorithm {
+	case HS256, "":
+		return NewHMACKey(cfg.ID, []byte(os.Getenv(cfg.SecretEnv)))
+	case RS256:
+		pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: cfg.ID, Method: jwt.SigningMethodRS256, signKey: privateKey, verifyKey: &privateKey.PublicKey}, nil
+	case EdDSA:
+		pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		signKey := privateKey.(ed25519.PrivateKey)
+		return &Key{ID: cfg.ID, Method: jwt.SigningMethodEdDSA, signKey: signKey, verifyKey: signKey.Public()}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, cfg.Algorithm)
+	}
+}
+
+// JWK - открытый ключ в формате JSON Web Key. Симметричные ключи не публикуются
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func (k *Key) JWK() (*JWK, bool) {
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: RS256,
+			Kid: k.ID,
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: EdDSA,
+			Kid: k.ID,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,252 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nikitalystsev/BookSmart-services/pkg/auth"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	refreshTokenBytes = 32
+	denylistTimeout   = time.Second
+)
+
+var (
+	ErrUnknownKeyID  = errors.New("unknown signing key id")
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrRevokedToken  = errors.New("token has been revoked")
+	ErrNoSigningKeys = errors.New("no signing keys configured")
+)
+
+// claims дополняет стандартные поля временем выпуска в миллисекундах: iat
+// хранится с точностью до секунды, и без IssuedAtMs токен, выпущенный в ту же
+// секунду, что и отзыв всех токенов читателя, нельзя было бы отличить от
+// отозванного
+type claims struct {
+	jwt.RegisteredClaims
+	Role       string `json:"role"`
+	IssuedAtMs int64  `json:"iat_ms"`
+}
+
+// Manager выпускает и проверяет access-токены, подписанные одним из
+// нескольких ключей. Ключ выбирается по заголовку kid, поэтому при ротации
+// новый ключ становится активным, а старые продолжают проверять выданные
+// ранее токены до их истечения. Токены без kid проверяет прежний
+// менеджер токенов до момента legacyUntil; после него такие токены
+// отклоняются, и утечка прежнего общего ключа перестает быть опасной
+type Manager struct {
+	activeKey   *Key
+	keys        map[string]*Key
+	legacy      auth.ITokenManager
+	legacyUntil time.Time
+	denylist    IDenylist
+	accessTTL   time.Duration
+	logger      *logrus.Entry
+}
+
+func NewManager(
+	activeKeyID string,
+	keys []*Key,
+	legacy auth.ITokenManager,
+	legacyUntil time.Time,
+	denylist IDenylist,
+	accessTTL time.Duration,
+	logger *logrus.Entry,
+) (*Manager, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+
+	m := &Manager{
+		keys:        make(map[string]*Key, len(keys)),
+		legacy:      legacy,
+		legacyUntil: legacyUntil,
+		denylist:    denylist,
+		accessTTL:   accessTTL,
+		logger:      logger,
+	}
+	for _, key := range keys {
+		m.keys[key.ID] = key
+	}
+
+	if activeKeyID == "" {
+		activeKeyID = keys[0].ID
+	}
+	activeKey, ok := m.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, activeKeyID)
+	}
+	m.activeKey = activeKey
+
+	return m, nil
+}
+
+func (m *Manager) NewJWT(userID uuid.UUID, role string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(m.activeKey.Method, &claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role:       role,
+		IssuedAtMs: now.UnixMilli(),
+	})
+	token.Header["kid"] = m.activeKey.ID
+
+	return token.SignedString(m.activeKey.signKey)
+}
+
+func (m *Manager) Parse(accessToken string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), denylistTimeout)
+	defer cancel()
+
+	parsed, err := m.parse(accessToken)
+	if errors.Is(err, errNoKeyID) {
+		return m.parseLegacy(ctx, accessToken)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	denied, err := m.denylist.IsDenied(ctx, parsed.ID, parsed.Subject, parsed.issuedAt())
+	if err != nil {
+		return "", "", err
+	}
+	if denied {
+		return "", "", ErrRevokedToken
+	}
+
+	return parsed.Subject, parsed.Role, nil
+}
+
+func (m *Manager) NewRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Revoke запрещает access-токен до истечения его срока действия
+func (m *Manager) Revoke(ctx context.Context, accessToken string) error {
+	tokenID, expiresAt, err := m.TokenID(accessToken)
+	if err != nil {
+		return err
+	}
+
+	return m.RevokeID(ctx, tokenID, expiresAt)
+}
+
+// TokenID возвращает идентификатор access-токена и срок его действия, по
+// которым токен можно запретить позже, не храня сам токен
+func (m *Manager) TokenID(accessToken string) (string, time.Time, error) {
+	parsed, err := m.parse(accessToken)
+	if errors.Is(err, errNoKeyID) {
+		return legacyTokenID(accessToken), time.Now().Add(m.accessTTL), nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return parsed.ID, parsed.ExpiresAt.Time, nil
+}
+
+// RevokeID запрещает access-токен по идентификатору, полученному от TokenID
+func (m *Manager) RevokeID(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	return m.denylist.Add(ctx, tokenID, expiresAt)
+}
+
+// RevokeReader запрещает все access-токены читателя, выданные до текущего момента
+func (m *Manager) RevokeReader(ctx context.Context, readerID uuid.UUID) error {
+	return m.denylist.AddReader(ctx, readerID, m.accessTTL)
+}
+
+// JWKS возвращает открытые ключи для проверки токенов сторонними сервисами
+func (m *Manager) JWKS() []*JWK {
+	jwks := make([]*JWK, 0, len(m.keys))
+	for _, key := range m.keys {
+		if jwk, ok := key.JWK(); ok {
+			jwks = append(jwks, jwk)
+		}
+	}
+
+	return jwks
+}
+
+var errNoKeyID = errors.New("token has no kid")
+
+func (m *Manager) parse(accessToken string) (*claims, error) {
+	var parsed claims
+
+	_, err := jwt.ParseWithClaims(accessToken, &parsed, func(token *jwt.Token) (any, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, errNoKeyID
+		}
+
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+
+		return key.verifyKey, nil
+	})
+	if errors.Is(err, errNoKeyID) {
+		return nil, errNoKeyID
+	}
+	if err != nil {
+		m.logger.Warnf("error parsing access token: %v", err)
+		return nil, err
+	}
+
+	if parsed.ID == "" || parsed.IssuedAt == nil || parsed.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &parsed, nil
+}
+
+func (m *Manager) parseLegacy(ctx context.Context, accessToken string) (string, string, error) {
+	if !time.Now().Before(m.legacyUntil) {
+		return "", "", ErrInvalidToken
+	}
+
+	readerID, role, err := m.legacy.Parse(accessToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	denied, err := m.denylist.IsDenied(ctx, legacyTokenID(accessToken), readerID, time.Time{})
+	if err != nil {
+		return "", "", err
+	}
+	if denied {
+		return "", "", ErrRevokedToken
+	}
+
+	return readerID, role, nil
+}
+
+// issuedAt возвращает время выпуска с точностью до миллисекунды
+func (c *claims) issuedAt() time.Time {
+	if c.IssuedAtMs == 0 {
+		return c.IssuedAt.Time
+	}
+
+	return time.UnixMilli(c.IssuedAtMs)
+}